@@ -1,50 +1,176 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
+	"github.com/deibys/sintronia/internal/auth"
+	"github.com/deibys/sintronia/internal/config"
 	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/httpclient"
+	"github.com/deibys/sintronia/internal/middleware"
+	"github.com/deibys/sintronia/internal/migrate"
 	"github.com/deibys/sintronia/internal/routes"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv(config.FileEnvVar), "archivo de configuración YAML")
+	printConfig := flag.Bool("print-config", false, "mostrar la configuración efectiva (sin secretos) y salir")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		os.Exit(1)
+	}
+
+	if *printConfig {
+		out, err := cfg.YAML()
+		if err != nil {
+			log.Printf("❌ %v", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+
+	// Subcomando de migraciones: sintronia-api [--config <ruta>] migrate status|up|down [n]|redo|baseline v
+	if flag.NArg() > 0 && flag.Arg(0) == "migrate" {
+		os.Exit(runMigrate(cfg, flag.Args()[1:]))
+	}
+
+	if err := serve(cfg); err != nil {
+		log.Printf("❌ %v", err)
+		os.Exit(1)
+	}
+}
+
+// serve levanta la API y bloquea hasta recibir SIGINT o SIGTERM. Al apagar
+// deja de aceptar conexiones, espera las solicitudes en curso hasta
+// server.shutdown_timeout_seconds, cancela las que sigan, espera a que sus
+// handlers terminen y recién entonces cierra la base de datos.
+func serve(cfg *config.Config) error {
 	fmt.Println("🌱 Iniciando Sintropia API...")
 
+	if err := auth.Configure(cfg.Auth); err != nil {
+		return fmt.Errorf("error configurando autenticación: %w", err)
+	}
+
 	// Verificar que PostgreSQL esté disponible antes de continuar
 	fmt.Println("🔍 Verificando PostgreSQL...")
 
-	// Inicializar base de datos
-	if err := db.InitDatabase(); err != nil {
+	// Sin base de datos la API solo arranca si se pidió explícitamente (DB_REQUIRED=false)
+	degraded := false
+	if err := db.InitDatabase(cfg.Database); err != nil {
+		if cfg.Database.Required {
+			return fmt.Errorf("error inicializando base de datos: %w", err)
+		}
 		log.Printf("❌ Error inicializando base de datos: %v", err)
-		log.Println("⚠️ Continuando sin base de datos (modo fallback)")
-
-		// Opcional: Continuar sin DB para testing
-		// En producción, esto debería ser fatal
-		// log.Fatalf("❌ Error inicializando base de datos: %v", err)
+		log.Println("⚠️ MODO DEGRADADO: la API arranca sin base de datos (DB_REQUIRED=false); los endpoints que la usan responden 503")
+		degraded = true
 	}
-
-	// Configurar cierre graceful de la base de datos
 	defer func() {
 		if err := db.CloseDatabase(); err != nil {
 			log.Printf("⚠️ Error cerrando base de datos: %v", err)
 		}
 	}()
 
-	// Obtener puerto del entorno o usar 3000 por defecto
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Contexto base de todas las solicitudes: se cancela si el drenado supera el
+	// plazo, para que las tareas largas (p. ej. importaciones) se detengan antes
+	// de cerrar la base de datos
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	// Cuenta los handlers en curso: Close no los espera
+	inFlight := &middleware.InFlight{}
+
+	port := strconv.Itoa(cfg.Server.Port)
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           inFlight.Handler(routes.NewRouter(cfg)),
+		ReadHeaderTimeout: cfg.Server.ReadTimeout(),
+		ReadTimeout:       cfg.Server.ReadTimeout(),
+		WriteTimeout:      cfg.Server.WriteTimeout(),
+		IdleTimeout:       cfg.Server.IdleTimeout(),
+		BaseContext:       func(net.Listener) context.Context { return requestsCtx },
 	}
 
-	r := routes.NewRouter()
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
 
 	fmt.Printf("🚀 Servidor corriendo en puerto %s\n", port)
 	fmt.Printf("📡 API disponible en: http://localhost:%s/api/v1\n", port)
 	fmt.Printf("🔍 Health check: http://localhost:%s/api/v1/health\n", port)
-	fmt.Printf("🗄️ Base de datos: PostgreSQL conectada\n")
+	if degraded {
+		fmt.Printf("🗄️ Base de datos: no disponible (modo degradado)\n")
+	} else {
+		fmt.Printf("🗄️ Base de datos: PostgreSQL conectada\n")
+	}
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("error en el servidor HTTP: %w", err)
+	case <-ctx.Done():
+	}
+	stop() // Una segunda señal termina el proceso sin esperar
+
+	log.Printf("🛑 Apagando servidor (plazo: %v)...", cfg.Server.ShutdownTimeout())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout())
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️ Quedaron solicitudes sin terminar, se cancelan: %v", err)
+		cancelRequests()
+		_ = server.Close()
+
+		// Los handlers cancelados pueden seguir usando la base de datos hasta
+		// notar la cancelación: se espera a que terminen antes de cerrarla. Una
+		// segunda señal termina el proceso si alguno no responde.
+		if n := inFlight.Count(); n > 0 {
+			log.Printf("⏳ Esperando a que terminen %d solicitudes canceladas...", n)
+		}
+		inFlight.Wait(context.Background())
+	}
+
+	// Detener el trabajo en segundo plano: conexiones salientes a las integraciones
+	httpclient.Shared().CloseIdleConnections()
+
+	log.Println("✅ Servidor detenido")
+	return nil
+}
+
+// runMigrate ejecuta el subcomando migrate y devuelve el código de salida
+func runMigrate(cfg *config.Config, args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Levantamos el servidor
-	r.Run(":" + port)
+	if err := db.Connect(cfg.Database); err != nil {
+		log.Printf("❌ %v", err)
+		return 1
+	}
+	defer db.CloseDatabase()
+
+	migrator, err := db.NewMigrator()
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return 1
+	}
+	if err := migrate.Run(ctx, migrator, args, os.Stdout); err != nil {
+		log.Printf("❌ %v", err)
+		return 1
+	}
+	return 0
 }
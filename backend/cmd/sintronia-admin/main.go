@@ -0,0 +1,172 @@
+// sintronia-admin reúne las tareas de operación de Sintropia (migraciones,
+// datos de ejemplo, usuarios, catálogo de especies y mantenimiento) para que
+// no hagan falta accesos directos con psql. Usa la misma configuración que la
+// API: el archivo de --config o SINTRONIA_CONFIG y las variables de entorno.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/deibys/sintronia/internal/config"
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/migrate"
+)
+
+// Autor con el que se registran los cambios hechos desde la CLI
+const cliActor = "sintronia-admin"
+
+const usage = `Uso: sintronia-admin [--config <ruta>] <comando> [opciones]
+
+Comandos:
+  migrate <status|up|down [n]|redo>   Migraciones del esquema
+  check-db                            Verifica la conexión y las migraciones pendientes
+  seed --owner <email>                Carga los datos de ejemplo en la organización "demo"
+  user create --email <email> --name <nombre> [--password <clave>] [--admin]
+  user reset-password --email <email> [--password <clave>]
+  species export [--file <ruta>] [--org <id>]
+  species import --file <ruta> [--org <id>] [--update] [--dry-run]
+  purge --days <n> [--dry-run]        Borra definitivamente lo eliminado hace más de n días
+
+Sin --config se usa el archivo de SINTRONIA_CONFIG, si está definida.
+Sin --password se genera una contraseña aleatoria y se muestra una sola vez.`
+
+// errUsage indica argumentos inválidos: se muestra la ayuda del comando
+var errUsage = errors.New("argumentos inválidos")
+
+// cfg es la configuración cargada al arrancar
+var cfg *config.Config
+
+func main() {
+	log.SetFlags(0)
+
+	fs := flag.NewFlagSet("sintronia-admin", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	configPath := fs.String("config", os.Getenv(config.FileEnvVar), "archivo de configuración YAML")
+	if err := fs.Parse(os.Args[1:]); err != nil || fs.NArg() == 0 {
+		if err != nil && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "❌ %v\n\n", err)
+		}
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	loaded, err := config.Load(*configPath)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		os.Exit(1)
+	}
+	cfg = loaded
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = run(ctx, fs.Arg(0), fs.Args()[1:])
+	stop()
+
+	if err := db.CloseDatabase(); err != nil {
+		log.Printf("⚠️ Error cerrando base de datos: %v", err)
+	}
+
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "❌ %v\n\n%s\n", err, usage)
+		os.Exit(2)
+	case err != nil:
+		log.Printf("❌ %v", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, command string, args []string) error {
+	switch command {
+	case "migrate":
+		return runMigrate(ctx, args)
+	case "check-db":
+		return runCheckDB(ctx)
+	case "seed":
+		return runSeed(ctx, args)
+	case "user":
+		return runUser(args)
+	case "species":
+		return runSpecies(ctx, args)
+	case "purge":
+		return runPurge(args)
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	}
+	return fmt.Errorf("%w: comando desconocido %q", errUsage, command)
+}
+
+// runMigrate ejecuta el subcomando migrate sin aplicar migraciones al conectar
+func runMigrate(ctx context.Context, args []string) error {
+	if err := db.Connect(cfg.Database); err != nil {
+		return err
+	}
+
+	migrator, err := db.NewMigrator()
+	if err != nil {
+		return err
+	}
+	return migrate.Run(ctx, migrator, args, os.Stdout)
+}
+
+// runCheckDB verifica que PostgreSQL responda e informa las migraciones pendientes
+func runCheckDB(ctx context.Context) error {
+	if err := db.Connect(cfg.Database); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	if err := db.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("PostgreSQL no responde: %w", err)
+	}
+	fmt.Printf("✅ PostgreSQL responde (%v)\n", time.Since(start).Round(time.Millisecond))
+
+	migrator, err := db.NewMigrator()
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("error consultando migraciones: %w", err)
+	}
+	if pending > 0 {
+		fmt.Printf("⚠️ Hay %d migraciones pendientes: ejecuta \"sintronia-admin migrate up\"\n", pending)
+		return nil
+	}
+	fmt.Println("✅ El esquema está actualizado")
+	return nil
+}
+
+// openDatabase conecta y prepara la base de datos como la API (migraciones,
+// filtros de organización y auditoría) antes de usar los repositorios
+func openDatabase() error {
+	if err := db.InitDatabase(cfg.Database); err != nil {
+		return fmt.Errorf("error inicializando base de datos: %w", err)
+	}
+	return nil
+}
+
+// parseFlags interpreta las opciones de un subcomando. Los errores y los
+// argumentos sobrantes se informan como errUsage.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(io.Discard) // La ayuda completa la muestra main
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return errUsage
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("%w: argumentos inesperados %v", errUsage, fs.Args())
+	}
+	return nil
+}
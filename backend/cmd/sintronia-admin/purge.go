@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/deibys/sintronia/internal/repositories"
+)
+
+// runPurge borra definitivamente los registros eliminados (soft delete) hace
+// más de --days días
+func runPurge(args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	days := fs.Int("days", 0, "antigüedad mínima de la eliminación, en días")
+	dryRun := fs.Bool("dry-run", false, "contar los registros sin borrarlos")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *days < 1 {
+		return fmt.Errorf("%w: --days debe ser un entero positivo", errUsage)
+	}
+
+	if err := openDatabase(); err != nil {
+		return err
+	}
+
+	before := time.Now().UTC().AddDate(0, 0, -*days)
+	counts, err := repositories.NewMaintenanceRepository().PurgeDeleted(before, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLA\tREGISTROS")
+	var total int64
+	for _, c := range counts {
+		fmt.Fprintf(w, "%s\t%d\n", c.Table, c.Rows)
+		total += c.Rows
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Printf("\nℹ️ Dry-run: se borrarían %d registros eliminados antes del %s\n", total, before.Format("2006-01-02"))
+	} else {
+		fmt.Printf("\n🧹 %d registros eliminados antes del %s borrados definitivamente\n", total, before.Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// Organización en la que se cargan los datos de ejemplo
+const (
+	demoOrganizationName = "Demo"
+	demoOrganizationSlug = "demo"
+)
+
+// demoSpecies son las especies de ejemplo del catálogo global
+var demoSpecies = []struct {
+	CommonName      string
+	ScientificName  string
+	Stratum         string
+	SuccessionStage string
+	Functions       []string // La primera es la principal
+}{
+	{"Aguacate Hass", "Persea americana", models.StratumHigh, models.SuccessionSecondary, []string{models.FunctionFood}},
+	{"Frijol Caupí", "Vigna unguiculata", models.StratumLow, models.SuccessionPioneer, []string{models.FunctionNitrogenFixer}},
+	{"Bambú Guadua", "Guadua angustifolia", models.StratumHigh, models.SuccessionPioneer, []string{models.FunctionTimber}},
+	{"Plátano Dominico", "Musa acuminata", models.StratumMedium, models.SuccessionPioneer, []string{models.FunctionFood}},
+	{"Moringa", "Moringa oleifera", models.StratumMedium, models.SuccessionPioneer, []string{models.FunctionMedicinal}},
+	{"Leucaena", "Leucaena leucocephala", models.StratumMedium, models.SuccessionPioneer,
+		[]string{models.FunctionNitrogenFixer, models.FunctionBiomassProduction, models.FunctionWindbreak}},
+}
+
+// runSeed carga los datos de ejemplo: las especies en el catálogo global y los
+// sitios, plantaciones, parcelas, instancias y plantilla en la organización
+// "demo", con owner como owner. Lo que ya existe (especies por nombre
+// científico, la organización por slug, los sitios por nombre) se omite, así
+// que se puede volver a ejecutar.
+func runSeed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	ownerEmail := fs.String("owner", "", "email del usuario que queda como owner de la organización demo")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *ownerEmail == "" {
+		return fmt.Errorf("%w: --owner es requerido", errUsage)
+	}
+
+	if err := openDatabase(); err != nil {
+		return err
+	}
+
+	owner, err := repositories.NewUserRepository().GetByEmail(*ownerEmail)
+	if err != nil {
+		return err
+	}
+
+	species, err := seedSpecies(ctx)
+	if err != nil {
+		return err
+	}
+
+	orgs := repositories.NewOrganizationRepository().WithContext(ctx)
+	org, err := orgs.GetBySlug(demoOrganizationSlug)
+	switch {
+	case err == nil:
+		fmt.Printf("ℹ️ La organización demo ya existe (ID: %d)\n", org.ID)
+	case errors.Is(err, repositories.ErrOrganizationNotFound):
+		org = &models.Organization{Name: demoOrganizationName, Slug: demoOrganizationSlug}
+		if err := orgs.Create(org, owner.ID); err != nil {
+			return err
+		}
+		fmt.Printf("🏢 Organización %q creada (ID: %d)\n", org.Name, org.ID)
+	default:
+		return err
+	}
+
+	if err := seedSites(ctx, tenant.Organization(org.ID), owner.ID, species); err != nil {
+		return err
+	}
+
+	fmt.Println("🌱 Datos de ejemplo cargados")
+	return nil
+}
+
+// seedSpecies crea las especies de ejemplo que falten en el catálogo global y
+// devuelve todas por nombre común
+func seedSpecies(ctx context.Context) (map[string]*models.PlantSpecies, error) {
+	plants := repositories.NewPlantRepository().WithContext(ctx).ForTenant(tenant.All())
+
+	byName := make(map[string]*models.PlantSpecies, len(demoSpecies))
+	for _, demo := range demoSpecies {
+		plant, err := plants.GetByScientificName(demo.ScientificName)
+		if err == nil {
+			fmt.Printf("ℹ️ La especie %s ya existe (ID: %d)\n", demo.ScientificName, plant.ID)
+			byName[demo.CommonName] = plant
+			continue
+		}
+		if !errors.Is(err, repositories.ErrSpeciesNotFound) {
+			return nil, err
+		}
+
+		plant = &models.PlantSpecies{
+			CommonName:      demo.CommonName,
+			ScientificName:  demo.ScientificName,
+			Stratum:         demo.Stratum,
+			SuccessionStage: demo.SuccessionStage,
+		}
+		plant.SetFunctions(demo.Functions, "")
+		if err := plant.Validate(); err != nil {
+			return nil, fmt.Errorf("especie %s: %w", demo.ScientificName, err)
+		}
+		if err := plants.Create(plant); err != nil {
+			return nil, err
+		}
+		fmt.Printf("🌿 Especie %s creada (ID: %d)\n", demo.ScientificName, plant.ID)
+		byName[demo.CommonName] = plant
+	}
+
+	return byName, nil
+}
+
+// seedSites crea los sitios de ejemplo que falten en la organización. Un sitio
+// que ya existe se omite junto con todo lo que cuelga de él.
+func seedSites(ctx context.Context, t tenant.Tenant, ownerID uint, species map[string]*models.PlantSpecies) error {
+	sites := repositories.NewSiteRepository().WithContext(ctx).ForTenant(t)
+
+	current, _, err := sites.GetAll(repositories.SiteFilters{})
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(current))
+	for _, site := range current {
+		existing[site.Name] = true
+	}
+
+	farm := &models.Site{Name: "Finca La Esperanza", AreaM2: 10000, LengthM: 100, WidthM: 100, Notes: "Sitio principal de agricultura sintrópica"}
+	experimental := &models.Site{Name: "Parcela Experimental", AreaM2: 2500, LengthM: 50, WidthM: 50, Notes: "Área de pruebas y experimentación"}
+	for _, site := range []*models.Site{farm, experimental} {
+		if existing[site.Name] {
+			fmt.Printf("ℹ️ El sitio %q ya existe\n", site.Name)
+			continue
+		}
+		if err := sites.Create(site, ownerID); err != nil {
+			return err
+		}
+		fmt.Printf("🏡 Sitio %q creado (ID: %d)\n", site.Name, site.ID)
+	}
+
+	if existing[farm.Name] {
+		return nil
+	}
+	return seedFarm(ctx, t, farm, species)
+}
+
+// seedFarm crea las plantaciones, parcelas, instancias de plantas y la
+// plantilla de sugerencias del sitio principal recién creado
+func seedFarm(ctx context.Context, t tenant.Tenant, farm *models.Site, species map[string]*models.PlantSpecies) error {
+	plantations := repositories.NewPlantationRepository().WithContext(ctx).ForTenant(t)
+	plots := repositories.NewPlotRepository().WithContext(ctx).ForTenant(t)
+	instances := repositories.NewPlantInstanceRepository().WithContext(ctx).ForTenant(t)
+	templates := repositories.NewSuggestionTemplateRepository().WithContext(ctx).ForTenant(t)
+
+	north := &models.Plantation{SiteID: farm.ID, Name: "Zona Norte", AreaM2: 3000, Notes: "Área principal con mejor exposición solar"}
+	garden := &models.Plantation{SiteID: farm.ID, Name: "Huerta Central", AreaM2: 1500, Notes: "Zona de hortalizas y plantas medicinales"}
+	for _, plantation := range []*models.Plantation{north, garden} {
+		if err := plantations.Create(plantation); err != nil {
+			return err
+		}
+	}
+
+	line := &models.Plot{PlantationID: north.ID, PlotType: models.PlotTypeLine, LengthM: 50, WidthM: 3, Notes: "Línea de frutales con orientación norte-sur"}
+	island := &models.Plot{PlantationID: garden.ID, PlotType: models.PlotTypeIsland, DiameterM: 8, Notes: "Isla circular de leguminosas"}
+	for _, plot := range []*models.Plot{line, island} {
+		if err := plots.Create(plot); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	seedInstances := []*models.PlantInstance{
+		{PlotID: line.ID, SpeciesID: species["Aguacate Hass"].ID, Quantity: 5, Role: models.PlantRoleObjetivo,
+			Status: models.PlantStatusPlanted, Position: "Espaciados cada 10 metros", PlantedAt: &now},
+		{PlotID: island.ID, SpeciesID: species["Frijol Caupí"].ID, Quantity: 20, Role: models.PlantRoleServicio,
+			Status: models.PlantStatusGerminated, Position: "Distribuidos uniformemente en la isla"},
+	}
+	for _, instance := range seedInstances {
+		instance.StatusChangedAt = &now
+		instance.StatusChangedBy = cliActor
+		if err := instances.Create(instance); err != nil {
+			return err
+		}
+	}
+
+	maxDensity := 2.5
+	rules := models.TemplateRules{
+		MaxDensity:     &maxDensity,
+		RequiredStrata: []string{models.StratumHigh, models.StratumMedium, models.StratumLow},
+		MinSuccession:  []string{models.SuccessionPioneer, models.SuccessionSecondary},
+	}
+	template := &models.SuggestionTemplate{
+		PlantationID: north.ID,
+		Name:         "Sistema Agroforestal Básico",
+		Description:  "Plantilla para sistema agroforestal con frutales y leguminosas",
+		Rules:        rules.JSON(),
+	}
+	if err := templates.Create(template); err != nil {
+		return err
+	}
+
+	fmt.Printf("🌳 %q: plantaciones, parcelas, plantas y plantilla creadas\n", farm.Name)
+	return nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// El catálogo se exporta e importa como un arreglo JSON de especies con el
+// mismo formato que la API. Al importar se ignoran id, organization_id,
+// overrides_species_id y las fechas: las especies se buscan por external_ref
+// o, si no tienen, por nombre científico.
+
+// runSpecies ejecuta species export o species import
+func runSpecies(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: falta el comando de species (export, import)", errUsage)
+	}
+
+	switch args[0] {
+	case "export":
+		return runSpeciesExport(ctx, args[1:])
+	case "import":
+		return runSpeciesImport(ctx, args[1:])
+	}
+	return fmt.Errorf("%w: comando de species desconocido %q", errUsage, args[0])
+}
+
+// catalogTenant devuelve el catálogo global (orgID 0) o el de la organización
+func catalogTenant(orgID uint) tenant.Tenant {
+	if orgID == 0 {
+		return tenant.All()
+	}
+	return tenant.Organization(orgID)
+}
+
+// runSpeciesExport escribe el catálogo global, o las especies privadas de una
+// organización, en un archivo o en la salida estándar
+func runSpeciesExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("species export", flag.ContinueOnError)
+	file := fs.String("file", "", "archivo de destino (salida estándar si se omite)")
+	orgID := fs.Uint("org", 0, "exportar las especies privadas de la organización")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := openDatabase(); err != nil {
+		return err
+	}
+
+	t := tenant.Catalog()
+	if *orgID != 0 {
+		t = tenant.Organization(*orgID)
+	}
+	plants, _, err := repositories.NewPlantRepository().WithContext(ctx).ForTenant(t).GetAll(repositories.PlantFilters{})
+	if err != nil {
+		return err
+	}
+
+	// Con una organización GetAll incluye el catálogo global: solo las propias
+	own := make([]models.PlantSpecies, 0, len(plants))
+	for _, plant := range plants {
+		if *orgID == 0 || plant.OrganizationID != nil {
+			own = append(own, plant)
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return fmt.Errorf("error creando %s: %w", *file, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(own); err != nil {
+		return fmt.Errorf("error escribiendo el catálogo: %w", err)
+	}
+
+	if *file != "" {
+		fmt.Printf("✅ %d especies exportadas a %s\n", len(own), *file)
+	}
+	return nil
+}
+
+// speciesImportResult resume lo que hizo (o haría, en dry-run) la importación
+type speciesImportResult struct {
+	Created int
+	Updated int
+	Skipped int
+	Errors  []string
+}
+
+// runSpeciesImport crea las especies del archivo que no existen en el catálogo
+// y, con --update, reemplaza los datos de las existentes
+func runSpeciesImport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("species import", flag.ContinueOnError)
+	file := fs.String("file", "", "archivo JSON con las especies")
+	orgID := fs.Uint("org", 0, "importar como especies privadas de la organización")
+	update := fs.Bool("update", false, "actualizar las especies que ya existen")
+	dryRun := fs.Bool("dry-run", false, "informar los cambios sin escribir en la base de datos")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("%w: --file es requerido", errUsage)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("error leyendo %s: %w", *file, err)
+	}
+	var species []models.PlantSpecies
+	if err := json.Unmarshal(data, &species); err != nil {
+		return fmt.Errorf("%s no es un arreglo JSON de especies: %w", *file, err)
+	}
+
+	if err := openDatabase(); err != nil {
+		return err
+	}
+
+	plants := repositories.NewPlantRepository().WithContext(ctx).ForTenant(catalogTenant(*orgID))
+	result := &speciesImportResult{}
+	for i := range species {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := importSpecies(plants, &species[i], *update, *dryRun, result); err != nil {
+			return err
+		}
+	}
+
+	for _, msg := range result.Errors {
+		fmt.Printf("⚠️ %s\n", msg)
+	}
+	fmt.Printf("✅ Importación (dry-run=%t): %d creadas, %d actualizadas, %d omitidas\n",
+		*dryRun, result.Created, result.Updated, result.Skipped)
+	return nil
+}
+
+// importSpecies crea o actualiza una especie. Solo los errores de la base de
+// datos cortan la importación; los registros inválidos se omiten y se informan.
+func importSpecies(plants *repositories.PlantRepository, plant *models.PlantSpecies, update, dryRun bool, result *speciesImportResult) error {
+	normalizeImportedSpecies(plant)
+	label := plant.ScientificName
+	if label == "" {
+		label = plant.CommonName
+	}
+
+	if err := plant.Validate(); err != nil {
+		result.Skipped++
+		result.Errors = append(result.Errors, fmt.Sprintf("especie %q: %v", label, err))
+		return nil
+	}
+
+	current, err := findImportedSpecies(plants, plant)
+	if errors.Is(err, repositories.ErrSpeciesNotFound) {
+		result.Created++
+		if dryRun {
+			return nil
+		}
+		return plants.Create(plant)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !update {
+		result.Skipped++
+		return nil
+	}
+
+	result.Updated++
+	if dryRun {
+		return nil
+	}
+	_, err = plants.Update(current.ID, speciesColumns(plant), plant.Functions)
+	return err
+}
+
+// normalizeImportedSpecies descarta los datos que asigna la base de datos y
+// deja las funciones ecológicas consistentes con function_ecol
+func normalizeImportedSpecies(plant *models.PlantSpecies) {
+	plant.ID = 0
+	plant.OrganizationID = nil
+	plant.OverridesSpeciesID = nil
+	plant.PlantInstances = nil
+	plant.CreatedAt, plant.UpdatedAt = time.Time{}, time.Time{}
+	plant.CommonName = strings.TrimSpace(plant.CommonName)
+	plant.ScientificName = strings.TrimSpace(plant.ScientificName)
+	plant.ExternalRef = strings.TrimSpace(plant.ExternalRef)
+
+	primary := plant.FunctionEcol
+	for _, f := range plant.Functions {
+		if f.IsPrimary {
+			primary = f.Function
+		}
+	}
+	plant.SetFunctions(plant.FunctionNames(), primary)
+}
+
+// findImportedSpecies busca la especie en el catálogo por external_ref o, si
+// no tiene, por nombre científico
+func findImportedSpecies(plants *repositories.PlantRepository, plant *models.PlantSpecies) (*models.PlantSpecies, error) {
+	if plant.ExternalRef != "" {
+		return plants.GetByExternalRef(plant.ExternalRef)
+	}
+	if plant.ScientificName != "" {
+		return plants.GetByScientificName(plant.ScientificName)
+	}
+	return nil, repositories.ErrSpeciesNotFound
+}
+
+// speciesColumns devuelve todas las columnas editables de la especie: al
+// actualizar, el archivo importado reemplaza los datos cargados
+func speciesColumns(plant *models.PlantSpecies) map[string]interface{} {
+	return map[string]interface{}{
+		"common_name":            plant.CommonName,
+		"scientific_name":        plant.ScientificName,
+		"stratum":                plant.Stratum,
+		"function_ecol":          plant.FunctionEcol,
+		"succession_stage":       plant.SuccessionStage,
+		"external_ref":           plant.ExternalRef,
+		"notes":                  plant.Notes,
+		"mature_height_m":        plant.MatureHeightM,
+		"canopy_diameter_m":      plant.CanopyDiameterM,
+		"spacing_m":              plant.SpacingM,
+		"lifespan_years":         plant.LifespanYears,
+		"time_to_harvest_months": plant.TimeToHarvestMonths,
+		"light_requirement":      plant.LightRequirement,
+		"root_depth":             plant.RootDepth,
+		"water_needs":            plant.WaterNeeds,
+		"frost_tolerance":        plant.FrostTolerance,
+	}
+}
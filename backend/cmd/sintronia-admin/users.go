@@ -0,0 +1,149 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deibys/sintronia/internal/auth"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// Límites de longitud de las contraseñas, los mismos que el registro de la API
+// (bcrypt ignora lo que pasa de 72 bytes)
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+// runUser ejecuta user create o user reset-password
+func runUser(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: falta el comando de user (create, reset-password)", errUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		return runUserCreate(args[1:])
+	case "reset-password":
+		return runUserResetPassword(args[1:])
+	}
+	return fmt.Errorf("%w: comando de user desconocido %q", errUsage, args[0])
+}
+
+// runUserCreate crea un usuario activo con su organización personal
+func runUserCreate(args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := fs.String("email", "", "email del usuario")
+	name := fs.String("name", "", "nombre del usuario")
+	password := fs.String("password", "", "contraseña (se genera una si se omite)")
+	admin := fs.Bool("admin", false, "crear el usuario con rol de administrador")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*email) == "" || strings.TrimSpace(*name) == "" {
+		return fmt.Errorf("%w: --email y --name son requeridos", errUsage)
+	}
+
+	plain, generated, err := resolvePassword(*password)
+	if err != nil {
+		return err
+	}
+	hash, err := auth.HashPassword(plain)
+	if err != nil {
+		return err
+	}
+
+	if err := openDatabase(); err != nil {
+		return err
+	}
+
+	role := models.UserRoleUser
+	if *admin {
+		role = models.UserRoleAdmin
+	}
+	user := &models.User{
+		Email:        *email,
+		Name:         strings.TrimSpace(*name),
+		PasswordHash: hash,
+		Role:         role,
+		IsActive:     true,
+	}
+	org := &models.Organization{Name: auth.PersonalOrganizationName(user)}
+	if err := repositories.NewUserRepository().CreateWithOrganization(user, org); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Usuario %s creado (ID: %d, rol: %s, organización: %s)\n", user.Email, user.ID, user.Role, org.Slug)
+	printGeneratedPassword(plain, generated)
+	return nil
+}
+
+// runUserResetPassword reemplaza la contraseña y cierra todas las sesiones del usuario
+func runUserResetPassword(args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	email := fs.String("email", "", "email del usuario")
+	password := fs.String("password", "", "contraseña nueva (se genera una si se omite)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*email) == "" {
+		return fmt.Errorf("%w: --email es requerido", errUsage)
+	}
+
+	plain, generated, err := resolvePassword(*password)
+	if err != nil {
+		return err
+	}
+	hash, err := auth.HashPassword(plain)
+	if err != nil {
+		return err
+	}
+
+	if err := openDatabase(); err != nil {
+		return err
+	}
+
+	users := repositories.NewUserRepository()
+	user, err := users.GetByEmail(*email)
+	if err != nil {
+		return err
+	}
+	if err := users.UpdatePassword(user.ID, hash); err != nil {
+		return err
+	}
+	if err := users.RevokeAllRefreshTokens(user.ID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Contraseña de %s actualizada; sus sesiones fueron cerradas\n", user.Email)
+	printGeneratedPassword(plain, generated)
+	return nil
+}
+
+// resolvePassword valida la contraseña indicada o genera una aleatoria
+func resolvePassword(password string) (plain string, generated bool, err error) {
+	if password == "" {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return "", false, fmt.Errorf("error generando contraseña: %w", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(buf), true, nil
+	}
+
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", false, fmt.Errorf("%w: la contraseña debe tener entre %d y %d caracteres",
+			errUsage, minPasswordLength, maxPasswordLength)
+	}
+	return password, false, nil
+}
+
+func printGeneratedPassword(password string, generated bool) {
+	if generated {
+		fmt.Printf("🔑 Contraseña generada: %s\n", password)
+	}
+}
@@ -0,0 +1,35 @@
+// Package audit registra en audit_events cada alta, modificación y baja de los
+// modelos del dominio. Los callbacks de GORM registrados por RegisterCallbacks
+// escriben el evento en la misma transacción que el cambio, con el autor que
+// los repositorios reciben en el contexto (WithActor) y las diferencias entre
+// los valores anteriores y los nuevos.
+package audit
+
+import (
+	"context"
+)
+
+// Actor identifica quién hizo un cambio y desde qué solicitud. Los cambios sin
+// Actor en el contexto (CLI, tareas internas) se registran sin autor.
+type Actor struct {
+	UserID    uint
+	APIKeyID  uint // Si la solicitud se autenticó con una API key
+	RequestID string
+	IP        string
+}
+
+type contextKey struct{}
+
+// WithActor agrega el autor de los cambios al contexto
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// ActorFromContext obtiene el autor de los cambios del contexto
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(contextKey{}).(Actor)
+	return actor, ok
+}
@@ -0,0 +1,354 @@
+package audit
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Tablas auditadas. Los eventos de las instancias (plant_instance_events) ya son
+// un historial y los tokens de renovación no son datos del dominio.
+var auditedTables = map[string]bool{
+	"organizations":           true,
+	"organization_members":    true,
+	"users":                   true,
+	"api_keys":                true,
+	"integration_credentials": true,
+	"sites":                   true,
+	"site_members":            true,
+	"plantations":             true,
+	"suggestion_templates":    true,
+	"plots":                   true,
+	"plant_instances":         true,
+	"plant_species":           true,
+	"species_functions":       true,
+}
+
+// Columnas que no se comparan: las fechas de control cambian en cada
+// modificación y las de último uso se actualizan en cada login o solicitud
+var ignoredColumns = map[string]bool{
+	"created_at":    true,
+	"updated_at":    true,
+	"deleted_at":    true,
+	"last_login_at": true,
+	"last_used_at":  true,
+}
+
+// Valor con el que se registran las columnas que la API nunca expone (json:"-")
+const redacted = "[oculto]"
+
+// Clave con la que se guardan los registros previos a una modificación o baja
+const beforeKey = "audit:before"
+
+// record es un registro auditado: su clave primaria y el valor de cada columna
+type record struct {
+	keys   []interface{}
+	values map[string]interface{}
+}
+
+// RegisterCallbacks registra la auditoría en la conexión. Debe registrarse
+// después de tenant.RegisterCallbacks: los registros previos a un cambio se
+// leen con los mismos filtros de organización que el cambio.
+func RegisterCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	// Los eventos se escriben antes de confirmar la transacción del cambio
+	if err := cb.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").Register("audit:create", recordCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().After("tenant:update").Before("gorm:update").Register("audit:before_update", loadBefore); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").Register("audit:update", recordUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("tenant:delete").Before("gorm:delete").Register("audit:before_delete", loadBefore); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").Register("audit:delete", recordDelete)
+}
+
+func audited(db *gorm.DB) bool {
+	return db.Error == nil && !db.DryRun && db.Statement.Schema != nil && auditedTables[db.Statement.Table]
+}
+
+// recordCreate registra las altas con el valor de cada columna
+func recordCreate(db *gorm.DB) {
+	if !audited(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	action := models.AuditActionCreate
+	if _, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
+		action = models.AuditActionUpsert
+	}
+
+	var events []models.AuditEvent
+	for _, rv := range structValues(db.Statement.ReflectValue) {
+		created := readRecord(db, rv)
+		changes := make(map[string]models.AuditChange)
+		for column, value := range created.values {
+			if !hiddenColumn(db.Statement.Schema, column) {
+				changes[column] = models.AuditChange{After: value}
+			}
+		}
+		events = append(events, newEvent(db, action, created, changes))
+	}
+	saveEvents(db, events)
+}
+
+// loadBefore lee los registros que la modificación o la baja va a afectar
+func loadBefore(db *gorm.DB) {
+	if !audited(db) {
+		return
+	}
+
+	query, ok := affectedRows(db)
+	if !ok {
+		return // Sin condiciones GORM rechaza el cambio
+	}
+	records, err := findRecords(db, query)
+	if err != nil {
+		db.AddError(fmt.Errorf("error leyendo registros para la auditoría: %w", err))
+		return
+	}
+	db.InstanceSet(beforeKey, records)
+}
+
+// recordUpdate registra las columnas que cambiaron en cada registro modificado
+func recordUpdate(db *gorm.DB) {
+	before := beforeRecords(db)
+	if len(before) == 0 || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	// Se vuelven a leer por clave primaria: la modificación pudo cambiar las
+	// columnas por las que se filtraron (p. ej. deleted_at)
+	query := newSession(db).Unscoped().Clauses(clause.Where{Exprs: []clause.Expression{keysCondition(db.Statement.Schema, before)}})
+	after, err := findRecords(db, query)
+	if err != nil {
+		db.AddError(fmt.Errorf("error leyendo registros para la auditoría: %w", err))
+		return
+	}
+	afterByID := make(map[string]record, len(after))
+	for _, r := range after {
+		afterByID[r.id()] = r
+	}
+
+	var events []models.AuditEvent
+	for _, old := range before {
+		updated, ok := afterByID[old.id()]
+		if !ok {
+			continue
+		}
+		if changes := diff(db.Statement.Schema, old, updated); len(changes) > 0 {
+			events = append(events, newEvent(db, models.AuditActionUpdate, old, changes))
+		}
+	}
+	saveEvents(db, events)
+}
+
+// recordDelete registra las bajas con el valor que tenía cada columna
+func recordDelete(db *gorm.DB) {
+	before := beforeRecords(db)
+	if len(before) == 0 || db.Statement.RowsAffected == 0 {
+		return
+	}
+
+	events := make([]models.AuditEvent, 0, len(before))
+	for _, old := range before {
+		changes := make(map[string]models.AuditChange)
+		for column, value := range old.values {
+			if !hiddenColumn(db.Statement.Schema, column) {
+				changes[column] = models.AuditChange{Before: value}
+			}
+		}
+		events = append(events, newEvent(db, models.AuditActionDelete, old, changes))
+	}
+	saveEvents(db, events)
+}
+
+func beforeRecords(db *gorm.DB) []record {
+	if !audited(db) {
+		return nil
+	}
+	value, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return nil
+	}
+	records, _ := value.([]record)
+	return records
+}
+
+// newSession devuelve una consulta sobre el modelo del cambio, en la misma
+// transacción y con el mismo contexto (tenant y autor)
+func newSession(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(reflect.New(db.Statement.Schema.ModelType).Interface())
+}
+
+// affectedRows arma la consulta de los registros que afecta el cambio: sus
+// condiciones y, si se indicó un modelo, su clave primaria
+func affectedRows(db *gorm.DB) (*gorm.DB, bool) {
+	query := newSession(db)
+	if db.Statement.Unscoped {
+		query = query.Unscoped()
+	}
+
+	conditions := false
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(clause.Where{Exprs: append([]clause.Expression(nil), where.Exprs...)})
+			conditions = true
+		}
+	}
+
+	if rv := db.Statement.ReflectValue; rv.Kind() == reflect.Struct {
+		var exprs []clause.Expression
+		for _, field := range db.Statement.Schema.PrimaryFields {
+			value, isZero := field.ValueOf(db.Statement.Context, rv)
+			if isZero {
+				exprs = nil
+				break
+			}
+			exprs = append(exprs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: value})
+		}
+		if len(exprs) > 0 {
+			query = query.Clauses(clause.Where{Exprs: exprs})
+			conditions = true
+		}
+	}
+
+	return query, conditions
+}
+
+// keysCondition filtra los registros por su clave primaria
+func keysCondition(s *schema.Schema, records []record) clause.Expression {
+	alternatives := make([]clause.Expression, 0, len(records))
+	for _, r := range records {
+		exprs := make([]clause.Expression, 0, len(s.PrimaryFields))
+		for i, field := range s.PrimaryFields {
+			exprs = append(exprs, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: r.keys[i]})
+		}
+		alternatives = append(alternatives, clause.And(exprs...))
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0] // Un Or de un solo término se uniría con OR a los demás filtros
+	}
+	return clause.Or(alternatives...)
+}
+
+func findRecords(db *gorm.DB, query *gorm.DB) ([]record, error) {
+	rows := reflect.New(reflect.SliceOf(db.Statement.Schema.ModelType))
+	if err := query.Find(rows.Interface()).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]record, 0, rows.Elem().Len())
+	for i := 0; i < rows.Elem().Len(); i++ {
+		records = append(records, readRecord(db, rows.Elem().Index(i)))
+	}
+	return records, nil
+}
+
+// readRecord lee la clave primaria y las columnas del registro
+func readRecord(db *gorm.DB, rv reflect.Value) record {
+	s := db.Statement.Schema
+	r := record{values: make(map[string]interface{})}
+	for _, field := range s.PrimaryFields {
+		value, _ := field.ValueOf(db.Statement.Context, rv)
+		r.keys = append(r.keys, value)
+	}
+	for _, column := range s.DBNames {
+		field := s.FieldsByDBName[column]
+		if ignoredColumns[column] || (!field.Creatable && !field.Updatable) {
+			continue // Fechas de control y columnas calculadas
+		}
+		value, _ := field.ValueOf(db.Statement.Context, rv)
+		r.values[column] = value
+	}
+	return r
+}
+
+func (r record) id() string {
+	parts := make([]string, len(r.keys))
+	for i, key := range r.keys {
+		parts[i] = fmt.Sprint(key)
+	}
+	return strings.Join(parts, ":")
+}
+
+// diff devuelve las columnas que cambiaron entre dos lecturas del registro
+func diff(s *schema.Schema, before, after record) map[string]models.AuditChange {
+	changes := make(map[string]models.AuditChange)
+	for column, old := range before.values {
+		updated := after.values[column]
+		if reflect.DeepEqual(old, updated) {
+			continue
+		}
+		if hiddenColumn(s, column) {
+			changes[column] = models.AuditChange{Before: redacted, After: redacted}
+			continue
+		}
+		changes[column] = models.AuditChange{Before: old, After: updated}
+	}
+	return changes
+}
+
+// hiddenColumn indica si la API nunca expone la columna (json:"-")
+func hiddenColumn(s *schema.Schema, column string) bool {
+	field := s.FieldsByDBName[column]
+	return field != nil && field.Tag.Get("json") == "-"
+}
+
+// structValues devuelve los registros de un alta individual o por lotes
+func structValues(rv reflect.Value) []reflect.Value {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Struct:
+		return []reflect.Value{rv}
+	case reflect.Slice, reflect.Array:
+		values := make([]reflect.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, reflect.Indirect(rv.Index(i)))
+		}
+		return values
+	}
+	return nil
+}
+
+func newEvent(db *gorm.DB, action string, r record, changes map[string]models.AuditChange) models.AuditEvent {
+	event := models.AuditEvent{
+		Action:     action,
+		EntityType: db.Statement.Table,
+		EntityID:   r.id(),
+		Changes:    changes,
+	}
+	if actor, ok := ActorFromContext(db.Statement.Context); ok {
+		if actor.UserID != 0 {
+			userID := actor.UserID
+			event.ActorID = &userID
+		}
+		if actor.APIKeyID != 0 {
+			keyID := actor.APIKeyID
+			event.APIKeyID = &keyID
+		}
+		event.RequestID = actor.RequestID
+		event.IP = actor.IP
+	}
+	return event
+}
+
+// saveEvents guarda los eventos en la transacción del cambio: si no se pueden
+// registrar, el cambio tampoco se aplica
+func saveEvents(db *gorm.DB, events []models.AuditEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if err := db.Session(&gorm.Session{NewDB: true}).Create(&events).Error; err != nil {
+		db.AddError(fmt.Errorf("error registrando auditoría: %w", err))
+	}
+}
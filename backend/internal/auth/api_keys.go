@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// APIKeyPrefix distingue una API key de un token de acceso JWT
+const APIKeyPrefix = "snt_"
+
+const (
+	// Vencimiento de las claves creadas sin expires_in_days
+	defaultAPIKeyTTL = 90 * 24 * time.Hour
+	// El último uso se registra como máximo una vez por intervalo para no
+	// escribir en cada solicitud de un script
+	apiKeyTouchInterval = time.Minute
+	// Caracteres de la clave que se guardan para reconocerla
+	apiKeyVisiblePrefix = len(APIKeyPrefix) + 8
+)
+
+var (
+	// ErrInvalidAPIKey se devuelve cuando la clave no existe, venció, fue revocada o su usuario está desactivado
+	ErrInvalidAPIKey = apperror.Unauthorized("invalid_api_key", "API key inválida o expirada")
+	// ErrInvalidAPIKeyScope se devuelve al crear una clave con un scope desconocido
+	ErrInvalidAPIKeyScope = apperror.Invalid("scopes", apperror.FieldInvalid, "scope de API key inválido")
+)
+
+// IsAPIKey indica si la credencial tiene el formato de una API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// HashAPIKey calcula el hash con el que se guarda una API key. Las claves tienen
+// 256 bits aleatorios, así que alcanza con SHA-256, igual que los tokens de renovación.
+func HashAPIKey(key string) string {
+	return HashRefreshToken(key)
+}
+
+// APIKeyService crea, lista, revoca y verifica las API keys de los usuarios
+type APIKeyService struct {
+	keys  *repositories.APIKeyRepository
+	users *repositories.UserRepository
+	now   func() time.Time
+}
+
+// NewAPIKeyService crea el servicio de API keys
+func NewAPIKeyService(keys *repositories.APIKeyRepository, users *repositories.UserRepository) *APIKeyService {
+	return &APIKeyService{keys: keys, users: users, now: time.Now}
+}
+
+// Create genera una clave para el usuario. La respuesta incluye la clave
+// completa, que no se guarda y no se puede volver a obtener.
+func (s *APIKeyService) Create(userID uint, req models.CreateAPIKeyRequest) (*models.APIKeyCreatedResponse, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("error generando API key: %w", err)
+	}
+	secret := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	ttl := defaultAPIKeyTTL
+	if req.ExpiresInDays != nil {
+		ttl = time.Duration(*req.ExpiresInDays) * 24 * time.Hour
+	}
+	expiresAt := s.now().Add(ttl)
+
+	key := models.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    secret[:apiKeyVisiblePrefix],
+		KeyHash:   HashAPIKey(secret),
+		Scopes:    scopes,
+		ExpiresAt: &expiresAt,
+	}
+	if err := s.keys.Create(&key); err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyCreatedResponse{APIKey: key, Key: secret}, nil
+}
+
+// List obtiene las claves del usuario
+func (s *APIKeyService) List(userID uint) ([]models.APIKey, error) {
+	return s.keys.GetByUser(userID)
+}
+
+// Revoke revoca una clave del usuario
+func (s *APIKeyService) Revoke(userID, id uint) error {
+	return s.keys.Revoke(userID, id, s.now())
+}
+
+// Authenticate verifica la clave y devuelve su usuario. Registra el último uso.
+func (s *APIKeyService) Authenticate(secret string) (*models.User, *models.APIKey, error) {
+	now := s.now()
+
+	key, err := s.keys.GetByHash(HashAPIKey(secret))
+	if errors.Is(err, repositories.ErrAPIKeyNotFound) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !key.IsActive(now) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.users.GetByID(key.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.keys.TouchLastUsed(key.ID, now); err != nil {
+			// No impide usar la clave
+			log.Printf("⚠️ Error registrando el uso de la API key %d: %v", key.ID, err)
+		}
+		key.LastUsedAt = &now
+	}
+
+	return user, key, nil
+}
+
+// normalizeScopes valida los scopes y quita los repetidos
+func normalizeScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !models.IsValidAPIKeyScope(scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAPIKeyScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized, nil
+}
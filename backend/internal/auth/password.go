@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword genera el hash bcrypt de una contraseña
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error generando hash de contraseña: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword compara una contraseña con su hash bcrypt
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyPasswordHash es un hash bcrypt con el costo por defecto que no pertenece
+// a ninguna cuenta. Login lo compara cuando el email no existe para que la
+// respuesta tarde lo mismo y no revele qué cuentas están registradas.
+const dummyPasswordHash = "$2a$10$V3AWBioUD1ewABY.xy6TEe1Hm/kXubm6x953Rv237u62G03El.sja"
+
+// checkDummyPassword hace el mismo trabajo que CheckPassword sin una cuenta real
+func checkDummyPassword(password string) {
+	CheckPassword(dummyPasswordHash, password)
+}
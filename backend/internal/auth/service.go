@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+var (
+	// ErrInvalidCredentials se devuelve cuando el email o la contraseña no coinciden
+	ErrInvalidCredentials = apperror.Unauthorized("invalid_credentials", "email o contraseña incorrectos")
+	// ErrUserInactive se devuelve cuando la cuenta está desactivada
+	ErrUserInactive = apperror.Forbidden("user_inactive", "la cuenta está desactivada")
+	// ErrInvalidRefreshToken se devuelve cuando el token de renovación no existe, venció o fue revocado
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "token de renovación inválido o expirado")
+)
+
+// SessionInfo identifica el cliente que abre una sesión
+type SessionInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// UserStore guarda usuarios y tokens de renovación. Lo implementa
+// repositories.UserRepository.
+type UserStore interface {
+	CreateWithOrganization(user *models.User, org *models.Organization) error
+	GetByID(id uint) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	TouchLastLogin(id uint, at time.Time) error
+	CreateRefreshToken(token *models.RefreshToken) error
+	GetRefreshToken(tokenHash string) (*models.RefreshToken, error)
+	RotateRefreshToken(oldID uint, replacement *models.RefreshToken, now time.Time) error
+	RevokeRefreshToken(userID uint, tokenHash string, now time.Time) error
+	RevokeAllRefreshTokens(userID uint, now time.Time) error
+}
+
+// Service registra usuarios y emite, rota y revoca sus tokens
+type Service struct {
+	users  UserStore
+	tokens *TokenManager
+	now    func() time.Time
+}
+
+// NewService crea el servicio de autenticación
+func NewService(users UserStore, tokens *TokenManager) *Service {
+	return &Service{users: users, tokens: tokens, now: time.Now}
+}
+
+// Register crea una cuenta con rol de usuario y su organización personal y abre
+// su primera sesión
+func (s *Service) Register(req models.RegisterRequest, session SessionInfo) (*models.TokenResponse, error) {
+	hash, err := HashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:        req.Email,
+		Name:         req.Name,
+		PasswordHash: hash,
+		Role:         models.UserRoleUser,
+		IsActive:     true,
+	}
+	// Cada cuenta nueva tiene su propia organización para empezar a trabajar
+	org := &models.Organization{Name: PersonalOrganizationName(user)}
+	if err := s.users.CreateWithOrganization(user, org); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session)
+}
+
+// Login verifica email y contraseña y abre una sesión
+func (s *Service) Login(req models.LoginRequest, session SessionInfo) (*models.TokenResponse, error) {
+	user, err := s.users.GetByEmail(req.Email)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		checkDummyPassword(req.Password)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !CheckPassword(user.PasswordHash, req.Password) {
+		return nil, ErrInvalidCredentials
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	now := s.now()
+	if err := s.users.TouchLastLogin(user.ID, now); err != nil {
+		return nil, err
+	}
+	user.LastLoginAt = &now
+
+	return s.issueTokens(user, session)
+}
+
+// Refresh cambia un token de renovación por un par nuevo. El token usado queda
+// revocado; si se presenta un token ya rotado se asume que fue robado y se
+// revocan todas las sesiones del usuario.
+func (s *Service) Refresh(refreshToken string, session SessionInfo) (*models.TokenResponse, error) {
+	now := s.now()
+
+	stored, err := s.users.GetRefreshToken(HashRefreshToken(refreshToken))
+	if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !stored.IsActive(now) {
+		if stored.ReplacedByID != nil {
+			s.revokeAfterReuse(stored.UserID, now)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.users.GetByID(stored.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	token, replacement, err := s.newRefreshToken(user.ID, session)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.users.RotateRefreshToken(stored.ID, replacement, now)
+	if errors.Is(err, repositories.ErrRefreshTokenInactive) {
+		// Otra solicitud rotó el mismo token al mismo tiempo
+		s.revokeAfterReuse(stored.UserID, now)
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(user, token)
+}
+
+// Logout revoca el token de renovación indicado, o todas las sesiones del usuario
+func (s *Service) Logout(userID uint, refreshToken string, allSessions bool) error {
+	now := s.now()
+	if allSessions {
+		return s.users.RevokeAllRefreshTokens(userID, now)
+	}
+	if refreshToken == "" {
+		return nil
+	}
+	return s.users.RevokeRefreshToken(userID, HashRefreshToken(refreshToken), now)
+}
+
+// CurrentUser obtiene el usuario autenticado
+func (s *Service) CurrentUser(userID uint) (*models.User, error) {
+	return s.users.GetByID(userID)
+}
+
+// issueTokens abre una sesión nueva: token de acceso y token de renovación
+func (s *Service) issueTokens(user *models.User, session SessionInfo) (*models.TokenResponse, error) {
+	token, refresh, err := s.newRefreshToken(user.ID, session)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.CreateRefreshToken(refresh); err != nil {
+		return nil, err
+	}
+
+	return s.buildResponse(user, token)
+}
+
+func (s *Service) newRefreshToken(userID uint, session SessionInfo) (string, *models.RefreshToken, error) {
+	token, hash, expiresAt, err := s.tokens.NewRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, &models.RefreshToken{
+		UserID:    userID,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+		UserAgent: truncate(session.UserAgent, 255),
+		IPAddress: truncate(session.IPAddress, 64),
+	}, nil
+}
+
+func (s *Service) buildResponse(user *models.User, refreshToken string) (*models.TokenResponse, error) {
+	access, err := s.tokens.IssueAccessToken(user.ID, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenResponse{
+		AccessToken:  access,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.tokens.AccessTTL() / time.Second),
+		User:         user,
+	}, nil
+}
+
+func (s *Service) revokeAfterReuse(userID uint, now time.Time) {
+	log.Printf("⚠️ Reuso de token de renovación del usuario %d: se revocan todas sus sesiones", userID)
+	if err := s.users.RevokeAllRefreshTokens(userID, now); err != nil {
+		log.Printf("❌ Error revocando sesiones del usuario %d: %v", userID, err)
+	}
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
+// PersonalOrganizationName nombra la organización creada al registrarse
+func PersonalOrganizationName(user *models.User) string {
+	name := strings.TrimSpace(user.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(user.Email, "@")
+	}
+	return "Organización de " + name
+}
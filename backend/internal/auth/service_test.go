@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserStore guarda usuarios y tokens en memoria con la misma semántica que UserRepository
+type fakeUserStore struct {
+	users      map[uint]*models.User
+	tokens     []*models.RefreshToken
+	rotateErr  error // Si no es nil, RotateRefreshToken lo devuelve
+	revokedAll []uint
+}
+
+func newFakeUserStore(users ...*models.User) *fakeUserStore {
+	store := &fakeUserStore{users: make(map[uint]*models.User)}
+	for _, user := range users {
+		store.users[user.ID] = user
+	}
+	return store
+}
+
+func (s *fakeUserStore) CreateWithOrganization(user *models.User, org *models.Organization) error {
+	user.ID = uint(len(s.users) + 1)
+	s.users[user.ID] = user
+	return nil
+}
+
+func (s *fakeUserStore) GetByID(id uint) (*models.User, error) {
+	if user, ok := s.users[id]; ok {
+		return user, nil
+	}
+	return nil, repositories.ErrUserNotFound
+}
+
+func (s *fakeUserStore) GetByEmail(email string) (*models.User, error) {
+	for _, user := range s.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, repositories.ErrUserNotFound
+}
+
+func (s *fakeUserStore) TouchLastLogin(id uint, at time.Time) error {
+	return nil
+}
+
+func (s *fakeUserStore) CreateRefreshToken(token *models.RefreshToken) error {
+	token.ID = uint(len(s.tokens) + 1)
+	s.tokens = append(s.tokens, token)
+	return nil
+}
+
+func (s *fakeUserStore) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	for _, token := range s.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, repositories.ErrRefreshTokenNotFound
+}
+
+func (s *fakeUserStore) RotateRefreshToken(oldID uint, replacement *models.RefreshToken, now time.Time) error {
+	if s.rotateErr != nil {
+		return s.rotateErr
+	}
+	old := s.tokens[oldID-1]
+	if !old.IsActive(now) {
+		return repositories.ErrRefreshTokenInactive
+	}
+	s.CreateRefreshToken(replacement)
+	old.RevokedAt = &now
+	old.ReplacedByID = &replacement.ID
+	return nil
+}
+
+func (s *fakeUserStore) RevokeRefreshToken(userID uint, tokenHash string, now time.Time) error {
+	for _, token := range s.tokens {
+		if token.UserID == userID && token.TokenHash == tokenHash && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (s *fakeUserStore) RevokeAllRefreshTokens(userID uint, now time.Time) error {
+	s.revokedAll = append(s.revokedAll, userID)
+	for _, token := range s.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (s *fakeUserStore) activeTokens(now time.Time) int {
+	active := 0
+	for _, token := range s.tokens {
+		if token.IsActive(now) {
+			active++
+		}
+	}
+	return active
+}
+
+const testPassword = "contraseña-segura"
+
+// testService crea un servicio con un usuario activo (ID 1) y otro desactivado (ID 2)
+func testService(t *testing.T, clock *time.Time) (*Service, *fakeUserStore) {
+	t.Helper()
+	// Costo mínimo para que los tests no tarden; CheckPassword acepta cualquier costo
+	hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+
+	store := newFakeUserStore(
+		&models.User{ID: 1, Email: "ana@example.org", PasswordHash: string(hash), Role: models.UserRoleUser, IsActive: true},
+		&models.User{ID: 2, Email: "luis@example.org", PasswordHash: string(hash), Role: models.UserRoleUser},
+	)
+	svc := NewService(store, testTokenManager(t, clock))
+	svc.now = func() time.Time { return *clock }
+	return svc, store
+}
+
+func TestLogin(t *testing.T) {
+	clock := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	svc, store := testService(t, &clock)
+
+	cases := []struct {
+		name     string
+		email    string
+		password string
+		wantErr  error
+	}{
+		{"credenciales válidas", "ana@example.org", testPassword, nil},
+		{"contraseña incorrecta", "ana@example.org", "otra-contraseña", ErrInvalidCredentials},
+		{"email desconocido", "nadie@example.org", testPassword, ErrInvalidCredentials},
+		{"cuenta desactivada", "luis@example.org", testPassword, ErrUserInactive},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := len(store.tokens)
+			tokens, err := svc.Login(models.LoginRequest{Email: tc.email, Password: tc.password}, SessionInfo{})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, se esperaba %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				if len(store.tokens) != before {
+					t.Error("no se debe abrir una sesión si el login falla")
+				}
+				return
+			}
+
+			if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.ExpiresIn != 15*60 {
+				t.Errorf("tokens = %+v, se esperaban ambos tokens", tokens)
+			}
+			if len(store.tokens) != before+1 || store.tokens[before].TokenHash != HashRefreshToken(tokens.RefreshToken) {
+				t.Error("se esperaba guardar el hash del token de renovación")
+			}
+		})
+	}
+}
+
+func TestRefreshRotation(t *testing.T) {
+	cases := []struct {
+		name string
+		// setup abre la sesión inicial y devuelve el token que se presenta
+		setup          func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string
+		wantErr        error
+		wantRevokedAll bool
+	}{
+		{
+			name: "rota el token usado",
+			setup: func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string {
+				return login(t, svc)
+			},
+		},
+		{
+			name: "token desconocido",
+			setup: func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string {
+				return "inexistente"
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "token vencido",
+			setup: func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string {
+				token := login(t, svc)
+				*clock = clock.Add(25 * time.Hour)
+				return token
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "token revocado por logout",
+			setup: func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string {
+				token := login(t, svc)
+				svc.Logout(1, token, false)
+				return token
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "reuso de un token rotado revoca todas las sesiones",
+			setup: func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string {
+				token := login(t, svc)
+				if _, err := svc.Refresh(token, SessionInfo{}); err != nil {
+					t.Fatalf("Refresh: %v", err)
+				}
+				return token
+			},
+			wantErr:        ErrInvalidRefreshToken,
+			wantRevokedAll: true,
+		},
+		{
+			name: "rotación simultánea revoca todas las sesiones",
+			setup: func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string {
+				token := login(t, svc)
+				store.rotateErr = repositories.ErrRefreshTokenInactive
+				return token
+			},
+			wantErr:        ErrInvalidRefreshToken,
+			wantRevokedAll: true,
+		},
+		{
+			name: "usuario desactivado",
+			setup: func(t *testing.T, svc *Service, store *fakeUserStore, clock *time.Time) string {
+				token := login(t, svc)
+				store.users[1].IsActive = false
+				return token
+			},
+			wantErr: ErrUserInactive,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+			svc, store := testService(t, &clock)
+			presented := tc.setup(t, svc, store, &clock)
+
+			tokens, err := svc.Refresh(presented, SessionInfo{UserAgent: "test"})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, se esperaba %v", err, tc.wantErr)
+			}
+			if revoked := len(store.revokedAll) > 0; revoked != tc.wantRevokedAll {
+				t.Errorf("revocación de todas las sesiones = %t, se esperaba %t", revoked, tc.wantRevokedAll)
+			}
+			if tc.wantRevokedAll && store.activeTokens(clock) != 0 {
+				t.Errorf("quedan %d sesiones activas tras el reuso", store.activeTokens(clock))
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			// El token usado queda revocado y apunta a su reemplazo, que sí se puede usar
+			old := store.tokens[0]
+			if old.RevokedAt == nil || old.ReplacedByID == nil || *old.ReplacedByID != store.tokens[1].ID {
+				t.Errorf("token usado = %+v, se esperaba revocado y reemplazado", old)
+			}
+			if tokens.RefreshToken == presented || store.tokens[1].TokenHash != HashRefreshToken(tokens.RefreshToken) {
+				t.Error("se esperaba un token de renovación nuevo")
+			}
+			if _, err := svc.Refresh(tokens.RefreshToken, SessionInfo{}); err != nil {
+				t.Errorf("el token nuevo no se pudo usar: %v", err)
+			}
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	cases := []struct {
+		name        string
+		token       func(first string) string
+		allSessions bool
+		wantActive  int
+	}{
+		{"revoca solo el token indicado", func(first string) string { return first }, false, 1},
+		{"sin token no revoca nada", func(string) string { return "" }, false, 2},
+		{"todas las sesiones", func(string) string { return "" }, true, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+			svc, store := testService(t, &clock)
+			first := login(t, svc)
+			login(t, svc)
+
+			if err := svc.Logout(1, tc.token(first), tc.allSessions); err != nil {
+				t.Fatalf("Logout: %v", err)
+			}
+			if active := store.activeTokens(clock); active != tc.wantActive {
+				t.Errorf("sesiones activas = %d, se esperaban %d", active, tc.wantActive)
+			}
+		})
+	}
+}
+
+// login abre una sesión del usuario activo y devuelve su token de renovación
+func login(t *testing.T, svc *Service) string {
+	t.Helper()
+	tokens, err := svc.Login(models.LoginRequest{Email: "ana@example.org", Password: testPassword}, SessionInfo{})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return tokens.RefreshToken
+}
+
+func TestDummyPasswordHashUsesDefaultCost(t *testing.T) {
+	// Con otro costo la comparación tardaría distinto que con una cuenta real
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil || cost != bcrypt.DefaultCost {
+		t.Errorf("costo = %d (%v), se esperaba %d", cost, err, bcrypt.DefaultCost)
+	}
+}
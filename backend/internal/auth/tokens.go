@@ -0,0 +1,176 @@
+// Package auth implementa la autenticación: contraseñas con bcrypt, tokens de
+// acceso JWT firmados y tokens de renovación rotativos guardados en la base de datos.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/deibys/sintronia/internal/config"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// Issuer identifica a esta API en los tokens emitidos
+	Issuer = "sintronia-api"
+
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// ErrInvalidToken se devuelve cuando el token de acceso no es válido o venció
+var ErrInvalidToken = apperror.Unauthorized("invalid_token", "token inválido o expirado")
+
+// Claims son los datos del usuario dentro del token de acceso
+type Claims struct {
+	UserID uint   `json:"uid"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// TokenManager firma y verifica tokens de acceso con HMAC-SHA256
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewTokenManager crea un TokenManager. El secreto debe tener al menos 32 bytes.
+func NewTokenManager(secret []byte, accessTTL, refreshTTL time.Duration) (*TokenManager, error) {
+	if len(secret) < 32 {
+		return nil, errors.New("el secreto JWT debe tener al menos 32 bytes")
+	}
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+
+	return &TokenManager{
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}, nil
+}
+
+var (
+	defaultMu      sync.Mutex
+	defaultManager *TokenManager
+)
+
+// Configure crea el TokenManager por defecto con la configuración de
+// autenticación. Sin jwt_secret se genera un secreto aleatorio: sirve para
+// desarrollo, pero los tokens no sobreviven un reinicio.
+func Configure(cfg config.AuthConfig) error {
+	manager, err := newManagerFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	defaultMu.Lock()
+	defaultManager = manager
+	defaultMu.Unlock()
+	return nil
+}
+
+// DefaultTokenManager devuelve el TokenManager creado por Configure. Si no se
+// llamó, lo crea con la configuración por defecto.
+func DefaultTokenManager() *TokenManager {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultManager == nil {
+		manager, err := newManagerFromConfig(config.Default().Auth)
+		if err != nil {
+			panic(err.Error())
+		}
+		defaultManager = manager
+	}
+	return defaultManager
+}
+
+func newManagerFromConfig(cfg config.AuthConfig) (*TokenManager, error) {
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		log.Println("⚠️ JWT_SECRET no configurado: se usa un secreto aleatorio, las sesiones se pierden al reiniciar")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("no se pudo generar el secreto JWT: %w", err)
+		}
+	}
+	return NewTokenManager(secret, cfg.AccessTTL(), cfg.RefreshTTL())
+}
+
+// AccessTTL devuelve la validez de los tokens de acceso
+func (m *TokenManager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+// IssueAccessToken firma un token de acceso para el usuario
+func (m *TokenManager) IssueAccessToken(userID uint, role string) (string, error) {
+	now := m.now()
+	claims := Claims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    Issuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTTL)),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("error firmando token: %w", err)
+	}
+	return signed, nil
+}
+
+// ParseAccessToken verifica la firma, el emisor y la expiración del token
+func (m *TokenManager) ParseAccessToken(token string) (*Claims, error) {
+	claims := &Claims{}
+
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil || !parsed.Valid || claims.UserID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// NewRefreshToken genera un token de renovación aleatorio y su hash para guardar
+func (m *TokenManager) NewRefreshToken() (token, hash string, expiresAt time.Time, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", time.Time{}, fmt.Errorf("error generando token de renovación: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashRefreshToken(token), m.now().Add(m.refreshTTL), nil
+}
+
+// HashRefreshToken calcula el hash con el que se guarda un token de renovación
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
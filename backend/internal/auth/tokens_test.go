@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = bytes.Repeat([]byte("k"), 32)
+
+// testTokenManager crea un TokenManager con el reloj fijo en *clock
+func testTokenManager(t *testing.T, clock *time.Time) *TokenManager {
+	t.Helper()
+	m, err := NewTokenManager(testSecret, 15*time.Minute, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewTokenManager: %v", err)
+	}
+	m.now = func() time.Time { return *clock }
+	return m
+}
+
+func TestNewTokenManager(t *testing.T) {
+	if _, err := NewTokenManager(make([]byte, 31), 0, 0); err == nil {
+		t.Error("se esperaba error con un secreto de 31 bytes")
+	}
+
+	m, err := NewTokenManager(testSecret, 0, 0)
+	if err != nil {
+		t.Fatalf("NewTokenManager: %v", err)
+	}
+	if m.accessTTL != DefaultAccessTTL || m.refreshTTL != DefaultRefreshTTL {
+		t.Errorf("ttl = %v/%v, se esperaban los valores por defecto", m.accessTTL, m.refreshTTL)
+	}
+}
+
+func TestAccessTokenRoundTrip(t *testing.T) {
+	clock := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	m := testTokenManager(t, &clock)
+
+	token, err := m.IssueAccessToken(42, "admin")
+	if err != nil {
+		t.Fatalf("IssueAccessToken: %v", err)
+	}
+
+	claims, err := m.ParseAccessToken(token)
+	if err != nil {
+		t.Fatalf("ParseAccessToken: %v", err)
+	}
+	if claims.UserID != 42 || claims.Role != "admin" || claims.Subject != "42" || claims.Issuer != Issuer {
+		t.Errorf("claims = %+v, se esperaba el usuario 42 con rol admin", claims)
+	}
+	if !claims.ExpiresAt.Time.Equal(clock.Add(15 * time.Minute)) {
+		t.Errorf("expira = %v, se esperaba %v", claims.ExpiresAt.Time, clock.Add(15*time.Minute))
+	}
+}
+
+func TestParseAccessTokenRejects(t *testing.T) {
+	clock := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	m := testTokenManager(t, &clock)
+
+	valid, _ := m.IssueAccessToken(42, "user")
+	otherSecret, _ := (&TokenManager{secret: bytes.Repeat([]byte("x"), 32), accessTTL: time.Hour, now: m.now}).IssueAccessToken(42, "user")
+
+	sign := func(method jwt.SigningMethod, claims Claims) string {
+		signed, err := jwt.NewWithClaims(method, claims).SignedString(testSecret)
+		if err != nil {
+			t.Fatalf("SignedString: %v", err)
+		}
+		return signed
+	}
+	expiresAt := jwt.NewNumericDate(clock.Add(time.Hour))
+
+	cases := []struct {
+		name    string
+		token   string
+		advance time.Duration
+	}{
+		{"vencido", valid, 16 * time.Minute},
+		{"otra clave", otherSecret, 0},
+		{"malformado", "no.es.jwt", 0},
+		{"otro algoritmo", sign(jwt.SigningMethodHS512, Claims{UserID: 42, RegisteredClaims: jwt.RegisteredClaims{Issuer: Issuer, ExpiresAt: expiresAt}}), 0},
+		{"otro emisor", sign(jwt.SigningMethodHS256, Claims{UserID: 42, RegisteredClaims: jwt.RegisteredClaims{Issuer: "otro", ExpiresAt: expiresAt}}), 0},
+		{"sin expiración", sign(jwt.SigningMethodHS256, Claims{UserID: 42, RegisteredClaims: jwt.RegisteredClaims{Issuer: Issuer}}), 0},
+		{"sin usuario", sign(jwt.SigningMethodHS256, Claims{RegisteredClaims: jwt.RegisteredClaims{Issuer: Issuer, ExpiresAt: expiresAt}}), 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := clock.Add(tc.advance)
+			m.now = func() time.Time { return now }
+
+			if _, err := m.ParseAccessToken(tc.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("err = %v, se esperaba ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	clock := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	m := testTokenManager(t, &clock)
+
+	token, hash, expiresAt, err := m.NewRefreshToken()
+	if err != nil {
+		t.Fatalf("NewRefreshToken: %v", err)
+	}
+	if hash != HashRefreshToken(token) || len(hash) != 64 || hash == token {
+		t.Errorf("hash = %q, se esperaba el SHA-256 del token", hash)
+	}
+	if !expiresAt.Equal(clock.Add(24 * time.Hour)) {
+		t.Errorf("expira = %v, se esperaba %v", expiresAt, clock.Add(24*time.Hour))
+	}
+
+	other, _, _, _ := m.NewRefreshToken()
+	if other == token {
+		t.Error("dos tokens de renovación no deben coincidir")
+	}
+}
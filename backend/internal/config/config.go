@@ -0,0 +1,220 @@
+// Package config reúne la configuración de la API y de sintronia-admin. Load
+// parte de los valores por defecto, aplica un archivo YAML opcional y encima
+// las variables de entorno, y valida el resultado antes de devolverlo.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnvVar es la variable de entorno con la ruta del archivo de configuración
+const FileEnvVar = "SINTRONIA_CONFIG"
+
+// Config es la configuración completa. Cada campo con etiqueta env se puede
+// reemplazar con esa variable de entorno; los marcados secret no se muestran.
+type Config struct {
+	Server       ServerConfig       `yaml:"server"`
+	Database     DatabaseConfig     `yaml:"database"`
+	CORS         CORSConfig         `yaml:"cors"`
+	Pagination   PaginationConfig   `yaml:"pagination"`
+	Auth         AuthConfig         `yaml:"auth"`
+	Integrations IntegrationsConfig `yaml:"integrations"`
+}
+
+// ServerConfig configura el servidor HTTP
+type ServerConfig struct {
+	Port                   int    `yaml:"port" env:"PORT"`
+	Mode                   string `yaml:"mode" env:"GIN_MODE"` // "debug", "release" o "test"
+	ReadTimeoutSeconds     int    `yaml:"read_timeout_seconds" env:"SERVER_READ_TIMEOUT_SECONDS"`
+	WriteTimeoutSeconds    int    `yaml:"write_timeout_seconds" env:"SERVER_WRITE_TIMEOUT_SECONDS"`
+	IdleTimeoutSeconds     int    `yaml:"idle_timeout_seconds" env:"SERVER_IDLE_TIMEOUT_SECONDS"`
+	ShutdownTimeoutSeconds int    `yaml:"shutdown_timeout_seconds" env:"SERVER_SHUTDOWN_TIMEOUT_SECONDS"` // Plazo para terminar las solicitudes en curso
+}
+
+// ReadTimeout devuelve el plazo para leer una solicitud completa
+func (c ServerConfig) ReadTimeout() time.Duration {
+	return time.Duration(c.ReadTimeoutSeconds) * time.Second
+}
+
+// WriteTimeout devuelve el plazo para escribir la respuesta
+func (c ServerConfig) WriteTimeout() time.Duration {
+	return time.Duration(c.WriteTimeoutSeconds) * time.Second
+}
+
+// IdleTimeout devuelve cuánto se mantiene abierta una conexión sin solicitudes
+func (c ServerConfig) IdleTimeout() time.Duration {
+	return time.Duration(c.IdleTimeoutSeconds) * time.Second
+}
+
+// ShutdownTimeout devuelve el plazo para drenar las solicitudes al apagar
+func (c ServerConfig) ShutdownTimeout() time.Duration {
+	return time.Duration(c.ShutdownTimeoutSeconds) * time.Second
+}
+
+// DatabaseConfig configura la conexión a PostgreSQL y su pool
+type DatabaseConfig struct {
+	Host                   string `yaml:"host" env:"DB_HOST"`
+	Port                   int    `yaml:"port" env:"DB_PORT"`
+	User                   string `yaml:"user" env:"DB_USER"`
+	Password               string `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name                   string `yaml:"name" env:"DB_NAME"`
+	SSLMode                string `yaml:"sslmode" env:"DB_SSLMODE"`
+	Required               bool   `yaml:"required" env:"DB_REQUIRED"`         // false: la API arranca sin base de datos (modo degradado)
+	AutoMigrate            bool   `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE"` // false: solo avisa si faltan migraciones
+	LogQueries             bool   `yaml:"log_queries" env:"DB_LOG_QUERIES"`   // Registra cada consulta SQL
+	MaxOpenConns           int    `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns           int    `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetimeMinutes int    `yaml:"conn_max_lifetime_minutes" env:"DB_CONN_MAX_LIFETIME_MINUTES"` // 0 = sin límite
+}
+
+// DSN arma la cadena de conexión de PostgreSQL
+func (c DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=UTC",
+		dsnValue(c.Host), dsnValue(c.User), dsnValue(c.Password), dsnValue(c.Name), c.Port, c.SSLMode)
+}
+
+// dsnValue entrecomilla un valor del DSN para admitir espacios y comillas
+func dsnValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// ConnMaxLifetime devuelve la vida máxima de cada conexión del pool
+func (c DatabaseConfig) ConnMaxLifetime() time.Duration {
+	return time.Duration(c.ConnMaxLifetimeMinutes) * time.Minute
+}
+
+// CORSConfig configura los orígenes que pueden llamar a la API desde un navegador
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"` // En el entorno, separados por comas
+	AllowAll       bool     `yaml:"allow_all" env:"CORS_ALLOW_ALL"`             // Cualquier origen, sin credenciales
+}
+
+// PaginationConfig limita el tamaño de página de los listados
+type PaginationConfig struct {
+	DefaultMaxLimit int `yaml:"default_max_limit" env:"DEFAULT_MAX_PAGINATION_LIMIT"`
+	AdminMaxLimit   int `yaml:"admin_max_limit" env:"ADMIN_MAX_PAGINATION_LIMIT"`
+}
+
+// AuthConfig configura los tokens de acceso y de renovación
+type AuthConfig struct {
+	JWTSecret        string `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"` // Vacío: secreto aleatorio (solo desarrollo)
+	AccessTTLMinutes int    `yaml:"access_ttl_minutes" env:"JWT_ACCESS_TTL_MINUTES"`
+	RefreshTTLHours  int    `yaml:"refresh_ttl_hours" env:"JWT_REFRESH_TTL_HOURS"`
+}
+
+// AccessTTL devuelve la validez de los tokens de acceso
+func (c AuthConfig) AccessTTL() time.Duration {
+	return time.Duration(c.AccessTTLMinutes) * time.Minute
+}
+
+// RefreshTTL devuelve la validez de los tokens de renovación
+func (c AuthConfig) RefreshTTL() time.Duration {
+	return time.Duration(c.RefreshTTLHours) * time.Hour
+}
+
+// IntegrationsConfig configura las APIs externas y el cifrado de sus credenciales
+type IntegrationsConfig struct {
+	CredentialsEncryptionKey string            `yaml:"credentials_encryption_key" env:"CREDENTIALS_ENCRYPTION_KEY" secret:"true"` // base64 de 32 bytes
+	Permapeople              PermapeopleConfig `yaml:"permapeople"`
+}
+
+// PermapeopleConfig configura el cliente de Permapeople. Las credenciales son
+// el respaldo cuando no hay unas guardadas en la base de datos.
+type PermapeopleConfig struct {
+	BaseURL   string `yaml:"base_url" env:"PERMAPEOPLE_BASE_URL"` // Vacío: la URL pública de la API
+	KeyID     string `yaml:"key_id" env:"PERMAPEOPLE_KEY_ID"`
+	KeySecret string `yaml:"key_secret" env:"PERMAPEOPLE_KEY_SECRET" secret:"true"`
+}
+
+// Default devuelve la configuración por defecto, pensada para desarrollo local
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:                   3000,
+			Mode:                   "debug",
+			ReadTimeoutSeconds:     15,
+			WriteTimeoutSeconds:    120, // Las importaciones de catálogos pueden tardar
+			IdleTimeoutSeconds:     120,
+			ShutdownTimeoutSeconds: 30,
+		},
+		Database: DatabaseConfig{
+			Host:                   "localhost",
+			Port:                   5432,
+			User:                   "sintropia_user",
+			Password:               "sintropia_pass",
+			Name:                   "sintropia",
+			SSLMode:                "disable",
+			Required:               true,
+			AutoMigrate:            true,
+			MaxOpenConns:           100,
+			MaxIdleConns:           10,
+			ConnMaxLifetimeMinutes: 60,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{
+				"http://localhost:5173",
+				"http://localhost:3000",
+				"http://127.0.0.1:5173",
+				"http://127.0.0.1:3000",
+				"https://stackblitz.com",
+			},
+			AllowAll: true,
+		},
+		Pagination: PaginationConfig{
+			DefaultMaxLimit: 100,
+			AdminMaxLimit:   1000,
+		},
+		Auth: AuthConfig{
+			AccessTTLMinutes: 15,
+			RefreshTTLHours:  30 * 24,
+		},
+	}
+}
+
+// Load arma la configuración: valores por defecto, el archivo YAML de path (si
+// no está vacío) y las variables de entorno. Devuelve todos los problemas de
+// validación juntos.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := errors.Join(applyEnv(cfg, os.LookupEnv), cfg.Validate()); err != nil {
+		return nil, fmt.Errorf("configuración inválida:\n%w", err)
+	}
+
+	return cfg, nil
+}
+
+// loadFile aplica el archivo YAML sobre la configuración. Las claves
+// desconocidas son un error, para no ignorar un nombre mal escrito.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error leyendo la configuración: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error en %s: %w", path, err)
+	}
+	return nil
+}
+
+// YAML devuelve la configuración en YAML con los secretos ocultos
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c.Redacted())
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// clearEnv vacía las variables de la configuración para que el entorno de
+// quien corre los tests no cambie los resultados (las vacías se ignoran)
+func clearEnv(t *testing.T) {
+	t.Helper()
+	eachField(reflect.ValueOf(Default()).Elem(), func(field reflect.StructField, _ reflect.Value) {
+		if name := field.Tag.Get("env"); name != "" {
+			t.Setenv(name, "")
+		}
+	})
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string // Vacío: sin archivo
+		env     map[string]string
+		check   func(t *testing.T, cfg *Config)
+		wantErr string
+	}{
+		{
+			name: "valores por defecto",
+			check: func(t *testing.T, cfg *Config) {
+				if !reflect.DeepEqual(cfg, Default()) {
+					t.Errorf("cfg = %+v, se esperaban los valores por defecto", cfg)
+				}
+			},
+		},
+		{
+			name: "el archivo reemplaza los valores por defecto",
+			file: "server:\n  port: 8080\n  mode: release\ndatabase:\n  host: db.interno\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 8080 || cfg.Server.Mode != "release" || cfg.Database.Host != "db.interno" {
+					t.Errorf("cfg = %+v, se esperaban los valores del archivo", cfg.Server)
+				}
+				if cfg.Database.Port != 5432 {
+					t.Errorf("database.port = %d, se esperaba el valor por defecto", cfg.Database.Port)
+				}
+			},
+		},
+		{
+			name: "el entorno reemplaza al archivo",
+			file: "server:\n  port: 8080\n",
+			env: map[string]string{
+				"PORT":                 " 9090 ",
+				"DB_AUTO_MIGRATE":      "false",
+				"CORS_ALLOWED_ORIGINS": "https://a.ejemplo.com, ,https://b.ejemplo.com",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 9090 || cfg.Database.AutoMigrate {
+					t.Errorf("port = %d, auto_migrate = %t, se esperaban los valores del entorno", cfg.Server.Port, cfg.Database.AutoMigrate)
+				}
+				want := []string{"https://a.ejemplo.com", "https://b.ejemplo.com"}
+				if !reflect.DeepEqual(cfg.CORS.AllowedOrigins, want) {
+					t.Errorf("allowed_origins = %v, se esperaba %v", cfg.CORS.AllowedOrigins, want)
+				}
+			},
+		},
+		{
+			name:    "clave desconocida en el archivo",
+			file:    "server:\n  puerto: 8080\n",
+			wantErr: "puerto",
+		},
+		{
+			name:    "variable con tipo inválido",
+			env:     map[string]string{"DB_PORT": "cinco"},
+			wantErr: "DB_PORT",
+		},
+		{
+			name:    "junta los errores del entorno y de la validación",
+			env:     map[string]string{"DB_LOG_QUERIES": "quizás", "GIN_MODE": "prod"},
+			wantErr: "server.mode",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearEnv(t)
+			for name, value := range tc.env {
+				t.Setenv(name, value)
+			}
+			path := ""
+			if tc.file != "" {
+				path = writeFile(t, tc.file)
+			}
+
+			cfg, err := Load(path)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, se esperaba un error con %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			tc.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load(writeFile(t, ""))
+	if err != nil {
+		t.Fatalf("Load con un archivo vacío: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("cfg = %+v, se esperaban los valores por defecto", cfg)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "no-existe.yaml")); err == nil {
+		t.Error("se esperaba un error para un archivo inexistente")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	validKey := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	cases := []struct {
+		name   string
+		modify func(c *Config)
+		keys   []string // Claves que deben aparecer en el error; vacío: válida
+	}{
+		{"valores por defecto", func(c *Config) {}, nil},
+		{"secretos válidos", func(c *Config) {
+			c.Auth.JWTSecret = strings.Repeat("s", 32)
+			c.Integrations.CredentialsEncryptionKey = validKey
+			c.Integrations.Permapeople = PermapeopleConfig{BaseURL: "https://permapeople.org", KeyID: "id", KeySecret: "secreto"}
+		}, nil},
+		{"puertos fuera de rango", func(c *Config) {
+			c.Server.Port = 0
+			c.Database.Port = 70000
+		}, []string{"server.port", "database.port"}},
+		{"modos inválidos", func(c *Config) {
+			c.Server.Mode = "prod"
+			c.Database.SSLMode = "on"
+		}, []string{"server.mode", "database.sslmode"}},
+		{"campos requeridos de la base de datos", func(c *Config) {
+			c.Database.Host = " "
+			c.Database.User = ""
+			c.Database.Name = ""
+		}, []string{"database.host", "database.user", "database.name"}},
+		{"pool inconsistente", func(c *Config) {
+			c.Database.MaxOpenConns = 5
+			c.Database.MaxIdleConns = 10
+			c.Database.ConnMaxLifetimeMinutes = -1
+		}, []string{"database.max_idle_conns", "database.conn_max_lifetime_minutes"}},
+		{"origen CORS inválido", func(c *Config) {
+			c.CORS.AllowedOrigins = []string{"https://ok.ejemplo.com", "ftp://x", "localhost:5173"}
+		}, []string{`"ftp://x"`, `"localhost:5173"`}},
+		{"límites de paginación", func(c *Config) {
+			c.Pagination.DefaultMaxLimit = 0
+			c.Pagination.AdminMaxLimit = -1
+		}, []string{"pagination.default_max_limit", "pagination.admin_max_limit"}},
+		{"autenticación", func(c *Config) {
+			c.Auth.JWTSecret = "corto"
+			c.Auth.AccessTTLMinutes = 0
+			c.Auth.RefreshTTLHours = 0
+		}, []string{"auth.jwt_secret", "auth.access_ttl_minutes", "auth.refresh_ttl_hours"}},
+		{"clave de cifrado inválida", func(c *Config) {
+			c.Integrations.CredentialsEncryptionKey = base64.StdEncoding.EncodeToString(make([]byte, 16))
+		}, []string{"integrations.credentials_encryption_key"}},
+		{"credenciales de Permapeople incompletas", func(c *Config) {
+			c.Integrations.Permapeople = PermapeopleConfig{BaseURL: "permapeople.org", KeyID: "id"}
+		}, []string{"integrations.permapeople.base_url", "key_id y key_secret"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Default()
+			tc.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tc.keys) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("se esperaban errores en %v", tc.keys)
+			}
+			for _, key := range tc.keys {
+				if !strings.Contains(err.Error(), key) {
+					t.Errorf("err = %v, falta %q", err, key)
+				}
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "pass-db"
+	cfg.Auth.JWTSecret = strings.Repeat("s", 32)
+	cfg.Integrations.Permapeople.KeyID = "key-id"
+
+	redacted := cfg.Redacted()
+
+	if redacted.Database.Password != RedactedValue || redacted.Auth.JWTSecret != RedactedValue {
+		t.Errorf("secretos = %q, %q, se esperaba %q", redacted.Database.Password, redacted.Auth.JWTSecret, RedactedValue)
+	}
+	// Un secreto vacío queda vacío para que se vea que no está configurado
+	if redacted.Integrations.CredentialsEncryptionKey != "" || redacted.Integrations.Permapeople.KeySecret != "" {
+		t.Errorf("los secretos vacíos no deben ocultarse: %+v", redacted.Integrations)
+	}
+	if redacted.Integrations.Permapeople.KeyID != "key-id" || redacted.Database.User != cfg.Database.User {
+		t.Errorf("los valores que no son secretos deben mantenerse: %+v", redacted.Integrations.Permapeople)
+	}
+
+	// La configuración original no cambia
+	if cfg.Database.Password != "pass-db" {
+		t.Errorf("Redacted modificó el original: password = %q", cfg.Database.Password)
+	}
+	redacted.CORS.AllowedOrigins[0] = "https://otro.ejemplo.com"
+	if cfg.CORS.AllowedOrigins[0] == "https://otro.ejemplo.com" {
+		t.Error("Redacted comparte allowed_origins con el original")
+	}
+
+	out, err := cfg.YAML()
+	if err != nil {
+		t.Fatalf("YAML: %v", err)
+	}
+	if strings.Contains(string(out), "pass-db") || !strings.Contains(string(out), RedactedValue) {
+		t.Errorf("YAML muestra secretos:\n%s", out)
+	}
+}
+
+func TestDSN(t *testing.T) {
+	db := DatabaseConfig{Host: "localhost", Port: 5432, User: "ana", Password: `p a's\`, Name: "sintropia", SSLMode: "disable"}
+
+	want := `host='localhost' user='ana' password='p a\'s\\' dbname='sintropia' port=5432 sslmode=disable TimeZone=UTC`
+	if got := db.DSN(); got != want {
+		t.Errorf("DSN = %s, se esperaba %s", got, want)
+	}
+}
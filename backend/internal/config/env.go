@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RedactedValue reemplaza a los secretos configurados al mostrar la configuración
+const RedactedValue = "[oculto]"
+
+// applyEnv reemplaza cada campo con etiqueta env por el valor de esa variable,
+// si está definida y no vacía
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	var errs []error
+	eachField(reflect.ValueOf(cfg).Elem(), func(field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("env")
+		if name == "" {
+			return
+		}
+		raw, ok := lookup(name)
+		if !ok || strings.TrimSpace(raw) == "" {
+			return
+		}
+		if err := setFromString(value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// setFromString asigna a value el texto de una variable de entorno según su tipo
+func setFromString(value reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q no es un número entero", raw)
+		}
+		value.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q no es true ni false", raw)
+		}
+		value.SetBool(b)
+	case reflect.Slice:
+		items := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("tipo %s no soportado", value.Kind())
+	}
+	return nil
+}
+
+// Redacted devuelve una copia de la configuración con los secretos ocultos
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+
+	eachField(reflect.ValueOf(&redacted).Elem(), func(field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") == "true" && value.String() != "" {
+			value.SetString(RedactedValue)
+		}
+	})
+	return &redacted
+}
+
+// eachField recorre los campos de la estructura y de sus estructuras anidadas
+func eachField(v reflect.Value, fn func(field reflect.StructField, value reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if value.Kind() == reflect.Struct {
+			eachField(value, fn)
+			continue
+		}
+		fn(field, value)
+	}
+}
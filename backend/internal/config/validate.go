@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Modos de Gin y de SSL de PostgreSQL aceptados
+var (
+	validModes    = []string{"debug", "release", "test"}
+	validSSLModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+)
+
+// Validate verifica la configuración y devuelve todos los problemas juntos,
+// cada uno con la clave YAML del campo
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	// Servidor
+	check(validPort(c.Server.Port), "server.port", "debe estar entre 1 y 65535")
+	check(contains(validModes, c.Server.Mode), "server.mode", "debe ser uno de: %s", strings.Join(validModes, ", "))
+	check(c.Server.ReadTimeoutSeconds > 0, "server.read_timeout_seconds", "debe ser mayor a cero")
+	check(c.Server.WriteTimeoutSeconds > 0, "server.write_timeout_seconds", "debe ser mayor a cero")
+	check(c.Server.IdleTimeoutSeconds > 0, "server.idle_timeout_seconds", "debe ser mayor a cero")
+	check(c.Server.ShutdownTimeoutSeconds > 0, "server.shutdown_timeout_seconds", "debe ser mayor a cero")
+
+	// Base de datos
+	db := c.Database
+	check(strings.TrimSpace(db.Host) != "", "database.host", "es requerido")
+	check(validPort(db.Port), "database.port", "debe estar entre 1 y 65535")
+	check(strings.TrimSpace(db.User) != "", "database.user", "es requerido")
+	check(strings.TrimSpace(db.Name) != "", "database.name", "es requerido")
+	check(contains(validSSLModes, db.SSLMode), "database.sslmode", "debe ser uno de: %s", strings.Join(validSSLModes, ", "))
+	check(db.MaxOpenConns > 0, "database.max_open_conns", "debe ser mayor a cero")
+	check(db.MaxIdleConns >= 0 && db.MaxIdleConns <= db.MaxOpenConns, "database.max_idle_conns",
+		"debe estar entre 0 y max_open_conns (%d)", db.MaxOpenConns)
+	check(db.ConnMaxLifetimeMinutes >= 0, "database.conn_max_lifetime_minutes", "no puede ser negativo")
+
+	// CORS
+	for _, origin := range c.CORS.AllowedOrigins {
+		check(validOrigin(origin), "cors.allowed_origins", "%q no es un origen válido (esquema://host[:puerto])", origin)
+	}
+
+	// Paginación
+	check(c.Pagination.DefaultMaxLimit > 0, "pagination.default_max_limit", "debe ser mayor a cero")
+	check(c.Pagination.AdminMaxLimit > 0, "pagination.admin_max_limit", "debe ser mayor a cero")
+
+	// Autenticación
+	check(c.Auth.JWTSecret == "" || len(c.Auth.JWTSecret) >= 32, "auth.jwt_secret", "debe tener al menos 32 bytes")
+	check(c.Auth.AccessTTLMinutes > 0, "auth.access_ttl_minutes", "debe ser mayor a cero")
+	check(c.Auth.RefreshTTLHours > 0, "auth.refresh_ttl_hours", "debe ser mayor a cero")
+
+	// Integraciones
+	check(validEncryptionKey(c.Integrations.CredentialsEncryptionKey), "integrations.credentials_encryption_key",
+		"debe ser base64 de 32 bytes")
+	pp := c.Integrations.Permapeople
+	check(pp.BaseURL == "" || validOrigin(pp.BaseURL), "integrations.permapeople.base_url", "%q no es una URL http(s) válida", pp.BaseURL)
+	check((pp.KeyID == "") == (pp.KeySecret == ""), "integrations.permapeople", "key_id y key_secret se configuran juntos")
+
+	return errors.Join(errs...)
+}
+
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// validOrigin acepta URLs http(s) con host
+func validOrigin(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validEncryptionKey(encoded string) bool {
+	if encoded == "" {
+		return true // Sin clave el vault queda deshabilitado
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	return err == nil && len(key) == 32
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
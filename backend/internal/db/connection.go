@@ -1,12 +1,18 @@
 package db
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
-	"github.com/deibys/sintronia/pkg/models"
+	"github.com/deibys/sintronia/internal/audit"
+	"github.com/deibys/sintronia/internal/config"
+	"github.com/deibys/sintronia/internal/migrate"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/migrations"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -14,30 +20,54 @@ import (
 
 var DB *gorm.DB
 
-// InitDatabase inicializa la conexión a PostgreSQL con GORM
-func InitDatabase() error {
-	// Construir DSN desde variables de entorno
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
-		getEnv("DB_HOST", "localhost"),
-		getEnv("DB_USER", "sintropia_user"),
-		getEnv("DB_PASSWORD", "sintropia_pass"),
-		getEnv("DB_NAME", "sintropia"),
-		getEnv("DB_PORT", "5432"),
-		getEnv("DB_SSLMODE", "disable"),
-	)
+// InitDatabase inicializa la conexión a PostgreSQL con GORM, aplica las
+// migraciones pendientes y registra los filtros de organización y la auditoría
+func InitDatabase(cfg config.DatabaseConfig) error {
+	if err := Connect(cfg); err != nil {
+		return err
+	}
 
+	if err := prepare(cfg); err != nil {
+		// Sin esquema o sin filtros de organización la conexión no se puede usar
+		_ = CloseDatabase()
+		DB = nil
+		return err
+	}
+	return nil
+}
+
+func prepare(cfg config.DatabaseConfig) error {
+	// Migraciones versionadas (migrations/*.sql)
+	if err := runMigrations(cfg.AutoMigrate); err != nil {
+		return err
+	}
+
+	// Aislamiento entre organizaciones: desde aquí cada consulta a las tablas de
+	// una organización necesita el tenant en su contexto
+	if err := tenant.RegisterCallbacks(DB); err != nil {
+		return fmt.Errorf("error registrando filtros de organización: %w", err)
+	}
+
+	// Auditoría: cada cambio de los modelos del dominio queda en audit_events
+	if err := audit.RegisterCallbacks(DB); err != nil {
+		return fmt.Errorf("error registrando auditoría: %w", err)
+	}
+
+	return nil
+}
+
+// Connect abre la conexión a PostgreSQL sin migrar ni registrar callbacks
+// (p. ej. para el subcomando migrate)
+func Connect(cfg config.DatabaseConfig) error {
 	// Configurar logger de GORM
-	gormLogger := logger.Default
-	if os.Getenv("GIN_MODE") == "debug" {
+	gormLogger := logger.Default.LogMode(logger.Silent)
+	if cfg.LogQueries {
 		gormLogger = logger.Default.LogMode(logger.Info)
-	} else {
-		gormLogger = logger.Default.LogMode(logger.Silent)
 	}
 
-	// Conectar a la base de datos
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+	// Conectar a la base de datos. gorm.Open devuelve la instancia aunque falle,
+	// así que DB solo se asigna si la conexión se pudo abrir.
+	conn, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
 		Logger: gormLogger,
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
@@ -49,69 +79,93 @@ func InitDatabase() error {
 	}
 
 	// Configurar pool de conexiones
-	sqlDB, err := DB.DB()
+	sqlDB, err := conn.DB()
 	if err != nil {
 		return fmt.Errorf("error obteniendo instancia SQL: %w", err)
 	}
 
-	// Configuración del pool
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime())
 
+	DB = conn
 	log.Println("✅ Conexión a PostgreSQL establecida")
-
-	// Auto-migrar modelos
-	if err := autoMigrate(); err != nil {
-		return fmt.Errorf("error en auto-migración: %w", err)
-	}
-
 	return nil
 }
 
-// autoMigrate ejecuta las migraciones automáticas de GORM
-func autoMigrate() error {
-	log.Println("🔄 Ejecutando auto-migraciones...")
-
-	err := DB.AutoMigrate(
-		&models.Site{},
-		&models.Plantation{},
-		&models.PlantSpecies{},
-		&models.Plot{},
-		&models.PlantInstance{},
-		&models.SuggestionTemplate{},
-	)
+// NewMigrator devuelve el migrador de las migraciones embebidas en el binario
+func NewMigrator() (*migrate.Migrator, error) {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo instancia SQL: %w", err)
+	}
+	return migrate.New(sqlDB, migrations.FS)
+}
 
+// runMigrations aplica las migraciones pendientes al iniciar. Sin autoMigrate
+// (DB_AUTO_MIGRATE=false) solo avisa si faltan (se aplican con "migrate up").
+func runMigrations(autoMigrate bool) error {
+	migrator, err := NewMigrator()
 	if err != nil {
-		return fmt.Errorf("error en auto-migración: %w", err)
+		return err
+	}
+	ctx := context.Background()
+
+	if !autoMigrate {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return fmt.Errorf("error consultando migraciones: %w", err)
+		}
+		if pending > 0 {
+			log.Printf("⚠️ Hay %d migraciones pendientes: ejecuta \"migrate up\"", pending)
+		}
+		return nil
 	}
 
-	log.Println("✅ Auto-migraciones completadas")
+	log.Println("🔄 Ejecutando migraciones...")
+	count, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("error en migraciones: %w", err)
+	}
+	log.Printf("✅ Migraciones completadas (%d aplicadas)", count)
 	return nil
 }
 
+// ErrNotInitialized se devuelve cuando la API corre sin base de datos
+var ErrNotInitialized = errors.New("base de datos no inicializada")
+
 // HealthCheck verifica el estado de la conexión a la base de datos
-func HealthCheck() error {
+func HealthCheck(ctx context.Context) error {
+	if DB == nil {
+		return ErrNotInitialized
+	}
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return err
 	}
-	return sqlDB.Ping()
+	return sqlDB.PingContext(ctx)
+}
+
+// PoolStats devuelve las estadísticas del pool de conexiones
+func PoolStats() (sql.DBStats, error) {
+	if DB == nil {
+		return sql.DBStats{}, ErrNotInitialized
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
 }
 
 // CloseDatabase cierra la conexión a la base de datos
 func CloseDatabase() error {
+	if DB == nil {
+		return nil
+	}
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return err
 	}
 	return sqlDB.Close()
 }
-
-// getEnv obtiene una variable de entorno con valor por defecto
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
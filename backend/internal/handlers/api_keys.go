@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/auth"
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var apiKeyService *auth.APIKeyService
+
+// getAPIKeyService obtiene el servicio de API keys, inicializándolo si es necesario
+func getAPIKeyService() *auth.APIKeyService {
+	if apiKeyService == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		apiKeyService = auth.NewAPIKeyService(repositories.NewAPIKeyRepository(), getUserRepo())
+	}
+	return apiKeyService
+}
+
+// GetAPIKeysHandler lista las API keys del usuario (sin el secreto)
+func GetAPIKeysHandler(c *gin.Context) {
+	svc := getAPIKeyService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	keys, err := svc.List(c.GetUint("user_id"))
+	if err != nil {
+		respondError(c, err, "Error obteniendo API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    keys,
+	})
+}
+
+// CreateAPIKeyHandler crea una API key para el usuario. La clave completa solo
+// se devuelve en esta respuesta.
+func CreateAPIKeyHandler(c *gin.Context) {
+	svc := getAPIKeyService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	key, err := svc.Create(userID, req)
+	if err != nil {
+		respondError(c, err, "Error creando API key")
+		return
+	}
+
+	log.Printf("🔑 API key %s (ID: %d) creada por el usuario %d con scopes %v", key.Prefix, key.ID, userID, key.Scopes)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    key,
+		Message: "API key creada. Guárdala ahora: no se vuelve a mostrar",
+	})
+}
+
+// RevokeAPIKeyHandler revoca una API key del usuario
+func RevokeAPIKeyHandler(c *gin.Context) {
+	svc := getAPIKeyService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := svc.Revoke(c.GetUint("user_id"), id); err != nil {
+		respondError(c, err, "Error revocando API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "API key revocada exitosamente",
+	})
+}
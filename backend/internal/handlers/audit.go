@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var auditRepo *repositories.AuditRepository
+
+// getAuditRepo obtiene el repository, inicializándolo si es necesario
+func getAuditRepo() *repositories.AuditRepository {
+	if auditRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		auditRepo = repositories.NewAuditRepository()
+	}
+	return auditRepo
+}
+
+// GetAuditEventsHandler lista los eventos de auditoría (solo administradores).
+// Filtros: entity_type, entity_id, actor_id, action, field (columna modificada),
+// request_id, since y until (RFC 3339 o AAAA-MM-DD).
+func GetAuditEventsHandler(c *gin.Context) {
+	repo := getAuditRepo()
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	// Parámetros de paginación
+	page, limit := parsePagination(c)
+
+	filters := repositories.AuditFilters{
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Action:     c.Query("action"),
+		Field:      c.Query("field"),
+		RequestID:  c.Query("request_id"),
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+	}
+
+	if filters.Action != "" && !models.IsValidAuditAction(filters.Action) {
+		respondInvalid(c, "action", "Acción inválida. Use "+strings.Join(models.GetAuditActions(), ", "))
+		return
+	}
+
+	if raw := c.Query("actor_id"); raw != "" {
+		actorID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || actorID == 0 {
+			respondInvalid(c, "actor_id", "actor_id debe ser un entero positivo")
+			return
+		}
+		filters.ActorID = uint(actorID)
+	}
+
+	var ok bool
+	if filters.Since, ok = parseAuditTime(c, "since", false); !ok {
+		return
+	}
+	if filters.Until, ok = parseAuditTime(c, "until", true); !ok {
+		return
+	}
+
+	events, total, err := repo.GetAll(filters)
+	if err != nil {
+		respondError(c, err, "Error obteniendo eventos de auditoría")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       events,
+		Pagination: newPagination(page, limit, total),
+	})
+}
+
+// parseAuditTime lee un filtro de fecha en RFC 3339 o AAAA-MM-DD. Con endOfDay,
+// una fecha sin hora incluye todo ese día. Si es inválido responde 400 y devuelve false.
+func parseAuditTime(c *gin.Context, key string, endOfDay bool) (*time.Time, bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, true
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, true
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t, true
+	}
+
+	respondInvalid(c, key, key+" debe ser una fecha RFC 3339 o AAAA-MM-DD")
+	return nil, false
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/auth"
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var authService *auth.Service
+
+// getAuthService obtiene el servicio de autenticación, inicializándolo si es necesario
+func getAuthService() *auth.Service {
+	if authService == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		authService = auth.NewService(repositories.NewUserRepository(), auth.DefaultTokenManager())
+	}
+	return authService
+}
+
+// RegisterHandler crea una cuenta de usuario y devuelve sus primeros tokens
+func RegisterHandler(c *gin.Context) {
+	svc := getAuthService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var req models.RegisterRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := svc.Register(req, sessionInfo(c))
+	if err != nil {
+		respondAuthError(c, err, "Error registrando usuario")
+		return
+	}
+
+	log.Printf("👤 Usuario registrado: %s (ID: %d)", tokens.User.Email, tokens.User.ID)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    tokens,
+		Message: "Usuario registrado exitosamente",
+	})
+}
+
+// LoginHandler verifica las credenciales y devuelve un token de acceso y uno de renovación
+func LoginHandler(c *gin.Context) {
+	svc := getAuthService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var req models.LoginRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := svc.Login(req, sessionInfo(c))
+	if err != nil {
+		respondAuthError(c, err, "Error iniciando sesión")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// RefreshHandler cambia un token de renovación por un par de tokens nuevo
+func RefreshHandler(c *gin.Context) {
+	svc := getAuthService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var req models.RefreshRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := svc.Refresh(req.RefreshToken, sessionInfo(c))
+	if err != nil {
+		respondAuthError(c, err, "Error renovando sesión")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// LogoutHandler revoca el token de renovación enviado, o todas las sesiones del
+// usuario con all_sessions. El cuerpo es opcional: sin cuerpo no se revoca ningún
+// token. El token de acceso sigue válido hasta su expiración.
+func LogoutHandler(c *gin.Context) {
+	svc := getAuthService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var req models.LogoutRequest
+
+	if c.Request.ContentLength != 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	if err := svc.Logout(c.GetUint("user_id"), req.RefreshToken, req.AllSessions); err != nil {
+		respondAuthError(c, err, "Error cerrando sesión")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Sesión cerrada exitosamente",
+	})
+}
+
+// MeHandler devuelve el usuario autenticado
+func MeHandler(c *gin.Context) {
+	svc := getAuthService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	user, err := svc.CurrentUser(c.GetUint("user_id"))
+	if err != nil {
+		respondAuthError(c, err, "Error obteniendo usuario")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    user,
+	})
+}
+
+// sessionInfo identifica el cliente para registrarlo en el token de renovación
+func sessionInfo(c *gin.Context) auth.SessionInfo {
+	return auth.SessionInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// respondAuthError traduce los errores de autenticación a respuestas HTTP
+func respondAuthError(c *gin.Context, err error, fallback string) {
+	// Un token válido de un usuario que ya no existe no identifica a nadie
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		err = apperror.Unauthorized("user_not_found", err.Error())
+	}
+	respondError(c, err, fallback)
+}
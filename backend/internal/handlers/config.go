@@ -0,0 +1,17 @@
+package handlers
+
+import "github.com/deibys/sintronia/internal/config"
+
+// Configuración que usan los handlers. Parte de los valores por defecto y se
+// reemplaza con Configure al arrancar, antes de atender solicitudes.
+var (
+	pagination   = config.Default().Pagination
+	integrations = config.Default().Integrations
+)
+
+// Configure aplica la configuración de la API a los handlers
+func Configure(cfg *config.Config) {
+	pagination = cfg.Pagination
+	integrations = cfg.Integrations
+	credentialService = nil // Se vuelve a crear con la clave y credenciales nuevas
+}
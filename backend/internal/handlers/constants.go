@@ -7,70 +7,120 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// GetConstantsHandler devuelve todas las constantes disponibles
+// constantGroups lista los valores canónicos de cada grupo de constantes
+var constantGroups = map[string][]string{
+	models.ConstantGroupStrata: {
+		models.StratumEmergent,
+		models.StratumHigh,
+		models.StratumMedium,
+		models.StratumLow,
+		models.StratumGround,
+		models.StratumClimber,
+		models.StratumRoot,
+	},
+	models.ConstantGroupSuccessionStages: {
+		models.SuccessionPlacenta,
+		models.SuccessionPioneer,
+		models.SuccessionSecondary,
+		models.SuccessionClimax,
+	},
+	models.ConstantGroupFunctions: {
+		models.FunctionNitrogenFixer,
+		models.FunctionDynamicAccumulator,
+		models.FunctionGroundCover,
+		models.FunctionWindbreak,
+		models.FunctionPollinator,
+		models.FunctionPestControl,
+		models.FunctionSoilAeration,
+		models.FunctionWaterRegulation,
+		models.FunctionBiomassProduction,
+		models.FunctionFood,
+		models.FunctionMedicinal,
+		models.FunctionTimber,
+		models.FunctionFiber,
+		models.FunctionOrnamental,
+	},
+	models.ConstantGroupPlantingModes: {
+		models.PlantingModeSeed,
+		models.PlantingModeCutting,
+		models.PlantingModeStake,
+		models.PlantingModeSeedling,
+		models.PlantingModeTree,
+	},
+	// Los estados se publican en su forma canónica, que es la que acepta
+	// PlantInstance.Validate y el CHECK de la base de datos
+	models.ConstantGroupStatuses: {
+		models.PlantStatusPlanned,
+		models.PlantStatusGerminated,
+		models.PlantStatusPlanted,
+		models.PlantStatusEstablished,
+		models.PlantStatusProductive,
+		models.PlantStatusDormant,
+		models.PlantStatusDead,
+	},
+	models.ConstantGroupSoilTypes: {
+		models.SoilTypeArgiloso,
+		models.SoilTypeArenoso,
+		models.SoilTypeFranco,
+		models.SoilTypeHumifero,
+		models.SoilTypePedregoso,
+		models.SoilTypeAnegadizo,
+	},
+	models.ConstantGroupPlotTypes: {
+		models.PlotTypeLine,
+		models.PlotTypeIsland,
+		models.PlotTypeGuild,
+	},
+	models.ConstantGroupPlantRoles: {
+		models.PlantRoleObjetivo,
+		models.PlantRoleServicio,
+		models.PlantRoleAcompañante,
+	},
+	models.ConstantGroupLight: {
+		models.LightFullSun,
+		models.LightPartialShade,
+		models.LightShade,
+	},
+	models.ConstantGroupRootDepths: {
+		models.RootDepthShallow,
+		models.RootDepthMedium,
+		models.RootDepthDeep,
+	},
+	models.ConstantGroupWaterNeeds: {
+		models.WaterNeedsLow,
+		models.WaterNeedsMedium,
+		models.WaterNeedsHigh,
+	},
+	models.ConstantGroupFrostTolerance: {
+		models.FrostSensitive,
+		models.FrostSemiHardy,
+		models.FrostHardy,
+	},
+	models.ConstantGroupSiteRoles: models.GetSiteRoles(),
+}
+
+// GetConstantsHandler devuelve todas las constantes disponibles.
+// Con ?lang=es|en cada valor se devuelve como {value, label, description}.
 func GetConstantsHandler(c *gin.Context) {
-	constants := map[string]interface{}{
-		"estratos": []string{
-			models.StratumEmergent,
-			models.StratumHigh,
-			models.StratumMedium,
-			models.StratumLow,
-			models.StratumGround,
-			models.StratumClimber,
-			models.StratumRoot,
-		},
-		"etapas_sucesionales": []string{
-			models.SuccessionPlacenta,
-			models.SuccessionPioneer,
-			models.SuccessionSecondary,
-			models.SuccessionClimax,
-		},
-		"funciones": []string{
-			models.FunctionNitrogenFixer,
-			models.FunctionDynamicAccumulator,
-			models.FunctionGroundCover,
-			models.FunctionWindbreak,
-			models.FunctionPollinator,
-			models.FunctionPestControl,
-			models.FunctionSoilAeration,
-			models.FunctionWaterRegulation,
-			models.FunctionBiomassProduction,
-			models.FunctionFood,
-			models.FunctionMedicinal,
-			models.FunctionTimber,
-			models.FunctionFiber,
-			models.FunctionOrnamental,
-		},
-		"modalidades_plantacion": []string{
-			models.PlantingModeSeed,
-			models.PlantingModeCutting,
-			models.PlantingModeStake,
-			models.PlantingModeSeedling,
-			models.PlantingModeTree,
-		},
-		"estados": []string{
-			models.StatusPlanned,
-			models.StatusGerminating,
-			models.StatusSeedling,
-			models.StatusPlanted,
-			models.StatusEstablished,
-			models.StatusProductive,
-			models.StatusDormant,
-			models.StatusDead,
-		},
-		"tipos_suelo": []string{
-			models.SoilTypeArgiloso,
-			models.SoilTypeArenoso,
-			models.SoilTypeFranco,
-			models.SoilTypeHumifero,
-			models.SoilTypePedregoso,
-			models.SoilTypeAnegadizo,
-		},
-		"tipo_de_parcela": []string{
-			models.PlotTypeLine,
-			models.PlotTypeIsland,
-			models.PlotTypeGuild,
-		},
+	lang := c.Query("lang")
+
+	if lang == "" {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Data:    constantGroups,
+			Message: "Constantes del sistema",
+		})
+		return
+	}
+
+	if !models.IsValidLang(lang) {
+		respondInvalid(c, "lang", "Idioma no soportado. Use lang=es o lang=en")
+		return
+	}
+
+	constants := make(map[string][]models.ConstantOption, len(constantGroups))
+	for group, values := range constantGroups {
+		constants[group] = models.LocalizedOptions(group, values, lang)
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/httpclient"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetHTTPClientDiagnosticsHandler devuelve el estado del cliente HTTP saliente:
+// contadores, uso del caché y circuit breaker de cada host externo
+func GetHTTPClientDiagnosticsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    httpclient.Shared().Stats(),
+	})
+}
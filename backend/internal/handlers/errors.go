@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Los errores de validación se informan con el nombre JSON del campo
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// respondError deja el error para que middleware.ErrorHandler responda con su
+// estado y código. Los errores que no son del dominio se informan con fallback;
+// la causa solo queda en el log.
+func respondError(c *gin.Context, err error, fallback string) {
+	if _, ok := apperror.As(err); !ok {
+		err = apperror.Internal(fallback, err)
+	}
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// respondInvalid responde 400 por un parámetro inválido, con el campo en details
+func respondInvalid(c *gin.Context, field, message string) {
+	respondError(c, apperror.Invalid(field, apperror.FieldInvalid, message), message)
+}
+
+// bindJSON decodifica el cuerpo de la solicitud. Si es inválido responde 400
+// con el detalle de cada campo y devuelve false.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		respondError(c, bindingError(err), "JSON inválido")
+		return false
+	}
+	return true
+}
+
+// bindingError traduce un error de decodificación o de las reglas binding a un
+// error de validación con el detalle de cada campo
+func bindingError(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		details := make([]apperror.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			details = append(details, fieldError(fe))
+		}
+		return apperror.Validation(details...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apperror.Invalid(typeErr.Field, apperror.FieldType,
+			fmt.Sprintf("%s debe ser de tipo %s", typeErr.Field, typeErr.Type))
+	}
+
+	return apperror.BadRequest(apperror.CodeInvalidJSON, "JSON inválido: "+err.Error())
+}
+
+// fieldError describe en español la regla binding que no se cumplió
+func fieldError(fe validator.FieldError) apperror.FieldError {
+	field := fe.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:] // Sin el nombre de la estructura
+	}
+
+	length := fe.Kind() == reflect.String || fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+	detail := apperror.FieldError{Field: field, Code: apperror.FieldInvalid}
+	switch fe.Tag() {
+	case "required":
+		detail.Code = apperror.FieldRequired
+		detail.Message = field + " es requerido"
+	case "min", "gte":
+		detail.Code = apperror.FieldOutOfRange
+		if length {
+			detail.Message = fmt.Sprintf("%s debe tener al menos %s elementos o caracteres", field, fe.Param())
+		} else {
+			detail.Message = fmt.Sprintf("%s debe ser como mínimo %s", field, fe.Param())
+		}
+	case "max", "lte":
+		detail.Code = apperror.FieldOutOfRange
+		if length {
+			detail.Message = fmt.Sprintf("%s debe tener como máximo %s elementos o caracteres", field, fe.Param())
+		} else {
+			detail.Message = fmt.Sprintf("%s debe ser como máximo %s", field, fe.Param())
+		}
+	case "gt":
+		detail.Code = apperror.FieldOutOfRange
+		detail.Message = fmt.Sprintf("%s debe ser mayor a %s", field, fe.Param())
+	case "email":
+		detail.Message = field + " debe ser un email válido"
+	case "oneof":
+		detail.Message = fmt.Sprintf("%s debe ser uno de: %s", field, fe.Param())
+	default:
+		detail.Message = fmt.Sprintf("%s no cumple la regla %s", field, fe.Tag())
+	}
+	return detail
+}
+
+// jsonFieldName devuelve el nombre JSON del campo (o el de Go si no tiene)
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
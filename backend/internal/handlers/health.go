@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/httpclient"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Estado de cada componente del chequeo de disponibilidad
+const (
+	CheckUp       = "up"
+	CheckDegraded = "degraded" // Funciona con problemas; no saca a la API del balanceo
+	CheckDown     = "down"
+)
+
+// readinessTimeout limita cuánto puede tardar el chequeo de disponibilidad
+const readinessTimeout = 3 * time.Second
+
+// ComponentCheck es el resultado del chequeo de una dependencia. Si una
+// dependencia crítica está caída la API no está lista.
+type ComponentCheck struct {
+	Status    string      `json:"status"`
+	Critical  bool        `json:"critical"`
+	LatencyMs *float64    `json:"latency_ms,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// ReadinessReport es la respuesta de /readyz (solo con el estado de cada
+// componente) y de /admin/diagnostics/readiness (completa)
+type ReadinessReport struct {
+	Status  string                    `json:"status"` // "ok", "degraded" o "unavailable"
+	Service string                    `json:"service"`
+	Checks  map[string]ComponentCheck `json:"checks"`
+}
+
+// poolStats resume sql.DBStats para el chequeo de la base de datos
+type poolStats struct {
+	MaxOpen        int     `json:"max_open"`
+	Open           int     `json:"open"`
+	InUse          int     `json:"in_use"`
+	Idle           int     `json:"idle"`
+	WaitCount      int64   `json:"wait_count"`
+	WaitDurationMs float64 `json:"wait_duration_ms"`
+}
+
+// migrationStats resume el estado de las migraciones para el chequeo
+type migrationStats struct {
+	Pending  int      `json:"pending"`
+	Modified []string `json:"modified,omitempty"` // Cambiaron después de aplicarse
+	Missing  []string `json:"missing,omitempty"`  // Aplicadas pero sin archivo en este binario
+}
+
+// LivezHandler indica que el proceso está vivo. No revisa dependencias: si
+// falla, el orquestador debe reiniciar el contenedor.
+func LivezHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"service": "sintropia-api",
+	})
+}
+
+// HealthHandler responde siempre 200 e informa si la base de datos responde.
+// En modo degradado (sin base de datos) el estado es "degraded".
+func HealthHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	status, database := "ok", CheckUp
+	if err := db.HealthCheck(ctx); err != nil {
+		status, database = "degraded", CheckDown
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":   status,
+		"service":  "sintropia-api",
+		"database": database,
+	})
+}
+
+// ReadyzHandler revisa las dependencias y responde 503 si alguna crítica
+// (base de datos, migraciones) falla, para que el orquestador no envíe tráfico.
+// Las integraciones externas no son críticas: solo degradan el estado. La ruta
+// es pública, así que solo informa el estado de cada componente; el detalle
+// está en /admin/diagnostics/readiness.
+func ReadyzHandler(c *gin.Context) {
+	report, status := checkReadiness(c.Request.Context())
+	c.JSON(status, report.summary())
+}
+
+// GetReadinessDiagnosticsHandler devuelve el chequeo de disponibilidad
+// completo: errores, latencia y pool de la base de datos, migraciones y
+// circuit breakers. Responde 200 aunque la API no esté lista.
+func GetReadinessDiagnosticsHandler(c *gin.Context) {
+	report, _ := checkReadiness(c.Request.Context())
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// checkReadiness revisa cada dependencia y devuelve el reporte con el código
+// HTTP de /readyz
+func checkReadiness(ctx context.Context) (ReadinessReport, int) {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	report := ReadinessReport{
+		Status:  "ok",
+		Service: "sintropia-api",
+		Checks: map[string]ComponentCheck{
+			"database":     checkDatabase(ctx),
+			"migrations":   checkMigrations(ctx),
+			"integrations": checkIntegrations(),
+		},
+	}
+
+	status := http.StatusOK
+	for _, check := range report.Checks {
+		switch {
+		case check.Status == CheckUp:
+		case check.Critical:
+			report.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		case report.Status == "ok":
+			report.Status = "degraded"
+		}
+	}
+	return report, status
+}
+
+// summary deja solo el estado de cada componente, sin errores ni detalles
+// internos
+func (r ReadinessReport) summary() ReadinessReport {
+	checks := make(map[string]ComponentCheck, len(r.Checks))
+	for name, check := range r.Checks {
+		checks[name] = ComponentCheck{Status: check.Status, Critical: check.Critical}
+	}
+	r.Checks = checks
+	return r
+}
+
+// checkDatabase mide la latencia de un ping e informa el uso del pool
+func checkDatabase(ctx context.Context) ComponentCheck {
+	check := ComponentCheck{Status: CheckUp, Critical: true}
+
+	start := time.Now()
+	err := db.HealthCheck(ctx)
+	latency := float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		check.Status = CheckDown
+		check.Error = err.Error()
+		return check
+	}
+	check.LatencyMs = &latency
+
+	if stats, err := db.PoolStats(); err == nil {
+		check.Details = poolStats{
+			MaxOpen:        stats.MaxOpenConnections,
+			Open:           stats.OpenConnections,
+			InUse:          stats.InUse,
+			Idle:           stats.Idle,
+			WaitCount:      stats.WaitCount,
+			WaitDurationMs: float64(stats.WaitDuration.Microseconds()) / 1000,
+		}
+	}
+	return check
+}
+
+// checkMigrations falla si hay migraciones pendientes: el esquema no es el
+// que espera este binario
+func checkMigrations(ctx context.Context) ComponentCheck {
+	check := ComponentCheck{Status: CheckUp, Critical: true}
+	if db.DB == nil {
+		check.Status = CheckDown
+		check.Error = db.ErrNotInitialized.Error()
+		return check
+	}
+
+	migrator, err := db.NewMigrator()
+	if err != nil {
+		check.Status = CheckDown
+		check.Error = err.Error()
+		return check
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		check.Status = CheckDown
+		check.Error = err.Error()
+		return check
+	}
+
+	stats := migrationStats{}
+	for _, s := range statuses {
+		switch {
+		case s.Missing:
+			stats.Missing = append(stats.Missing, s.Name)
+		case s.Modified:
+			stats.Modified = append(stats.Modified, s.Name)
+		case !s.Applied():
+			stats.Pending++
+		}
+	}
+	check.Details = stats
+	if stats.Pending > 0 {
+		check.Status = CheckDown
+		check.Error = "hay migraciones pendientes"
+	}
+	return check
+}
+
+// checkIntegrations informa el circuit breaker de cada host externo. Un
+// circuito abierto degrada el estado pero no saca a la API del balanceo.
+func checkIntegrations() ComponentCheck {
+	check := ComponentCheck{Status: CheckUp}
+
+	breakers := httpclient.Shared().Stats().Breakers
+	for _, breaker := range breakers {
+		if breaker.State != httpclient.BreakerClosed {
+			check.Status = CheckDegraded
+			check.Error = "hay integraciones con el circuito abierto"
+		}
+	}
+	check.Details = breakers
+	return check
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Sin base de datos (db.DB nil) las dependencias críticas están caídas
+func TestReadinessWithoutDatabase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/readyz", ReadyzHandler)
+	router.GET("/diagnostics", GetReadinessDiagnosticsHandler)
+
+	cases := []struct {
+		path       string
+		wantStatus int
+		detailed   bool
+	}{
+		{"/readyz", http.StatusServiceUnavailable, false},
+		{"/diagnostics", http.StatusOK, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if w.Code != tc.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", w.Code, tc.wantStatus)
+			}
+
+			var report ReadinessReport
+			if tc.detailed {
+				var response models.APIResponse
+				response.Data = &report
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("respuesta inválida: %v", err)
+				}
+			} else if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("respuesta inválida: %v", err)
+			}
+
+			if report.Status != "unavailable" {
+				t.Errorf("status = %q, se esperaba unavailable", report.Status)
+			}
+			for _, name := range []string{"database", "migrations"} {
+				check := report.Checks[name]
+				if check.Status != CheckDown || !check.Critical {
+					t.Errorf("%s = %+v, se esperaba down y crítico", name, check)
+				}
+				if hasError := check.Error != ""; hasError != tc.detailed {
+					t.Errorf("%s: error = %q, se esperaba detalle %t", name, check.Error, tc.detailed)
+				}
+			}
+
+			// La ruta pública no expone detalles internos
+			if !tc.detailed {
+				var raw map[string]map[string]map[string]interface{}
+				_ = json.Unmarshal(w.Body.Bytes(), &raw)
+				for name, check := range raw["checks"] {
+					for key := range check {
+						if key != "status" && key != "critical" {
+							t.Errorf("%s expone %q en /readyz", name, key)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/httpclient"
+	"github.com/deibys/sintronia/internal/integrations/permapeople"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/services"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/internal/vault"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var credentialService *services.CredentialService
+
+// getCredentialService obtiene el servicio de credenciales, inicializándolo si es necesario.
+// Sin clave de cifrado el servicio solo usa las credenciales de la configuración.
+func getCredentialService() *services.CredentialService {
+	if credentialService == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+
+		v, err := vault.FromKey(integrations.CredentialsEncryptionKey)
+		if err != nil {
+			log.Printf("⚠️ Vault de credenciales deshabilitado: %v", err)
+		}
+		fallback := map[string]services.Credentials{
+			models.IntegrationPermapeople: {
+				KeyID:     integrations.Permapeople.KeyID,
+				KeySecret: integrations.Permapeople.KeySecret,
+			},
+		}
+		credentialService = services.NewCredentialService(repositories.NewCredentialRepository(), v, fallback)
+	}
+	return credentialService
+}
+
+// newPermapeopleClient crea un cliente que toma las credenciales del servidor en cada
+// solicitud y usa el cliente HTTP compartido (timeouts, reintentos, caché)
+func newPermapeopleClient(creds permapeople.CredentialProvider) *permapeople.Client {
+	return permapeople.NewClient(permapeople.Config{
+		BaseURL:     integrations.Permapeople.BaseURL,
+		KeyID:       integrations.Permapeople.KeyID,
+		KeySecret:   integrations.Permapeople.KeySecret,
+		Credentials: creds,
+		HTTPClient:  httpclient.Shared().Client(),
+	})
+}
+
+// GetIntegrationCredentialsHandler informa si la integración tiene credenciales,
+// su origen y la clave enmascarada. El secreto nunca se devuelve.
+func GetIntegrationCredentialsHandler(c *gin.Context) {
+	svc := getCredentialService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	provider, ok := parseProviderParam(c)
+	if !ok {
+		return
+	}
+
+	info, err := svc.Info(provider)
+	if err != nil {
+		respondCredentialError(c, err, "Error obteniendo credenciales")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    info,
+	})
+}
+
+// RotateIntegrationCredentialsHandler reemplaza las credenciales de una integración.
+// El secreto se guarda cifrado y solo se registra enmascarado.
+func RotateIntegrationCredentialsHandler(c *gin.Context) {
+	svc := getCredentialService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	provider, ok := parseProviderParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.RotateCredentialRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	actor := currentActor(c)
+	info, err := svc.Rotate(provider, req.KeyID, req.KeySecret, actor)
+	if err != nil {
+		respondCredentialError(c, err, "Error guardando credenciales")
+		return
+	}
+
+	log.Printf("🔐 Credenciales de %s rotadas por %s (key id %s)", provider, actor, vault.Mask(req.KeyID))
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    info,
+		Message: "Credenciales actualizadas exitosamente",
+	})
+}
+
+// ProxyPermapeoplePlantsHandler reenvía el listado de plantas de Permapeople usando
+// las credenciales del servidor, para que el navegador no necesite el secreto
+func ProxyPermapeoplePlantsHandler(c *gin.Context) {
+	svc := getCredentialService()
+	if svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	status, body, err := newPermapeopleClient(svc).Forward(c.Request.Context(), "/plants", c.Request.URL.Query())
+	if err != nil {
+		log.Printf("❌ Error llamando a Permapeople: %v", err)
+		respondPermapeopleError(c, err, nil)
+		return
+	}
+
+	c.Data(status, "application/json", body)
+}
+
+// ImportPermapeopleHandler importa el catálogo de Permapeople al catálogo global de plant_species.
+// Con ?dry_run=true solo informa lo que crearía o actualizaría; ?max_pages limita
+// cuántas páginas del catálogo se recorren.
+func ImportPermapeopleHandler(c *gin.Context) {
+	repo := getPlantRepo(c)
+	svc := getCredentialService()
+	if repo == nil || svc == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	opts := permapeople.ImportOptions{}
+
+	if raw := c.Query("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			respondInvalid(c, "dry_run", "dry_run debe ser true o false")
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	if raw := c.Query("max_pages"); raw != "" {
+		maxPages, err := strconv.Atoi(raw)
+		if err != nil || maxPages < 0 {
+			respondInvalid(c, "max_pages", "max_pages debe ser un entero no negativo")
+			return
+		}
+		opts.MaxPages = maxPages
+	}
+
+	// Las especies importadas forman el catálogo global, compartido por todas las organizaciones
+	importer := permapeople.NewImporter(newPermapeopleClient(svc), repo.ForTenant(tenant.All()))
+	result, err := importer.Run(c.Request.Context(), opts)
+	if err != nil {
+		log.Printf("❌ Error importando catálogo de Permapeople: %v", err)
+		respondPermapeopleError(c, err, result)
+		return
+	}
+
+	message := "Catálogo de Permapeople importado exitosamente"
+	if opts.DryRun {
+		message = "Simulación de importación completada, no se guardaron cambios"
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    result,
+		Message: message,
+	})
+}
+
+// parseProviderParam valida el proveedor de la ruta. Si es desconocido responde 404.
+func parseProviderParam(c *gin.Context) (string, bool) {
+	provider := c.Param("provider")
+	if !models.IsValidIntegrationProvider(provider) {
+		respondError(c, apperror.NotFound("integration_not_found", "Integración no encontrada"), "Error obteniendo integración")
+		return "", false
+	}
+	return provider, true
+}
+
+// respondPermapeopleError traduce los errores de la integración a respuestas HTTP.
+// Responde directamente, sin respondError, porque data permite devolver el
+// resultado parcial de una importación.
+func respondPermapeopleError(c *gin.Context, err error, data interface{}) {
+	status := http.StatusBadGateway
+	code := "upstream_error"
+	message := "Error llamando a Permapeople"
+
+	switch {
+	case errors.Is(err, httpclient.ErrCircuitOpen):
+		status = http.StatusServiceUnavailable
+		code = apperror.CodeUnavailable
+		message = "Permapeople no disponible temporalmente"
+	case errors.Is(err, permapeople.ErrNoCredentials),
+		errors.Is(err, permapeople.ErrUnauthorized),
+		errors.Is(err, vault.ErrKeyNotConfigured),
+		errors.Is(err, vault.ErrDecrypt):
+		status = http.StatusServiceUnavailable
+		code = "integration_unavailable"
+		message = "Integración con Permapeople no disponible"
+	}
+
+	c.JSON(status, models.APIResponse{
+		Success: false,
+		Data:    data,
+		Error:   message + ": " + err.Error(),
+		Code:    code,
+	})
+}
+
+// respondCredentialError traduce los errores del vault de credenciales a respuestas HTTP
+func respondCredentialError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, vault.ErrKeyNotConfigured) {
+		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+			Success: false,
+			Error:   err.Error(),
+			Code:    apperror.CodeUnavailable,
+		})
+		return
+	}
+
+	respondError(c, err, fallback)
+}
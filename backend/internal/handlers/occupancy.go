@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/services"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPlotOccupancyHandler compara la cobertura de copa de cada estrato de una
+// parcela con la ocupación objetivo del diseño sintrópico
+func GetPlotOccupancyHandler(c *gin.Context) {
+	repo := getPlotRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	plot, err := repo.GetWithInstances(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo parcela de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    services.AnalyzePlotOccupancy(plot),
+	})
+}
+
+// GetPlantationOccupancyHandler agrega la ocupación por estrato de todas las
+// parcelas de una plantación
+func GetPlantationOccupancyHandler(c *gin.Context) {
+	repo := getPlantationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	plantation, err := repo.GetWithLayout(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantación de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    services.AnalyzePlantationOccupancy(plantation),
+	})
+}
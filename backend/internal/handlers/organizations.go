@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var organizationRepo *repositories.OrganizationRepository
+
+// getOrganizationRepo obtiene el repository con el contexto de la solicitud,
+// inicializándolo si es necesario
+func getOrganizationRepo(c *gin.Context) *repositories.OrganizationRepository {
+	if organizationRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		organizationRepo = repositories.NewOrganizationRepository()
+	}
+	return organizationRepo.WithContext(requestContext(c))
+}
+
+// GetOrganizationsHandler lista las organizaciones del usuario con su rol en cada una
+func GetOrganizationsHandler(c *gin.Context) {
+	repo := getOrganizationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	orgs, err := repo.GetForUser(c.GetUint("user_id"))
+	if err != nil {
+		respondOrganizationError(c, err, "Error obteniendo organizaciones")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    orgs,
+	})
+}
+
+// CreateOrganizationHandler crea una organización con el usuario como owner
+func CreateOrganizationHandler(c *gin.Context) {
+	repo := getOrganizationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var req models.CreateOrganizationRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	org := models.Organization{Name: req.Name, Slug: req.Slug}
+	userID := c.GetUint("user_id")
+	if err := repo.Create(&org, userID); err != nil {
+		respondOrganizationError(c, err, "Error creando organización")
+		return
+	}
+	org.Role = models.OrganizationRoleOwner
+
+	log.Printf("🏢 Organización creada: %s (ID: %d) por %d", org.Name, org.ID, userID)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    org,
+		Message: "Organización creada exitosamente",
+	})
+}
+
+// GetOrganizationMembersHandler lista los miembros de la organización
+func GetOrganizationMembersHandler(c *gin.Context) {
+	repo := getOrganizationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	orgID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if !requireOrganizationRole(c, repo, orgID, false) {
+		return
+	}
+
+	members, err := repo.GetMembers(orgID)
+	if err != nil {
+		respondOrganizationError(c, err, "Error obteniendo miembros de la organización")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    members,
+	})
+}
+
+// AddOrganizationMemberHandler agrega un usuario registrado a la organización (solo owners)
+func AddOrganizationMemberHandler(c *gin.Context) {
+	repo := getOrganizationRepo(c)
+	users := getUserRepo()
+	if repo == nil || users == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	orgID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if !requireOrganizationRole(c, repo, orgID, true) {
+		return
+	}
+
+	var req models.AddOrganizationMemberRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if !models.IsValidOrganizationRole(req.Role) {
+		respondInvalid(c, "role", "rol de organización inválido")
+		return
+	}
+
+	user, err := users.GetByEmail(req.Email)
+	if err != nil {
+		respondOrganizationError(c, err, "Error obteniendo usuario")
+		return
+	}
+
+	member := models.OrganizationMember{
+		OrganizationID: orgID,
+		UserID:         user.ID,
+		Role:           req.Role,
+	}
+	if err := repo.AddMember(&member); err != nil {
+		respondOrganizationError(c, err, "Error agregando miembro de la organización")
+		return
+	}
+
+	log.Printf("🏢 Usuario %d agregado a la organización %d como %s por %d", user.ID, orgID, req.Role, c.GetUint("user_id"))
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    member,
+		Message: "Miembro agregado exitosamente",
+	})
+}
+
+// RemoveOrganizationMemberHandler quita a un miembro de la organización y de sus
+// sitios (solo owners)
+func RemoveOrganizationMemberHandler(c *gin.Context) {
+	repo := getOrganizationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	orgID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	if !requireOrganizationRole(c, repo, orgID, true) {
+		return
+	}
+
+	if err := repo.RemoveMember(orgID, userID); err != nil {
+		respondOrganizationError(c, err, "Error quitando miembro de la organización")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Miembro quitado exitosamente",
+	})
+}
+
+// requireOrganizationRole verifica que el usuario pertenezca a la organización y,
+// con ownerOnly, que sea owner. Los administradores globales actúan como owner.
+// Si no, responde y devuelve false.
+func requireOrganizationRole(c *gin.Context, repo *repositories.OrganizationRepository, orgID uint, ownerOnly bool) bool {
+	if isAdmin(c) {
+		if _, err := repo.GetByID(orgID); err != nil {
+			respondOrganizationError(c, err, "Error obteniendo organización")
+			return false
+		}
+		return true
+	}
+
+	role, err := repo.GetRole(orgID, c.GetUint("user_id"))
+	if err != nil {
+		// Quien no es miembro no distingue una organización ajena de una inexistente
+		if errors.Is(err, repositories.ErrOrganizationMemberNotFound) {
+			err = repositories.ErrOrganizationNotFound
+		}
+		respondOrganizationError(c, err, "Error obteniendo organización")
+		return false
+	}
+
+	if ownerOnly && role != models.OrganizationRoleOwner {
+		respondError(c, apperror.Forbidden("organization_owner_required",
+			"Acceso denegado. Solo los owners de la organización pueden gestionar sus miembros"), "Error verificando permisos")
+		return false
+	}
+	return true
+}
+
+// respondOrganizationError traduce los errores de organizaciones a respuestas HTTP
+func respondOrganizationError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		err = apperror.NotFound("user_not_found", "No hay un usuario registrado con ese email")
+	}
+	respondError(c, err, fallback)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// parsePagination lee los parámetros page y limit de la query aplicando
+// los valores por defecto y el límite máximo según el rol del usuario
+func parsePagination(c *gin.Context) (page int, limit int) {
+	page = 1
+	limit = 10
+
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	if l := c.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			limit = parsed
+
+			// Límite flexible basado en rol de usuario o configuración
+			maxLimit := getMaxPaginationLimit(c)
+			if limit > maxLimit {
+				limit = maxLimit
+			}
+		}
+	}
+
+	return page, limit
+}
+
+// newPagination construye los metadatos de paginación para la respuesta
+func newPagination(page, limit int, total int64) models.Pagination {
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+
+	return models.Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var plantInstanceRepo *repositories.PlantInstanceRepository
+
+// getPlantInstanceRepo obtiene el repository limitado a la organización de la solicitud,
+// inicializándolo si es necesario
+func getPlantInstanceRepo(c *gin.Context) *repositories.PlantInstanceRepository {
+	if plantInstanceRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		plantInstanceRepo = repositories.NewPlantInstanceRepository()
+	}
+	return plantInstanceRepo.WithContext(requestContext(c)).ForTenant(requestTenant(c))
+}
+
+// CreatePlantInstanceHandler maneja la creación de instancias de plantas en una parcela
+func CreatePlantInstanceHandler(c *gin.Context) {
+	repo := getPlantInstanceRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	plotID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.CreatePlantInstanceRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Las instancias nuevas empiezan antes o al momento de plantarse: los
+	// estados posteriores se alcanzan con transiciones
+	status := models.PlantStatusPlanned
+	if req.Status != "" {
+		normalized, ok := models.NormalizePlantStatus(req.Status)
+		if !ok {
+			respondInvalid(c, "status", "estado inválido")
+			return
+		}
+		status = normalized
+	}
+	if !models.IsValidInitialPlantStatus(status) {
+		respondInvalid(c, "status", "Estado inicial inválido: una instancia nueva debe estar planned, germinated o planted")
+		return
+	}
+
+	now := time.Now().UTC()
+	instance := models.PlantInstance{
+		PlotID:          plotID,
+		SpeciesID:       req.SpeciesID,
+		Quantity:        req.Quantity,
+		Role:            req.Role,
+		Status:          status,
+		Position:        req.Position,
+		Order:           req.Order,
+		PlantedAt:       req.PlantedAt,
+		Notes:           req.Notes,
+		StatusChangedAt: &now,
+		StatusChangedBy: currentActor(c),
+	}
+
+	// Las instancias que se registran ya plantadas quedan con fecha de plantación
+	if models.IsPlantedStatus(instance.Status) && instance.PlantedAt == nil {
+		instance.PlantedAt = &now
+	}
+
+	// Validar
+	if err := instance.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	if err := repo.Create(&instance); err != nil {
+		respondPlantInstanceError(c, err, "Error guardando instancia de planta en base de datos")
+		return
+	}
+
+	log.Printf("Instancia de planta creada exitosamente (ID: %d, parcela: %d, especie: %d)", instance.ID, instance.PlotID, instance.SpeciesID)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    instance,
+		Message: "Instancia de planta creada exitosamente",
+	})
+}
+
+// GetPlotInstancesHandler maneja la obtención de las instancias de una parcela
+func GetPlotInstancesHandler(c *gin.Context) {
+	repo := getPlantInstanceRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	plotID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	filters := repositories.PlantInstanceFilters{
+		Role: c.Query("role"),
+	}
+
+	if v := c.Query("status"); v != "" {
+		status, ok := models.NormalizePlantStatus(v)
+		if !ok {
+			respondInvalid(c, "status", "estado inválido")
+			return
+		}
+		filters.Status = status
+	}
+
+	if v := c.Query("species_id"); v != "" {
+		speciesID, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			respondInvalid(c, "species_id", "species_id inválido")
+			return
+		}
+		filters.SpeciesID = uint(speciesID)
+	}
+
+	page, limit := parsePagination(c)
+	filters.Limit = limit
+	filters.Offset = (page - 1) * limit
+
+	instances, total, err := repo.GetByPlot(plotID, filters)
+	if err != nil {
+		respondPlantInstanceError(c, err, "Error obteniendo instancias de plantas de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       instances,
+		Pagination: newPagination(page, limit, total),
+	})
+}
+
+// GetPlantInstanceHandler maneja la obtención de una instancia específica
+func GetPlantInstanceHandler(c *gin.Context) {
+	repo := getPlantInstanceRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	instance, err := repo.GetByID(id)
+	if err != nil {
+		respondPlantInstanceError(c, err, "Error obteniendo instancia de planta de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    instance,
+	})
+}
+
+// UpdatePlantInstanceHandler maneja la actualización de una instancia.
+// El estado no se modifica aquí: se usa POST /instances/:id/transitions.
+func UpdatePlantInstanceHandler(c *gin.Context) {
+	repo := getPlantInstanceRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePlantInstanceRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	instance, err := repo.GetByID(id)
+	if err != nil {
+		respondPlantInstanceError(c, err, "Error obteniendo instancia de planta de la base de datos")
+		return
+	}
+
+	// Construir mapa de actualizaciones
+	updates := make(map[string]interface{})
+
+	if req.Quantity != nil {
+		instance.Quantity = *req.Quantity
+		updates["quantity"] = *req.Quantity
+	}
+	if req.Role != nil {
+		instance.Role = *req.Role
+		updates["role"] = *req.Role
+	}
+	if req.Position != nil {
+		instance.Position = *req.Position
+		updates["position"] = *req.Position
+	}
+	if req.Order != nil {
+		instance.Order = *req.Order
+		updates["order"] = *req.Order
+	}
+	if req.Notes != nil {
+		instance.Notes = *req.Notes
+		updates["notes"] = *req.Notes
+	}
+
+	// Validar antes de guardar
+	if err := instance.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	updated, err := repo.Update(id, updates)
+	if err != nil {
+		respondPlantInstanceError(c, err, "Error actualizando instancia de planta en la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    updated,
+		Message: "Instancia de planta actualizada exitosamente",
+	})
+}
+
+// TransitionPlantInstanceHandler maneja los cambios de estado de una instancia
+func TransitionPlantInstanceHandler(c *gin.Context) {
+	repo := getPlantInstanceRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.PlantInstanceTransitionRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	status, ok := models.NormalizePlantStatus(req.Status)
+	if !ok {
+		respondInvalid(c, "status", "estado inválido")
+		return
+	}
+
+	at := time.Now().UTC()
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(at) {
+			message := "la fecha de la transición no puede estar en el futuro"
+			respondError(c, apperror.Invalid("occurred_at", apperror.FieldOutOfRange, message), message)
+			return
+		}
+		at = req.OccurredAt.UTC()
+	}
+
+	actor := currentActor(c)
+
+	instance, err := repo.Transition(id, status, actor, req.Notes, at)
+	if err != nil {
+		respondPlantInstanceError(c, err, "Error actualizando estado de la instancia de planta")
+		return
+	}
+
+	log.Printf("Instancia %d cambió a estado %s (por: %s)", instance.ID, instance.Status, actor)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    instance,
+		Message: "Estado actualizado exitosamente",
+	})
+}
+
+// GetPlantInstanceTimelineHandler devuelve la historia de estados de una instancia
+// con el tiempo que pasó en cada uno
+func GetPlantInstanceTimelineHandler(c *gin.Context) {
+	repo := getPlantInstanceRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	instance, err := repo.GetByID(id)
+	if err != nil {
+		respondPlantInstanceError(c, err, "Error obteniendo instancia de planta de la base de datos")
+		return
+	}
+
+	events, err := repo.Events(id)
+	if err != nil {
+		respondPlantInstanceError(c, err, "Error obteniendo historia de estados de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    models.BuildPlantInstanceTimeline(*instance, events, time.Now().UTC()),
+	})
+}
+
+// DeletePlantInstanceHandler maneja la eliminación de una instancia de planta
+func DeletePlantInstanceHandler(c *gin.Context) {
+	repo := getPlantInstanceRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := repo.Delete(id); err != nil {
+		respondPlantInstanceError(c, err, "Error eliminando instancia de planta de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Instancia de planta eliminada exitosamente",
+	})
+}
+
+// respondPlantInstanceError traduce los errores del repositorio de instancias a respuestas HTTP
+func respondPlantInstanceError(c *gin.Context, err error, fallback string) {
+	// La especie es un dato de la solicitud: no es la instancia la que falta
+	if errors.Is(err, repositories.ErrSpeciesNotFound) {
+		err = apperror.Invalid("species_id", apperror.FieldInvalid, "La especie especificada no existe")
+	}
+	respondError(c, err, fallback)
+}
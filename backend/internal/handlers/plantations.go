@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var plantationRepo *repositories.PlantationRepository
+
+// getPlantationRepo obtiene el repository limitado a la organización de la solicitud,
+// inicializándolo si es necesario
+func getPlantationRepo(c *gin.Context) *repositories.PlantationRepository {
+	if plantationRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		plantationRepo = repositories.NewPlantationRepository()
+	}
+	return plantationRepo.WithContext(requestContext(c)).ForTenant(requestTenant(c))
+}
+
+// CreatePlantationHandler maneja la creación de plantaciones dentro de un sitio
+func CreatePlantationHandler(c *gin.Context) {
+	repo := getPlantationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	siteID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.CreatePlantationRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	plantation := models.Plantation{
+		SiteID: siteID,
+		Name:   req.Name,
+		AreaM2: req.AreaM2,
+		Notes:  req.Notes,
+	}
+
+	// Validar
+	if err := plantation.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	if err := repo.Create(&plantation); err != nil {
+		respondError(c, err, "Error guardando plantación en base de datos")
+		return
+	}
+
+	log.Printf("Plantación creada exitosamente: %s (ID: %d, sitio: %d)", plantation.Name, plantation.ID, plantation.SiteID)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    plantation,
+		Message: "Plantación creada exitosamente",
+	})
+}
+
+// GetSitePlantationsHandler maneja la obtención de las plantaciones de un sitio
+func GetSitePlantationsHandler(c *gin.Context) {
+	repo := getPlantationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	siteID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	filters := repositories.PlantationFilters{
+		Search: c.Query("search"),
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	plantations, total, err := repo.GetBySite(siteID, filters)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantaciones de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       plantations,
+		Pagination: newPagination(page, limit, total),
+	})
+}
+
+// GetPlantationHandler maneja la obtención de una plantación específica
+func GetPlantationHandler(c *gin.Context) {
+	repo := getPlantationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	plantation, err := repo.GetByID(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantación de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    plantation,
+	})
+}
+
+// UpdatePlantationHandler maneja la actualización de una plantación
+func UpdatePlantationHandler(c *gin.Context) {
+	repo := getPlantationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePlantationRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	plantation, err := repo.GetByID(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantación de la base de datos")
+		return
+	}
+
+	// Construir mapa de actualizaciones
+	updates := make(map[string]interface{})
+
+	if req.Name != nil {
+		plantation.Name = *req.Name
+		updates["name"] = *req.Name
+	}
+	if req.AreaM2 != nil {
+		plantation.AreaM2 = *req.AreaM2
+		updates["area_m2"] = *req.AreaM2
+	}
+	if req.Notes != nil {
+		plantation.Notes = *req.Notes
+		updates["notes"] = *req.Notes
+	}
+
+	// Validar antes de guardar
+	if err := plantation.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	updated, err := repo.Update(id, updates)
+	if err != nil {
+		respondError(c, err, "Error actualizando plantación en la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    updated,
+		Message: "Plantación actualizada exitosamente",
+	})
+}
+
+// DeletePlantationHandler maneja la eliminación de una plantación y sus hijos
+func DeletePlantationHandler(c *gin.Context) {
+	repo := getPlantationRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := repo.Delete(id); err != nil {
+		respondError(c, err, "Error eliminando plantación de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Plantación eliminada exitosamente",
+	})
+}
@@ -3,11 +3,13 @@ package handlers
 import (
 	"log"
 	"net/http"
-	"os"
 	"strconv"
+	"strings"
 
 	"github.com/deibys/sintronia/internal/db"
 	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
 	"github.com/deibys/sintronia/pkg/models"
 	"github.com/gin-gonic/gin"
 )
@@ -19,35 +21,32 @@ func init() {
 	// Este init se ejecutará después de que main() inicialice la DB
 }
 
-// getPlantRepo obtiene el repository, inicializándolo si es necesario
-func getPlantRepo() *repositories.PlantRepository {
+// getPlantRepo obtiene el repository limitado a la organización de la solicitud,
+// inicializándolo si es necesario
+func getPlantRepo(c *gin.Context) *repositories.PlantRepository {
 	if plantRepo == nil {
 		if db.DB == nil {
 			return nil // DB no disponible
 		}
 		plantRepo = repositories.NewPlantRepository()
 	}
-	return plantRepo
+	return plantRepo.WithContext(requestContext(c)).ForTenant(requestTenant(c))
 }
 
 // CreatePlantSpeciesHandler maneja la creación de especies de plantas
 func CreatePlantSpeciesHandler(c *gin.Context) {
 
-	// Obtén el repositorio a través de getPlantRepo()
-	repo := getPlantRepo()
+	// Obtén el repositorio a través de getPlantRepo(c)
+	repo := getPlantRepo(c)
 	if repo == nil {
-		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
-			Success: false,
-			Error:   "Base de datos no disponible",
-			Message: "El servicio está funcionando en modo limitado",
-		})
+		respondDatabaseUnavailable(c)
 		return
 	}
 
 	// Obtener información del usuario autenticado con verificación
 	userID, exists := c.Get("user_id")
 	if exists && userID != nil {
-		c.Header("X-User-ID", strconv.FormatUint(uint64(userID.(int64)), 10))
+		c.Header("X-User-ID", strconv.FormatUint(uint64(userID.(uint)), 10))
 		log.Printf("Usuario %v creando especie", userID)
 	}
 
@@ -58,29 +57,32 @@ func CreatePlantSpeciesHandler(c *gin.Context) {
 
 	var req models.CreatePlantSpeciesRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   "JSON inválido: " + err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Las especies nuevas son privadas de la organización; los administradores
+	// pueden agregarlas al catálogo global con ?global=true
+	if c.Query("global") == "true" {
+		if !isAdmin(c) {
+			respondError(c, apperror.Forbidden("global_species_admin_only",
+				"Solo los administradores pueden agregar especies al catálogo global"), "Error verificando permisos")
+			return
+		}
+		repo = repo.ForTenant(tenant.All())
+	} else if !requireOrganization(c) {
 		return
 	}
 
 	// Verificar si external_id ya existe (si se proporciona)
 	if req.ExternalRef != "" {
-		exists, err := plantRepo.ExistsByExternalRef(req.ExternalRef)
+		exists, err := repo.ExistsByExternalRef(req.ExternalRef)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Success: false,
-				Error:   "Error verificando external_ref: " + err.Error(),
-			})
+			respondError(c, err, "Error verificando external_ref")
 			return
 		}
 		if exists {
-			c.JSON(http.StatusConflict, models.APIResponse{
-				Success: false,
-				Error:   "Ya existe una planta con ese external_ref",
-			})
+			respondError(c, repositories.ErrSpeciesExternalRefTaken, "Error verificando external_ref")
 			return
 		}
 	}
@@ -90,28 +92,33 @@ func CreatePlantSpeciesHandler(c *gin.Context) {
 		CommonName:      req.CommonName,
 		ScientificName:  req.ScientificName,
 		Stratum:         req.Stratum,
-		FunctionEcol:    req.FunctionEcol,
 		SuccessionStage: req.SuccessionStage,
 		ExternalRef:     req.ExternalRef,
 		Notes:           req.Notes,
+
+		MatureHeightM:       req.MatureHeightM,
+		CanopyDiameterM:     req.CanopyDiameterM,
+		SpacingM:            req.SpacingM,
+		LifespanYears:       req.LifespanYears,
+		TimeToHarvestMonths: req.TimeToHarvestMonths,
+		LightRequirement:    req.LightRequirement,
+		RootDepth:           req.RootDepth,
+		WaterNeeds:          req.WaterNeeds,
+		FrostTolerance:      req.FrostTolerance,
 	}
 
+	plant.SetFunctions(resolveSpeciesFunctions(req.Functions, req.PrimaryFunction, req.FunctionEcol))
+
 	// Validar
 	if err := plant.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondError(c, err, "Datos inválidos")
 		return
 	}
 
-	// Guardar en base de datos
-	if err := plantRepo.Create(&plant); err != nil {
-		log.Printf("Error creando planta: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Error:   "Error guardando planta en base de datos",
-		})
+	// Guardar en base de datos. Si otra solicitud creó el mismo external_ref
+	// después de la verificación, el repositorio devuelve un conflicto.
+	if err := repo.Create(&plant); err != nil {
+		respondError(c, err, "Error guardando planta en base de datos")
 		return
 	}
 
@@ -128,105 +135,71 @@ func CreatePlantSpeciesHandler(c *gin.Context) {
 // GetPlantsSpeciesHandler maneja la obtención de todas las plantas
 func GetPlantsSpeciesHandler(c *gin.Context) {
 	// Verificar que la base de datos esté disponible
-	repo := getPlantRepo()
+	repo := getPlantRepo(c)
 	if repo == nil {
-		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
-			Success: false,
-			Error:   "Base de datos no disponible",
-			Message: "El servicio está funcionando en modo limitado",
-		})
+		respondDatabaseUnavailable(c)
 		return
 	}
 
-	// Parámetros de paginación mejorados
-	page := 1
-	limit := 10
-
-	if p := c.Query("page"); p != "" {
-		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
-			page = parsed
-		}
-	}
-
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
-			limit = parsed
-
-			// Límite flexible basado en rol de usuario o configuración
-			maxLimit := getMaxPaginationLimit(c)
-			if limit > maxLimit {
-				limit = maxLimit
-			}
-		}
-	}
+	// Parámetros de paginación
+	page, limit := parsePagination(c)
 
 	// Construir filtros
 	filters := repositories.PlantFilters{
 		Search:          c.Query("search"),
 		Stratum:         c.Query("stratum"),
-		FunctionEcol:    c.Query("function_ecol"),
+		Functions:       parseListQuery(c, "function_ecol"),
+		FunctionMatch:   c.DefaultQuery("function_match", repositories.FunctionMatchAny),
 		SuccessionStage: c.Query("succession_stage"),
 		Limit:           limit,
 		Offset:          (page - 1) * limit,
+
+		LightRequirement: c.Query("light"),
+		RootDepth:        c.Query("root_depth"),
+		WaterNeeds:       c.Query("water_needs"),
+		FrostTolerance:   c.Query("frost_tolerance"),
 	}
 
-	// Obtener plantas de la base de datos
-	plants, total, err := repo.GetAll(filters)
-	if err != nil {
-		log.Printf("Error obteniendo plantas: %v", err)
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Error:   "Error obteniendo plantas de la base de datos",
-		})
+	if filters.FunctionMatch != repositories.FunctionMatchAny && filters.FunctionMatch != repositories.FunctionMatchAll {
+		respondInvalid(c, "function_match", "function_match debe ser 'any' o 'all'")
 		return
 	}
 
-	// Calcular total de páginas
-	totalPages := int(total) / limit
-	if int(total)%limit != 0 {
-		totalPages++
+	if !parseTraitRangeFilters(c, &filters) {
+		return
 	}
 
-	pagination := models.Pagination{
-		Page:       page,
-		Limit:      limit,
-		Total:      total,
-		TotalPages: totalPages,
+	// Obtener plantas de la base de datos
+	plants, total, err := repo.GetAll(filters)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantas de la base de datos")
+		return
 	}
 
 	c.JSON(http.StatusOK, models.PaginatedResponse{
 		Success:    true,
 		Data:       plants,
-		Pagination: pagination,
+		Pagination: newPagination(page, limit, total),
 	})
 }
 
 // GetPlantSpeciesHandler maneja la obtención de una planta específica
 func GetPlantSpeciesHandler(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   "ID inválido",
-		})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	repo := getPlantRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
 		return
 	}
 
 	// Buscar planta en base de datos
-	plant, err := plantRepo.GetByID(uint(id))
+	plant, err := repo.GetByID(id)
 	if err != nil {
-		if err.Error() == "planta no encontrada" {
-			c.JSON(http.StatusNotFound, models.APIResponse{
-				Success: false,
-				Error:   "Planta no encontrada",
-			})
-		} else {
-			log.Printf("Error obteniendo planta: %v", err)
-			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Success: false,
-				Error:   "Error obteniendo planta de la base de datos",
-			})
-		}
+		respondError(c, err, "Error obteniendo planta de la base de datos")
 		return
 	}
 
@@ -241,7 +214,7 @@ func UpdatePlantSpeciesHandler(c *gin.Context) {
 	// Obtener información del usuario autenticado con verificación
 	userID, exists := c.Get("user_id")
 	if exists && userID != nil {
-		c.Header("X-User-ID", strconv.FormatUint(uint64(userID.(int64)), 10))
+		c.Header("X-User-ID", strconv.FormatUint(uint64(userID.(uint)), 10))
 	}
 
 	userRole, exists := c.Get("user_role")
@@ -249,22 +222,27 @@ func UpdatePlantSpeciesHandler(c *gin.Context) {
 		c.Header("X-User-Role", userRole.(string))
 	}
 
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   "ID inválido",
-		})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
 		return
 	}
 
 	var req models.UpdatePlantSpeciesRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   "JSON inválido: " + err.Error(),
-		})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	repo := getPlantRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	// Obtener la planta actual para validar el resultado antes de guardar
+	plant, err := repo.GetByID(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo planta de la base de datos")
 		return
 	}
 
@@ -272,26 +250,80 @@ func UpdatePlantSpeciesHandler(c *gin.Context) {
 	updates := make(map[string]interface{})
 
 	if req.CommonName != nil {
+		plant.CommonName = *req.CommonName
 		updates["common_name"] = *req.CommonName
 	}
 	if req.ScientificName != nil {
+		plant.ScientificName = *req.ScientificName
 		updates["scientific_name"] = *req.ScientificName
 	}
 	if req.Stratum != nil {
+		plant.Stratum = *req.Stratum
 		updates["stratum"] = *req.Stratum
 	}
-	if req.FunctionEcol != nil {
-		updates["function_ecol"] = *req.FunctionEcol
-	}
 	if req.SuccessionStage != nil {
+		plant.SuccessionStage = *req.SuccessionStage
 		updates["succession_stage"] = *req.SuccessionStage
 	}
 	if req.ExternalRef != nil {
+		plant.ExternalRef = *req.ExternalRef
 		updates["external_ref"] = *req.ExternalRef
 	}
 	if req.Notes != nil {
+		plant.Notes = *req.Notes
 		updates["notes"] = *req.Notes
 	}
+	if req.MatureHeightM != nil {
+		plant.MatureHeightM = req.MatureHeightM
+		updates["mature_height_m"] = *req.MatureHeightM
+	}
+	if req.CanopyDiameterM != nil {
+		plant.CanopyDiameterM = req.CanopyDiameterM
+		updates["canopy_diameter_m"] = *req.CanopyDiameterM
+	}
+	if req.SpacingM != nil {
+		plant.SpacingM = req.SpacingM
+		updates["spacing_m"] = *req.SpacingM
+	}
+	if req.LifespanYears != nil {
+		plant.LifespanYears = req.LifespanYears
+		updates["lifespan_years"] = *req.LifespanYears
+	}
+	if req.TimeToHarvestMonths != nil {
+		plant.TimeToHarvestMonths = req.TimeToHarvestMonths
+		updates["time_to_harvest_months"] = *req.TimeToHarvestMonths
+	}
+	if req.LightRequirement != nil {
+		plant.LightRequirement = *req.LightRequirement
+		updates["light_requirement"] = *req.LightRequirement
+	}
+	if req.RootDepth != nil {
+		plant.RootDepth = *req.RootDepth
+		updates["root_depth"] = *req.RootDepth
+	}
+	if req.WaterNeeds != nil {
+		plant.WaterNeeds = *req.WaterNeeds
+		updates["water_needs"] = *req.WaterNeeds
+	}
+	if req.FrostTolerance != nil {
+		plant.FrostTolerance = *req.FrostTolerance
+		updates["frost_tolerance"] = *req.FrostTolerance
+	}
+
+	// Funciones ecológicas: se reemplaza el conjunto completo si cambia alguna
+	var functions []models.SpeciesFunction
+	if req.Functions != nil || req.PrimaryFunction != nil || req.FunctionEcol != nil {
+		names, primary := mergeSpeciesFunctions(plant, req)
+		plant.SetFunctions(names, primary)
+		functions = plant.Functions
+		updates["function_ecol"] = plant.FunctionEcol
+	}
+
+	// Validar antes de guardar
+	if err := plant.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
 
 	// Log de la actualización
 	if userID != nil {
@@ -299,29 +331,9 @@ func UpdatePlantSpeciesHandler(c *gin.Context) {
 	}
 
 	// Actualizar en base de datos
-	plant, err := plantRepo.Update(uint(id), updates)
+	plant, err = speciesWriteRepo(c, repo, plant).Update(id, updates, functions)
 	if err != nil {
-		if err.Error() == "planta no encontrada" {
-			c.JSON(http.StatusNotFound, models.APIResponse{
-				Success: false,
-				Error:   "Planta no encontrada",
-			})
-		} else {
-			log.Printf("Error actualizando planta: %v", err)
-			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Success: false,
-				Error:   "Error actualizando planta en la base de datos",
-			})
-		}
-		return
-	}
-
-	// Validar después de la actualización
-	if err := plant.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   err.Error(),
-		})
+		respondError(c, err, "Error actualizando planta en la base de datos")
 		return
 	}
 
@@ -337,7 +349,7 @@ func DeletePlantSpeciesHandler(c *gin.Context) {
 	// Obtener información del usuario autenticado con verificación
 	userID, exists := c.Get("user_id")
 	if exists && userID != nil {
-		c.Header("X-User-ID", strconv.FormatUint(uint64(userID.(int64)), 10))
+		c.Header("X-User-ID", strconv.FormatUint(uint64(userID.(uint)), 10))
 	}
 
 	userRole, exists := c.Get("user_role")
@@ -345,12 +357,14 @@ func DeletePlantSpeciesHandler(c *gin.Context) {
 		c.Header("X-User-Role", userRole.(string))
 	}
 
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Error:   "ID inválido",
-		})
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	repo := getPlantRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
 		return
 	}
 
@@ -360,24 +374,12 @@ func DeletePlantSpeciesHandler(c *gin.Context) {
 	}
 
 	// Eliminar de base de datos
-	if err := plantRepo.Delete(uint(id)); err != nil {
-		if err.Error() == "planta no encontrada" {
-			c.JSON(http.StatusNotFound, models.APIResponse{
-				Success: false,
-				Error:   "Planta no encontrada",
-			})
-		} else if err.Error() != "" && err.Error()[:50] == "no se puede eliminar la planta porque está siendo" {
-			c.JSON(http.StatusConflict, models.APIResponse{
-				Success: false,
-				Error:   err.Error(),
-			})
-		} else {
-			log.Printf("Error eliminando planta: %v", err)
-			c.JSON(http.StatusInternalServerError, models.APIResponse{
-				Success: false,
-				Error:   "Error eliminando planta de la base de datos",
-			})
-		}
+	plant, err := repo.GetByID(id)
+	if err == nil {
+		err = speciesWriteRepo(c, repo, plant).Delete(id)
+	}
+	if err != nil {
+		respondError(c, err, "Error eliminando planta de la base de datos")
 		return
 	}
 
@@ -387,31 +389,146 @@ func DeletePlantSpeciesHandler(c *gin.Context) {
 	})
 }
 
-// getMaxPaginationLimit determina el límite máximo de paginación basado en el rol del usuario
-func getMaxPaginationLimit(c *gin.Context) int {
-	// Obtener rol del usuario
-	userRole, exists := c.Get("user_role")
-	if exists && userRole != nil {
-		role := userRole.(string)
-
-		// Los administradores pueden tener límites más altos
-		if role == "admin" {
-			// Verificar si hay un límite configurado por variable de entorno
-			if envLimit := os.Getenv("ADMIN_MAX_PAGINATION_LIMIT"); envLimit != "" {
-				if limit, err := strconv.Atoi(envLimit); err == nil && limit > 0 {
-					return limit
-				}
+// OverridePlantSpeciesHandler crea para la organización una copia privada de una
+// especie del catálogo global, que la reemplaza en sus listados y se puede modificar
+func OverridePlantSpeciesHandler(c *gin.Context) {
+	repo := getPlantRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if !requireOrganization(c) {
+		return
+	}
+
+	plant, err := repo.Override(id)
+	if err != nil {
+		respondError(c, err, "Error creando override de la planta")
+		return
+	}
+
+	log.Printf("Override de planta creado: %s (ID: %d, reemplaza a %d)", plant.CommonName, plant.ID, id)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    plant,
+		Message: "Override creado exitosamente",
+	})
+}
+
+// speciesWriteRepo devuelve el repositorio con el que se modifica la especie:
+// solo los administradores globales modifican el catálogo global
+func speciesWriteRepo(c *gin.Context, repo *repositories.PlantRepository, plant *models.PlantSpecies) *repositories.PlantRepository {
+	if plant.OrganizationID == nil && isAdmin(c) {
+		return repo.ForTenant(tenant.All())
+	}
+	return repo
+}
+
+// resolveSpeciesFunctions combina las funciones de una solicitud nueva con el campo
+// de compatibilidad function_ecol, que por sí solo equivale a una única función principal
+func resolveSpeciesFunctions(functions []string, primary, legacy string) ([]string, string) {
+	if primary == "" {
+		primary = legacy
+	}
+	if len(functions) == 0 && primary != "" {
+		return []string{primary}, primary
+	}
+	return functions, primary
+}
+
+// mergeSpeciesFunctions aplica una actualización parcial sobre las funciones actuales.
+// Si solo llega function_ecol (o primary_function) se agrega a la lista y pasa a ser la principal.
+func mergeSpeciesFunctions(plant *models.PlantSpecies, req models.UpdatePlantSpeciesRequest) ([]string, string) {
+	names := plant.FunctionNames()
+	if req.Functions != nil {
+		names = req.Functions
+	}
+
+	primary := plant.FunctionEcol
+	switch {
+	case req.PrimaryFunction != nil:
+		primary = *req.PrimaryFunction
+	case req.FunctionEcol != nil:
+		primary = *req.FunctionEcol
+	case req.Functions != nil && !containsString(names, primary):
+		primary = ""
+	}
+
+	if req.Functions == nil && primary != "" && !containsString(names, primary) {
+		names = append(names, primary)
+	}
+
+	return names, primary
+}
+
+// parseListQuery admite valores repetidos (?f=a&f=b) o separados por comas (?f=a,b)
+func parseListQuery(c *gin.Context, name string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(name) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
 			}
-			return 1000 // Límite alto para admins
 		}
 	}
+	return values
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTraitRangeFilters lee los filtros por rango de rasgos (?min_height=5&max_lifespan=3).
+// Si alguno no es numérico responde 400 y devuelve false.
+func parseTraitRangeFilters(c *gin.Context, filters *repositories.PlantFilters) bool {
+	params := []struct {
+		name string
+		dst  **float64
+	}{
+		{"min_height", &filters.MinHeightM},
+		{"max_height", &filters.MaxHeightM},
+		{"min_canopy", &filters.MinCanopyM},
+		{"max_canopy", &filters.MaxCanopyM},
+		{"min_spacing", &filters.MinSpacingM},
+		{"max_spacing", &filters.MaxSpacingM},
+		{"min_lifespan", &filters.MinLifespanYears},
+		{"max_lifespan", &filters.MaxLifespanYears},
+		{"min_harvest_months", &filters.MinHarvestMonths},
+		{"max_harvest_months", &filters.MaxHarvestMonths},
+	}
+
+	for _, p := range params {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
 
-	// Límite por defecto para usuarios normales
-	if envLimit := os.Getenv("DEFAULT_MAX_PAGINATION_LIMIT"); envLimit != "" {
-		if limit, err := strconv.Atoi(envLimit); err == nil && limit > 0 {
-			return limit
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 {
+			respondInvalid(c, p.name, "Valor inválido para "+p.name)
+			return false
 		}
+		*p.dst = &value
 	}
+	return true
+}
 
-	return 100 // Límite por defecto
+// getMaxPaginationLimit determina el límite máximo de paginación basado en el rol del usuario
+func getMaxPaginationLimit(c *gin.Context) int {
+	// Los administradores pueden tener límites más altos
+	if role, ok := c.Get("user_role"); ok && role == "admin" {
+		return pagination.AdminMaxLimit
+	}
+	return pagination.DefaultMaxLimit
 }
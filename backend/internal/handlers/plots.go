@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var plotRepo *repositories.PlotRepository
+
+// getPlotRepo obtiene el repository limitado a la organización de la solicitud,
+// inicializándolo si es necesario
+func getPlotRepo(c *gin.Context) *repositories.PlotRepository {
+	if plotRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		plotRepo = repositories.NewPlotRepository()
+	}
+	return plotRepo.WithContext(requestContext(c)).ForTenant(requestTenant(c))
+}
+
+// CreatePlotHandler maneja la creación de parcelas dentro de una plantación
+func CreatePlotHandler(c *gin.Context) {
+	repo := getPlotRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	plantationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.CreatePlotRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	plot := models.Plot{
+		PlantationID: plantationID,
+		PlotType:     req.PlotType,
+		LengthM:      req.LengthM,
+		WidthM:       req.WidthM,
+		DiameterM:    req.DiameterM,
+		RadiusM:      req.RadiusM,
+		Geometry:     req.Geometry,
+		Notes:        req.Notes,
+	}
+
+	// Validar geometría según el tipo de parcela
+	if err := plot.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	if err := repo.Create(&plot); err != nil {
+		respondError(c, err, "Error guardando parcela en base de datos")
+		return
+	}
+
+	log.Printf("Parcela creada exitosamente: %s (ID: %d, plantación: %d)", plot.PlotType, plot.ID, plot.PlantationID)
+
+	// Una parcela recién creada todavía no tiene plantas
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    models.NewPlotWithMetrics(plot, 0),
+		Message: "Parcela creada exitosamente",
+	})
+}
+
+// GetPlantationPlotsHandler maneja la obtención de las parcelas de una plantación
+func GetPlantationPlotsHandler(c *gin.Context) {
+	repo := getPlotRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	plantationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	plotType := c.Query("plot_type")
+	if plotType != "" && !models.IsValidPlotType(plotType) {
+		respondInvalid(c, "plot_type", "tipo de parcela inválido")
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	filters := repositories.PlotFilters{
+		PlotType: plotType,
+		Limit:    limit,
+		Offset:   (page - 1) * limit,
+	}
+
+	plots, total, err := repo.GetByPlantation(plantationID, filters)
+	if err != nil {
+		respondError(c, err, "Error obteniendo parcelas de la base de datos")
+		return
+	}
+
+	ids := make([]uint, len(plots))
+	for i, plot := range plots {
+		ids[i] = plot.ID
+	}
+
+	counts, err := repo.PlantCounts(ids)
+	if err != nil {
+		respondError(c, err, "Error obteniendo parcelas de la base de datos")
+		return
+	}
+
+	data := make([]models.PlotWithMetrics, len(plots))
+	for i, plot := range plots {
+		data[i] = models.NewPlotWithMetrics(plot, counts[plot.ID])
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       data,
+		Pagination: newPagination(page, limit, total),
+	})
+}
+
+// GetPlotHandler maneja la obtención de una parcela específica
+func GetPlotHandler(c *gin.Context) {
+	repo := getPlotRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	plot, err := repo.GetByID(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo parcela de la base de datos")
+		return
+	}
+
+	respondPlotWithMetrics(c, repo, plot, http.StatusOK, "")
+}
+
+// UpdatePlotHandler maneja la actualización de una parcela
+func UpdatePlotHandler(c *gin.Context) {
+	repo := getPlotRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePlotRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	plot, err := repo.GetByID(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo parcela de la base de datos")
+		return
+	}
+
+	// Construir mapa de actualizaciones
+	updates := make(map[string]interface{})
+
+	if req.PlotType != nil {
+		plot.PlotType = *req.PlotType
+		updates["plot_type"] = *req.PlotType
+	}
+	if req.LengthM != nil {
+		plot.LengthM = *req.LengthM
+		updates["length_m"] = *req.LengthM
+	}
+	if req.WidthM != nil {
+		plot.WidthM = *req.WidthM
+		updates["width_m"] = *req.WidthM
+	}
+	if req.DiameterM != nil {
+		plot.DiameterM = *req.DiameterM
+		updates["diameter_m"] = *req.DiameterM
+	}
+	if req.RadiusM != nil {
+		plot.RadiusM = *req.RadiusM
+		updates["radius_m"] = *req.RadiusM
+	}
+	if req.Geometry != nil {
+		plot.Geometry = *req.Geometry
+		updates["geometry"] = *req.Geometry
+	}
+	if req.Notes != nil {
+		plot.Notes = *req.Notes
+		updates["notes"] = *req.Notes
+	}
+
+	// Validar la geometría resultante antes de guardar
+	if err := plot.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	updated, err := repo.Update(id, updates)
+	if err != nil {
+		respondError(c, err, "Error actualizando parcela en la base de datos")
+		return
+	}
+
+	respondPlotWithMetrics(c, repo, updated, http.StatusOK, "Parcela actualizada exitosamente")
+}
+
+// DeletePlotHandler maneja la eliminación de una parcela y sus instancias
+func DeletePlotHandler(c *gin.Context) {
+	repo := getPlotRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := repo.Delete(id); err != nil {
+		respondError(c, err, "Error eliminando parcela de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Parcela eliminada exitosamente",
+	})
+}
+
+// respondPlotWithMetrics responde con la parcela, su área y su densidad de plantas
+func respondPlotWithMetrics(c *gin.Context, repo *repositories.PlotRepository, plot *models.Plot, status int, message string) {
+	counts, err := repo.PlantCounts([]uint{plot.ID})
+	if err != nil {
+		respondError(c, err, "Error obteniendo parcela de la base de datos")
+		return
+	}
+
+	c.JSON(status, models.APIResponse{
+		Success: true,
+		Data:    models.NewPlotWithMetrics(*plot, counts[plot.ID]),
+		Message: message,
+	})
+}
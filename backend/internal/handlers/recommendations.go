@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/services"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPlotRecommendationsHandler sugiere especies del catálogo que cubren los
+// estratos, etapas sucesionales y funciones ecológicas que faltan en una parcela
+func GetPlotRecommendationsHandler(c *gin.Context) {
+	plots := getPlotRepo(c)
+	species := getPlantRepo(c)
+	if plots == nil || species == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	// Solo se usa el límite; las recomendaciones no se paginan
+	_, limit := parsePagination(c)
+
+	plot, err := plots.GetWithInstances(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo parcela de la base de datos")
+		return
+	}
+
+	gaps := services.AnalyzePlotGaps(plot)
+
+	candidates, err := species.FindCandidates(repositories.CandidateFilters{
+		Strata:           gaps.MissingStrata,
+		SuccessionStages: gaps.MissingSuccession,
+		Functions:        gaps.MissingFunctions,
+		ExcludeIDs:       services.SpeciesInPlot(plot),
+	})
+	if err != nil {
+		respondError(c, err, "Error obteniendo especies del catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    services.RecommendSpecies(plot, candidates, limit),
+	})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	siteMemberRepo *repositories.SiteMemberRepository
+	userRepo       *repositories.UserRepository
+)
+
+// getSiteMemberRepo obtiene el repository limitado a la organización de la solicitud,
+// inicializándolo si es necesario
+func getSiteMemberRepo(c *gin.Context) *repositories.SiteMemberRepository {
+	if siteMemberRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		siteMemberRepo = repositories.NewSiteMemberRepository()
+	}
+	return siteMemberRepo.WithContext(requestContext(c)).ForTenant(requestTenant(c))
+}
+
+// getUserRepo obtiene el repository, inicializándolo si es necesario
+func getUserRepo() *repositories.UserRepository {
+	if userRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		userRepo = repositories.NewUserRepository()
+	}
+	return userRepo
+}
+
+// GetSiteMembersHandler lista los miembros de un sitio con su rol
+func GetSiteMembersHandler(c *gin.Context) {
+	repo := getSiteMemberRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	siteID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	members, err := repo.GetBySite(siteID)
+	if err != nil {
+		respondSiteMemberError(c, err, "Error obteniendo miembros del sitio")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    members,
+	})
+}
+
+// InviteSiteMemberHandler agrega un usuario registrado al sitio con un rol
+func InviteSiteMemberHandler(c *gin.Context) {
+	repo := getSiteMemberRepo(c)
+	users := getUserRepo()
+	if repo == nil || users == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	siteID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.InviteSiteMemberRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if !models.IsValidSiteRole(req.Role) {
+		respondInvalid(c, "role", "rol de sitio inválido")
+		return
+	}
+
+	user, err := users.GetByEmail(req.Email)
+	if err != nil {
+		respondSiteMemberError(c, err, "Error obteniendo usuario")
+		return
+	}
+
+	inviterID := c.GetUint("user_id")
+	member := models.SiteMember{
+		SiteID:    siteID,
+		UserID:    user.ID,
+		Role:      req.Role,
+		InvitedBy: &inviterID,
+	}
+
+	if err := repo.Add(&member); err != nil {
+		respondSiteMemberError(c, err, "Error agregando miembro del sitio")
+		return
+	}
+
+	log.Printf("👥 Usuario %d agregado al sitio %d como %s por %d", user.ID, siteID, req.Role, inviterID)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    member,
+		Message: "Miembro agregado exitosamente",
+	})
+}
+
+// UpdateSiteMemberHandler cambia el rol de un miembro del sitio
+func UpdateSiteMemberHandler(c *gin.Context) {
+	repo := getSiteMemberRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	siteID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSiteMemberRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if !models.IsValidSiteRole(req.Role) {
+		respondInvalid(c, "role", "rol de sitio inválido")
+		return
+	}
+
+	member, err := repo.UpdateRole(siteID, userID, req.Role)
+	if err != nil {
+		respondSiteMemberError(c, err, "Error actualizando miembro del sitio")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    member,
+		Message: "Rol actualizado exitosamente",
+	})
+}
+
+// RemoveSiteMemberHandler quita a un miembro del sitio
+func RemoveSiteMemberHandler(c *gin.Context) {
+	repo := getSiteMemberRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	siteID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := repo.Remove(siteID, userID); err != nil {
+		respondSiteMemberError(c, err, "Error quitando miembro del sitio")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Miembro quitado exitosamente",
+	})
+}
+
+// respondSiteMemberError traduce los errores de miembros de sitio a respuestas HTTP
+func respondSiteMemberError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		err = apperror.NotFound("user_not_found", "No hay un usuario registrado con ese email")
+	}
+	respondError(c, err, fallback)
+}
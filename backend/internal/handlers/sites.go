@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var siteRepo *repositories.SiteRepository
+
+// getSiteRepo obtiene el repository limitado a la organización de la solicitud,
+// inicializándolo si es necesario
+func getSiteRepo(c *gin.Context) *repositories.SiteRepository {
+	if siteRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		siteRepo = repositories.NewSiteRepository()
+	}
+	return siteRepo.WithContext(requestContext(c)).ForTenant(requestTenant(c))
+}
+
+// CreateSiteHandler maneja la creación de sitios
+func CreateSiteHandler(c *gin.Context) {
+	repo := getSiteRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	var req models.CreateSiteRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// El sitio pertenece a la organización de la solicitud
+	if !requireOrganization(c) {
+		return
+	}
+
+	site := models.Site{
+		Name:    req.Name,
+		AreaM2:  req.AreaM2,
+		LengthM: req.LengthM,
+		WidthM:  req.WidthM,
+		Notes:   req.Notes,
+		Climate: req.Climate,
+	}
+
+	// Validar
+	if err := site.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	// Si no se indicó el área se calcula a partir de las dimensiones
+	site.AreaM2 = site.CalculateArea()
+
+	// Quien crea el sitio queda como su owner
+	if err := repo.Create(&site, c.GetUint("user_id")); err != nil {
+		respondError(c, err, "Error guardando sitio en base de datos")
+		return
+	}
+
+	log.Printf("Sitio creado exitosamente: %s (ID: %d)", site.Name, site.ID)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    site,
+		Message: "Sitio creado exitosamente",
+	})
+}
+
+// GetSitesHandler maneja la obtención de los sitios de la organización de los que
+// el usuario es miembro. Los administradores ven todos los sitios de la organización
+// indicada o, sin X-Organization-ID, los de todas.
+func GetSitesHandler(c *gin.Context) {
+	repo := getSiteRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	filters := repositories.SiteFilters{
+		Search:  c.Query("search"),
+		Climate: c.Query("climate"),
+		Limit:   limit,
+		Offset:  (page - 1) * limit,
+	}
+
+	if !isAdmin(c) {
+		filters.MemberID = c.GetUint("user_id")
+	}
+
+	if v := c.Query("min_area"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			respondInvalid(c, "min_area", "min_area inválido")
+			return
+		}
+		filters.MinAreaM2 = parsed
+	}
+
+	if v := c.Query("max_area"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil || parsed < 0 {
+			respondInvalid(c, "max_area", "max_area inválido")
+			return
+		}
+		filters.MaxAreaM2 = parsed
+	}
+
+	sites, total, err := repo.GetAll(filters)
+	if err != nil {
+		respondError(c, err, "Error obteniendo sitios de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       sites,
+		Pagination: newPagination(page, limit, total),
+	})
+}
+
+// GetSiteHandler maneja la obtención de un sitio específico
+func GetSiteHandler(c *gin.Context) {
+	repo := getSiteRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	site, err := repo.GetByID(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo sitio de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    site,
+	})
+}
+
+// UpdateSiteHandler maneja la actualización de un sitio
+func UpdateSiteHandler(c *gin.Context) {
+	repo := getSiteRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSiteRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	site, err := repo.GetByID(id)
+	if err != nil {
+		respondError(c, err, "Error obteniendo sitio de la base de datos")
+		return
+	}
+
+	// Aplicar cambios sobre el sitio actual para validar antes de guardar
+	if req.Name != nil {
+		site.Name = *req.Name
+	}
+	if req.AreaM2 != nil {
+		site.AreaM2 = *req.AreaM2
+	} else if req.LengthM != nil || req.WidthM != nil {
+		// Si cambian las dimensiones sin área explícita, el área se recalcula
+		site.AreaM2 = 0
+	}
+	if req.LengthM != nil {
+		site.LengthM = *req.LengthM
+	}
+	if req.WidthM != nil {
+		site.WidthM = *req.WidthM
+	}
+	if req.Notes != nil {
+		site.Notes = *req.Notes
+	}
+	if req.Climate != nil {
+		site.Climate = *req.Climate
+	}
+
+	if err := site.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	updates := map[string]interface{}{
+		"name":     site.Name,
+		"area_m2":  site.CalculateArea(),
+		"length_m": site.LengthM,
+		"width_m":  site.WidthM,
+		"notes":    site.Notes,
+		"climate":  site.Climate,
+	}
+
+	updated, err := repo.Update(id, updates)
+	if err != nil {
+		respondError(c, err, "Error actualizando sitio en la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    updated,
+		Message: "Sitio actualizado exitosamente",
+	})
+}
+
+// DeleteSiteHandler maneja la eliminación de un sitio
+func DeleteSiteHandler(c *gin.Context) {
+	repo := getSiteRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := repo.Delete(id); err != nil {
+		respondError(c, err, "Error eliminando sitio de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Sitio eliminado exitosamente",
+	})
+}
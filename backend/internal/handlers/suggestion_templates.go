@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/services"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var suggestionTemplateRepo *repositories.SuggestionTemplateRepository
+
+// getSuggestionTemplateRepo obtiene el repository limitado a la organización de la solicitud,
+// inicializándolo si es necesario
+func getSuggestionTemplateRepo(c *gin.Context) *repositories.SuggestionTemplateRepository {
+	if suggestionTemplateRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		suggestionTemplateRepo = repositories.NewSuggestionTemplateRepository()
+	}
+	return suggestionTemplateRepo.WithContext(requestContext(c)).ForTenant(requestTenant(c))
+}
+
+// CreatePlantationTemplateHandler maneja la creación de plantillas de sugerencias de una plantación
+func CreatePlantationTemplateHandler(c *gin.Context) {
+	repo := getSuggestionTemplateRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	plantationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req models.CreateSuggestionTemplateRequest
+
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	template := models.SuggestionTemplate{
+		PlantationID: plantationID,
+		Name:         req.Name,
+		Description:  req.Description,
+		Rules:        string(req.Rules),
+	}
+
+	// Validar, incluido el esquema de reglas
+	if err := template.Validate(); err != nil {
+		respondError(c, err, "Datos inválidos")
+		return
+	}
+
+	// Guardar las reglas en su forma canónica
+	rules, _ := models.ParseTemplateRules(template.Rules)
+	template.Rules = rules.JSON()
+
+	if err := repo.Create(&template); err != nil {
+		respondError(c, err, "Error guardando plantilla en base de datos")
+		return
+	}
+
+	log.Printf("Plantilla creada exitosamente: %s (ID: %d, plantación: %d)", template.Name, template.ID, template.PlantationID)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Data:    template,
+		Message: "Plantilla creada exitosamente",
+	})
+}
+
+// GetPlantationTemplatesHandler maneja la obtención de las plantillas de una plantación
+func GetPlantationTemplatesHandler(c *gin.Context) {
+	repo := getSuggestionTemplateRepo(c)
+	if repo == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	plantationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	templates, err := repo.GetByPlantation(plantationID)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantillas de la base de datos")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    templates,
+	})
+}
+
+// EvaluatePlantationTemplateHandler evalúa una plantación contra las reglas de una
+// de sus plantillas y devuelve el informe de cumplimiento
+func EvaluatePlantationTemplateHandler(c *gin.Context) {
+	repo := getSuggestionTemplateRepo(c)
+	plantations := getPlantationRepo(c)
+	if repo == nil || plantations == nil {
+		respondDatabaseUnavailable(c)
+		return
+	}
+
+	plantationID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	templateID, ok := parseIDParam(c, "tid")
+	if !ok {
+		return
+	}
+
+	plantation, err := plantations.GetWithLayout(plantationID)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantación de la base de datos")
+		return
+	}
+
+	template, err := repo.GetByID(plantationID, templateID)
+	if err != nil {
+		respondError(c, err, "Error obteniendo plantilla de la base de datos")
+		return
+	}
+
+	report, err := services.EvaluateTemplate(template, plantation, time.Now())
+	if err != nil {
+		respondError(c, err, "Error evaluando plantilla")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
@@ -1,8 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/deibys/sintronia/internal/audit"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,3 +19,68 @@ func SaludoHandler(c *gin.Context) {
 		"mensaje": "!Hola desde Sintronia con Gin!",
 	})
 }
+
+// respondDatabaseUnavailable responde cuando el servicio corre sin base de datos
+func respondDatabaseUnavailable(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+		Success: false,
+		Error:   "Base de datos no disponible",
+		Code:    apperror.CodeUnavailable,
+		Message: "El servicio está funcionando en modo limitado",
+	})
+}
+
+// parseIDParam obtiene un ID numérico de los parámetros de la ruta.
+// Si es inválido responde 400 y devuelve false.
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		respondInvalid(c, name, "ID inválido")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// currentActor identifica al usuario autenticado para registrar quién hizo un cambio
+func currentActor(c *gin.Context) string {
+	if userID, exists := c.Get("user_id"); exists && userID != nil {
+		return fmt.Sprintf("%v", userID)
+	}
+	return "desconocido"
+}
+
+// requestContext devuelve el contexto de la solicitud con su autor, para que la
+// auditoría registre quién hizo cada cambio
+func requestContext(c *gin.Context) context.Context {
+	return audit.WithActor(c.Request.Context(), audit.Actor{
+		UserID:    c.GetUint("user_id"),
+		APIKeyID:  c.GetUint("api_key_id"),
+		RequestID: c.GetString("request_id"),
+		IP:        c.ClientIP(),
+	})
+}
+
+// isAdmin indica si el usuario autenticado tiene el rol global de administrador
+func isAdmin(c *gin.Context) bool {
+	return c.GetString("user_role") == models.UserRoleAdmin
+}
+
+// requestTenant obtiene el tenant que dejó TenantMiddleware. Sin él se usa el
+// catálogo, que no da acceso a datos de ninguna organización.
+func requestTenant(c *gin.Context) tenant.Tenant {
+	if t, ok := tenant.FromContext(c.Request.Context()); ok {
+		return t
+	}
+	return tenant.Catalog()
+}
+
+// requireOrganization verifica que la solicitud trabaje sobre una organización
+// concreta (y no sobre el catálogo o todas). Si no, responde 400 y devuelve false.
+func requireOrganization(c *gin.Context) bool {
+	if requestTenant(c).OrganizationID != 0 {
+		return true
+	}
+	message := "Indica la organización con el header X-Organization-ID"
+	respondError(c, apperror.Invalid("X-Organization-ID", apperror.FieldRequired, message), message)
+	return false
+}
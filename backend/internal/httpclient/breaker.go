@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// Estados del circuit breaker
+const (
+	BreakerClosed   = "closed"    // Las solicitudes pasan normalmente
+	BreakerOpen     = "open"      // El host falló demasiado: se rechaza sin llamar
+	BreakerHalfOpen = "half_open" // Pasó el enfriamiento: se deja pasar una solicitud de prueba
+)
+
+// breaker corta las llamadas a un host después de varios fallos seguidos
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+
+	state     string
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	lastError string
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, state: BreakerClosed}
+}
+
+// allow indica si se puede llamar al host. En half_open solo pasa una
+// solicitud a la vez hasta saber si el host se recuperó.
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// success cierra el circuito
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// release libera la solicitud de prueba sin cambiar el estado, cuando el
+// llamador la canceló y no se sabe si el host se recuperó
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// failure cuenta un fallo y abre el circuito al llegar al umbral, o de
+// inmediato si falló la solicitud de prueba
+func (b *breaker) failure(now time.Time, reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastError = reason
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = now
+	}
+}
+
+// BreakerStats es el estado del circuit breaker de un host
+type BreakerStats struct {
+	State     string     `json:"state"`
+	Failures  int        `json:"consecutive_failures"`
+	OpenedAt  *time.Time `json:"opened_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+func (b *breaker) stats() BreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := BreakerStats{State: b.state, Failures: b.failures, LastError: b.lastError}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
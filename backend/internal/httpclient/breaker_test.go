@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerTransitions(t *testing.T) {
+	start := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	cooldown := 30 * time.Second
+
+	// Cada paso aplica una acción en el instante indicado y verifica el estado resultante
+	type step struct {
+		action  string // allow, success, failure, release
+		at      time.Duration
+		allowed bool // Solo para allow
+		state   string
+	}
+
+	cases := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "se abre al llegar al umbral",
+			steps: []step{
+				{action: "failure", state: BreakerClosed},
+				{action: "failure", state: BreakerClosed},
+				{action: "failure", state: BreakerOpen},
+				{action: "allow", at: time.Second, allowed: false, state: BreakerOpen},
+			},
+		},
+		{
+			name: "un éxito reinicia el conteo",
+			steps: []step{
+				{action: "failure", state: BreakerClosed},
+				{action: "failure", state: BreakerClosed},
+				{action: "success", state: BreakerClosed},
+				{action: "failure", state: BreakerClosed},
+				{action: "allow", allowed: true, state: BreakerClosed},
+			},
+		},
+		{
+			name: "tras el enfriamiento pasa una sola prueba",
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure", state: BreakerOpen},
+				{action: "allow", at: cooldown, allowed: true, state: BreakerHalfOpen},
+				{action: "allow", at: cooldown, allowed: false, state: BreakerHalfOpen},
+			},
+		},
+		{
+			name: "la prueba exitosa cierra el circuito",
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure", state: BreakerOpen},
+				{action: "allow", at: cooldown, allowed: true, state: BreakerHalfOpen},
+				{action: "success", state: BreakerClosed},
+				{action: "allow", at: cooldown, allowed: true, state: BreakerClosed},
+			},
+		},
+		{
+			name: "la prueba fallida vuelve a abrir",
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure", state: BreakerOpen},
+				{action: "allow", at: cooldown, allowed: true, state: BreakerHalfOpen},
+				{action: "failure", at: cooldown, state: BreakerOpen},
+				{action: "allow", at: cooldown + time.Second, allowed: false, state: BreakerOpen},
+				{action: "allow", at: 2 * cooldown, allowed: true, state: BreakerHalfOpen},
+			},
+		},
+		{
+			name: "release libera la prueba sin cambiar el estado",
+			steps: []step{
+				{action: "failure"}, {action: "failure"}, {action: "failure", state: BreakerOpen},
+				{action: "allow", at: cooldown, allowed: true, state: BreakerHalfOpen},
+				{action: "release", state: BreakerHalfOpen},
+				{action: "allow", at: cooldown, allowed: true, state: BreakerHalfOpen},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newBreaker(3, cooldown)
+			for i, s := range tc.steps {
+				now := start.Add(s.at)
+				switch s.action {
+				case "allow":
+					if got := b.allow(now); got != s.allowed {
+						t.Fatalf("paso %d: allow = %t, se esperaba %t", i, got, s.allowed)
+					}
+				case "success":
+					b.success()
+				case "failure":
+					b.failure(now, "503 Service Unavailable")
+				case "release":
+					b.release()
+				}
+				if s.state != "" && b.stats().State != s.state {
+					t.Fatalf("paso %d (%s): estado = %q, se esperaba %q", i, s.action, b.stats().State, s.state)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerStats(t *testing.T) {
+	start := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	b := newBreaker(1, time.Minute)
+
+	if stats := b.stats(); stats.State != BreakerClosed || stats.OpenedAt != nil {
+		t.Fatalf("stats = %+v, se esperaba cerrado sin opened_at", stats)
+	}
+
+	b.failure(start, "timeout")
+	stats := b.stats()
+	if stats.State != BreakerOpen || stats.Failures != 1 || stats.LastError != "timeout" ||
+		stats.OpenedAt == nil || !stats.OpenedAt.Equal(start) {
+		t.Errorf("stats = %+v, se esperaba abierto desde %v por timeout", stats, start)
+	}
+}
@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// cacheEntry es una respuesta guardada con sus validadores
+type cacheEntry struct {
+	key          string
+	status       int
+	header       http.Header
+	body         []byte
+	etag         string
+	lastModified string
+	expiresAt    time.Time
+}
+
+// fresh indica si la entrada se puede servir sin consultar al host
+func (e *cacheEntry) fresh(now time.Time) bool {
+	return now.Before(e.expiresAt)
+}
+
+// revalidatable indica si la entrada vencida se puede validar con una solicitud condicional
+func (e *cacheEntry) revalidatable() bool {
+	return e.etag != "" || e.lastModified != ""
+}
+
+// responseCache es un caché LRU con TTL. Las entradas vencidas se conservan
+// mientras haya lugar para poder revalidarlas con ETag/Last-Modified.
+// Las entradas no se modifican una vez guardadas: renovarlas es volver a guardarlas.
+type responseCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	order   *list.List // Más reciente al frente
+	entries map[string]*list.Element
+}
+
+func newResponseCache(maxSize int, ttl time.Duration) *responseCache {
+	return &responseCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *responseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry), true
+}
+
+func (c *responseCache) put(entry *cacheEntry, now time.Time) {
+	if c.maxSize <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.expiresAt = now.Add(c.ttl)
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *responseCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
@@ -0,0 +1,90 @@
+package httpclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResponseCacheFreshness(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	cache := newResponseCache(4, time.Minute)
+	cache.put(&cacheEntry{key: "a", etag: `"v1"`}, now)
+
+	entry, ok := cache.get("a")
+	if !ok {
+		t.Fatal("se esperaba la entrada en el caché")
+	}
+
+	cases := []struct {
+		name  string
+		at    time.Time
+		fresh bool
+	}{
+		{"recién guardada", now, true},
+		{"antes del TTL", now.Add(59 * time.Second), true},
+		{"al vencer el TTL", now.Add(time.Minute), false},
+		{"vencida", now.Add(time.Hour), false},
+	}
+	for _, tc := range cases {
+		if got := entry.fresh(tc.at); got != tc.fresh {
+			t.Errorf("%s: fresh = %t, se esperaba %t", tc.name, got, tc.fresh)
+		}
+	}
+}
+
+func TestResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Now()
+	cache := newResponseCache(2, time.Minute)
+
+	cache.put(&cacheEntry{key: "a"}, now)
+	cache.put(&cacheEntry{key: "b"}, now)
+	cache.get("a") // "a" pasa a ser la más reciente
+	cache.put(&cacheEntry{key: "c"}, now)
+
+	if cache.len() != 2 {
+		t.Fatalf("len = %d, se esperaban 2 entradas", cache.len())
+	}
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok := cache.get(key); ok != want {
+			t.Errorf("get(%q) presente = %t, se esperaba %t", key, ok, want)
+		}
+	}
+}
+
+func TestResponseCacheReplacesEntry(t *testing.T) {
+	now := time.Now()
+	cache := newResponseCache(2, time.Minute)
+
+	cache.put(&cacheEntry{key: "a", etag: `"v1"`}, now)
+	cache.put(&cacheEntry{key: "a", etag: `"v2"`}, now.Add(time.Hour))
+
+	entry, _ := cache.get("a")
+	if cache.len() != 1 || entry.etag != `"v2"` || !entry.fresh(now.Add(time.Hour)) {
+		t.Errorf("entry = %+v, se esperaba una sola entrada renovada con v2", entry)
+	}
+}
+
+func TestResponseCacheDisabled(t *testing.T) {
+	cache := newResponseCache(0, time.Minute)
+	cache.put(&cacheEntry{key: "a"}, time.Now())
+
+	if _, ok := cache.get("a"); ok || cache.len() != 0 {
+		t.Error("un caché de tamaño 0 no debe guardar entradas")
+	}
+}
+
+func TestCacheEntryRevalidatable(t *testing.T) {
+	cases := []struct {
+		entry cacheEntry
+		want  bool
+	}{
+		{cacheEntry{}, false},
+		{cacheEntry{etag: `"v1"`}, true},
+		{cacheEntry{lastModified: "Sat, 01 Jun 2024 10:00:00 GMT"}, true},
+	}
+	for _, tc := range cases {
+		if got := tc.entry.revalidatable(); got != tc.want {
+			t.Errorf("revalidatable(%+v) = %t, se esperaba %t", tc.entry, got, tc.want)
+		}
+	}
+}
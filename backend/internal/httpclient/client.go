@@ -0,0 +1,398 @@
+// Package httpclient provee el cliente HTTP compartido para llamadas a APIs
+// externas: timeouts por host, reintentos con backoff exponencial, circuit
+// breaker por host y caché LRU/TTL que revalida con ETag/Last-Modified.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCircuitOpen se devuelve cuando el circuit breaker del host está abierto
+var ErrCircuitOpen = errors.New("circuito abierto: el servicio externo no responde")
+
+// Config configura el transporte compartido
+type Config struct {
+	DefaultTimeout   time.Duration            // Timeout por intento si el host no tiene uno propio
+	HostTimeouts     map[string]time.Duration // Timeout por intento para hosts específicos
+	MaxRetries       int                      // Reintentos después del primer intento (solo GET/HEAD)
+	BaseBackoff      time.Duration            // Espera antes del primer reintento; se duplica en cada uno
+	MaxBackoff       time.Duration
+	BreakerThreshold int           // Fallos seguidos que abren el circuito
+	BreakerCooldown  time.Duration // Tiempo abierto antes de probar de nuevo
+	CacheSize        int           // Respuestas en caché (0 = sin caché)
+	CacheTTL         time.Duration // Tiempo en que una respuesta se sirve sin revalidar
+}
+
+// DefaultConfig devuelve la configuración usada por el cliente compartido
+func DefaultConfig() Config {
+	return Config{
+		DefaultTimeout: 15 * time.Second,
+		HostTimeouts: map[string]time.Duration{
+			"permapeople.org": 10 * time.Second,
+		},
+		MaxRetries:       2,
+		BaseBackoff:      200 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+		CacheSize:        256,
+		CacheTTL:         5 * time.Minute,
+	}
+}
+
+// Transport es un http.RoundTripper con timeouts, reintentos, circuit breaker y caché.
+// El caché se indexa por URL, así que solo debe usarse con credenciales del servidor
+// (las mismas para todos los usuarios), nunca con credenciales de cada usuario.
+type Transport struct {
+	cfg   Config
+	base  http.RoundTripper
+	cache *responseCache
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+
+	requests      atomic.Int64
+	retries       atomic.Int64
+	cacheHits     atomic.Int64
+	cacheMisses   atomic.Int64
+	revalidations atomic.Int64
+	staleServed   atomic.Int64
+}
+
+// NewTransport crea el transporte. base es el transporte real (nil = http.DefaultTransport).
+func NewTransport(cfg Config, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if cfg.BreakerThreshold <= 0 {
+		cfg.BreakerThreshold = 1
+	}
+
+	return &Transport{
+		cfg:      cfg,
+		base:     base,
+		cache:    newResponseCache(cfg.CacheSize, cfg.CacheTTL),
+		now:      time.Now,
+		sleep:    sleepContext,
+		breakers: make(map[string]*breaker),
+	}
+}
+
+// Client devuelve un *http.Client que usa el transporte. Los timeouts se aplican
+// por intento, por eso el cliente no tiene un timeout global.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// CloseIdleConnections cierra las conexiones ociosas del transporte real
+// (p. ej. al apagar el servidor)
+func (t *Transport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+var (
+	sharedOnce      sync.Once
+	sharedTransport *Transport
+)
+
+// Shared devuelve el transporte compartido por todas las integraciones
+func Shared() *Transport {
+	sharedOnce.Do(func() {
+		sharedTransport = NewTransport(DefaultConfig(), nil)
+	})
+	return sharedTransport
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+
+	cacheable := req.Method == http.MethodGet && t.cfg.CacheSize > 0
+	key := req.URL.String()
+
+	var cached *cacheEntry
+	if cacheable {
+		if entry, ok := t.cache.get(key); ok {
+			if entry.fresh(t.now()) {
+				t.cacheHits.Add(1)
+				return entry.response(req), nil
+			}
+			cached = entry
+		} else {
+			t.cacheMisses.Add(1)
+		}
+	}
+
+	// Revalidación condicional de una entrada vencida
+	if cached != nil && cached.revalidatable() {
+		req = req.Clone(req.Context())
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := t.doWithRetries(req)
+	if err != nil {
+		// Si el host no responde, una respuesta vieja es mejor que ninguna
+		if cached != nil {
+			t.staleServed.Add(1)
+			return cached.response(req), nil
+		}
+		return nil, err
+	}
+
+	if cached != nil && resp.StatusCode >= 500 {
+		drain(resp)
+		t.staleServed.Add(1)
+		return cached.response(req), nil
+	}
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		t.revalidations.Add(1)
+		renewed := *cached
+		t.cache.put(&renewed, t.now())
+		return renewed.response(req), nil
+	}
+
+	if cacheable && resp.StatusCode == http.StatusOK {
+		return t.store(key, resp)
+	}
+	return resp, nil
+}
+
+// doWithRetries hace la solicitud respetando el circuit breaker del host y
+// reintentando errores de red, 429 y 5xx en métodos idempotentes
+func (t *Transport) doWithRetries(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	b := t.breakerFor(host)
+
+	attempts := 1
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		attempts += t.cfg.MaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			t.retries.Add(1)
+			if err := t.sleep(req.Context(), t.backoff(attempt)); err != nil {
+				return nil, err
+			}
+		}
+
+		if !b.allow(t.now()) {
+			return nil, fmt.Errorf("%s: %w", host, ErrCircuitOpen)
+		}
+
+		resp, err := t.attempt(req, host)
+		switch {
+		case err != nil:
+			// Cancelación del llamador: no es un fallo del host
+			if req.Context().Err() != nil {
+				b.release()
+				return nil, err
+			}
+			b.failure(t.now(), err.Error())
+			lastErr = err
+		case resp.StatusCode >= 500:
+			b.failure(t.now(), resp.Status)
+			lastErr = fmt.Errorf("%s respondió %s", host, resp.Status)
+			if attempt == attempts-1 {
+				return resp, nil
+			}
+			drain(resp)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			b.success()
+			lastErr = fmt.Errorf("%s respondió %s", host, resp.Status)
+			if attempt == attempts-1 {
+				return resp, nil
+			}
+			drain(resp)
+		default:
+			b.success()
+			return resp, nil
+		}
+	}
+
+	return nil, lastErr
+}
+
+// attempt hace un intento con el timeout del host. El cuerpo se lee completo
+// dentro del plazo para poder liberar el contexto del intento.
+func (t *Transport) attempt(req *http.Request, host string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeoutFor(host))
+	defer cancel()
+
+	resp, err := t.base.RoundTrip(req.Clone(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo respuesta de %s: %w", host, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+// store guarda una respuesta 200 en el caché y devuelve una copia para el llamador
+func (t *Transport) store(key string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Header.Get("Cache-Control") != "no-store" {
+		t.cache.put(&cacheEntry{
+			key:          key,
+			status:       resp.StatusCode,
+			header:       resp.Header.Clone(),
+			body:         body,
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+		}, t.now())
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (t *Transport) breakerFor(host string) *breaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.breakers[host]
+	if !ok {
+		b = newBreaker(t.cfg.BreakerThreshold, t.cfg.BreakerCooldown)
+		t.breakers[host] = b
+	}
+	return b
+}
+
+func (t *Transport) timeoutFor(host string) time.Duration {
+	if timeout, ok := t.cfg.HostTimeouts[host]; ok && timeout > 0 {
+		return timeout
+	}
+	if t.cfg.DefaultTimeout > 0 {
+		return t.cfg.DefaultTimeout
+	}
+	return DefaultConfig().DefaultTimeout
+}
+
+// backoff devuelve la espera antes del reintento n (1, 2, ...) con jitter de ±20%
+func (t *Transport) backoff(attempt int) time.Duration {
+	delay := t.cfg.BaseBackoff << (attempt - 1)
+	if delay <= 0 || (t.cfg.MaxBackoff > 0 && delay > t.cfg.MaxBackoff) {
+		delay = t.cfg.MaxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/5 + 1))
+	if rand.Intn(2) == 0 {
+		return delay - jitter
+	}
+	return delay + jitter
+}
+
+// response arma una respuesta nueva a partir de la entrada del caché
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Stats es el estado del cliente para el endpoint de diagnóstico
+type Stats struct {
+	Requests       int64                   `json:"requests"`
+	Retries        int64                   `json:"retries"`
+	Cache          CacheStats              `json:"cache"`
+	Breakers       map[string]BreakerStats `json:"breakers"`
+	DefaultTimeout string                  `json:"default_timeout"`
+	HostTimeouts   map[string]string       `json:"host_timeouts"`
+}
+
+// CacheStats resume el uso del caché de respuestas
+type CacheStats struct {
+	Entries       int   `json:"entries"`
+	MaxEntries    int   `json:"max_entries"`
+	TTLSeconds    int64 `json:"ttl_seconds"`
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Revalidations int64 `json:"revalidations"` // Respuestas 304 servidas desde el caché
+	StaleServed   int64 `json:"stale_served"`  // Respuestas vencidas servidas por fallo del host
+}
+
+// Stats devuelve una instantánea de contadores, caché y circuit breakers
+func (t *Transport) Stats() Stats {
+	stats := Stats{
+		Requests: t.requests.Load(),
+		Retries:  t.retries.Load(),
+		Cache: CacheStats{
+			Entries:       t.cache.len(),
+			MaxEntries:    t.cfg.CacheSize,
+			TTLSeconds:    int64(t.cfg.CacheTTL / time.Second),
+			Hits:          t.cacheHits.Load(),
+			Misses:        t.cacheMisses.Load(),
+			Revalidations: t.revalidations.Load(),
+			StaleServed:   t.staleServed.Load(),
+		},
+		Breakers:       make(map[string]BreakerStats),
+		DefaultTimeout: t.timeoutFor("").String(),
+		HostTimeouts:   make(map[string]string),
+	}
+
+	t.mu.Lock()
+	for host, b := range t.breakers {
+		stats.Breakers[host] = b.stats()
+	}
+	t.mu.Unlock()
+
+	for host, timeout := range t.cfg.HostTimeouts {
+		stats.HostTimeouts[host] = timeout.String()
+	}
+	return stats
+}
@@ -0,0 +1,405 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reply es la respuesta (o el error de red) que devuelve el transporte de prueba
+type reply struct {
+	status int
+	header http.Header
+	body   string
+	err    error
+}
+
+// scriptedTransport devuelve las respuestas en orden y guarda las solicitudes recibidas
+type scriptedTransport struct {
+	t        *testing.T
+	replies  []reply
+	requests []*http.Request
+}
+
+func (s *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(s.requests) >= len(s.replies) {
+		s.t.Fatalf("solicitud inesperada número %d a %s", len(s.requests)+1, req.URL)
+	}
+	r := s.replies[len(s.requests)]
+	s.requests = append(s.requests, req)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	header := r.header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:     http.StatusText(r.status),
+		StatusCode: r.status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(r.body)),
+		Request:    req,
+	}, nil
+}
+
+// testTransport arma un Transport con reloj fijo y esperas registradas en lugar de dormir
+type testTransport struct {
+	*Transport
+	base   *scriptedTransport
+	clock  time.Time
+	sleeps []time.Duration
+}
+
+func newTestTransport(t *testing.T, cfg Config, replies ...reply) *testTransport {
+	tt := &testTransport{
+		base:  &scriptedTransport{t: t, replies: replies},
+		clock: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+	tt.Transport = NewTransport(cfg, tt.base)
+	tt.now = func() time.Time { return tt.clock }
+	tt.sleep = func(ctx context.Context, d time.Duration) error {
+		tt.sleeps = append(tt.sleeps, d)
+		return ctx.Err()
+	}
+	return tt
+}
+
+func (tt *testTransport) do(t *testing.T, method string) (*http.Response, string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, "https://api.example.org/plants", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := tt.Client().Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body), nil
+}
+
+func testConfig() Config {
+	return Config{
+		DefaultTimeout:   time.Second,
+		MaxRetries:       2,
+		BaseBackoff:      100 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		BreakerThreshold: 10,
+		BreakerCooldown:  time.Minute,
+	}
+}
+
+var errNetwork = errors.New("connection refused")
+
+func TestTransportRetries(t *testing.T) {
+	cases := []struct {
+		name       string
+		method     string
+		replies    []reply
+		wantStatus int // 0 = se espera error
+		wantCalls  int
+	}{
+		{
+			name:       "éxito al primer intento",
+			method:     http.MethodGet,
+			replies:    []reply{{status: 200}},
+			wantStatus: 200,
+			wantCalls:  1,
+		},
+		{
+			name:       "reintenta 5xx hasta el éxito",
+			method:     http.MethodGet,
+			replies:    []reply{{status: 503}, {status: 502}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  3,
+		},
+		{
+			name:       "reintenta 429",
+			method:     http.MethodGet,
+			replies:    []reply{{status: 429}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+		},
+		{
+			name:       "reintenta errores de red",
+			method:     http.MethodGet,
+			replies:    []reply{{err: errNetwork}, {status: 200}},
+			wantStatus: 200,
+			wantCalls:  2,
+		},
+		{
+			name:       "devuelve el último 5xx al agotar los reintentos",
+			method:     http.MethodGet,
+			replies:    []reply{{status: 503}, {status: 503}, {status: 503}},
+			wantStatus: 503,
+			wantCalls:  3,
+		},
+		{
+			name:      "devuelve el error de red al agotar los reintentos",
+			method:    http.MethodGet,
+			replies:   []reply{{err: errNetwork}, {err: errNetwork}, {err: errNetwork}},
+			wantCalls: 3,
+		},
+		{
+			name:       "no reintenta errores del cliente",
+			method:     http.MethodGet,
+			replies:    []reply{{status: 404}},
+			wantStatus: 404,
+			wantCalls:  1,
+		},
+		{
+			name:       "no reintenta métodos no idempotentes",
+			method:     http.MethodPost,
+			replies:    []reply{{status: 503}},
+			wantStatus: 503,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tt := newTestTransport(t, testConfig(), tc.replies...)
+
+			resp, _, err := tt.do(t, tc.method)
+			if tc.wantStatus == 0 {
+				if err == nil {
+					t.Fatalf("se esperaba error, status = %d", resp.StatusCode)
+				}
+			} else if err != nil {
+				t.Fatalf("Do: %v", err)
+			} else if resp.StatusCode != tc.wantStatus {
+				t.Errorf("status = %d, se esperaba %d", resp.StatusCode, tc.wantStatus)
+			}
+
+			if len(tt.base.requests) != tc.wantCalls {
+				t.Errorf("llamadas = %d, se esperaban %d", len(tt.base.requests), tc.wantCalls)
+			}
+			if len(tt.sleeps) != tc.wantCalls-1 || tt.Stats().Retries != int64(tc.wantCalls-1) {
+				t.Errorf("esperas = %d, reintentos = %d, se esperaban %d", len(tt.sleeps), tt.Stats().Retries, tc.wantCalls-1)
+			}
+		})
+	}
+}
+
+func TestTransportBackoff(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxRetries = 4
+	cfg.MaxBackoff = 300 * time.Millisecond
+	tt := newTestTransport(t, cfg, reply{status: 503}, reply{status: 503}, reply{status: 503}, reply{status: 503}, reply{status: 200})
+
+	if _, _, err := tt.do(t, http.MethodGet); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+
+	// Base de 100 ms que se duplica hasta el máximo de 300 ms, con ±20% de jitter
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	if len(tt.sleeps) != len(want) {
+		t.Fatalf("esperas = %v, se esperaban %d", tt.sleeps, len(want))
+	}
+	for i, w := range want {
+		if got := tt.sleeps[i]; got < w*8/10 || got > w*12/10 {
+			t.Errorf("espera %d = %v, se esperaba %v ±20%%", i+1, got, w)
+		}
+	}
+}
+
+func TestTransportStopsRetryingWhenCanceled(t *testing.T) {
+	tt := newTestTransport(t, testConfig(), reply{status: 503})
+	ctx, cancel := context.WithCancel(context.Background())
+	tt.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.example.org/plants", nil)
+	if _, err := tt.Client().Do(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, se esperaba context.Canceled", err)
+	}
+	if len(tt.base.requests) != 1 {
+		t.Errorf("llamadas = %d, se esperaba 1", len(tt.base.requests))
+	}
+}
+
+func TestTransportCircuitBreaker(t *testing.T) {
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	cfg.BreakerThreshold = 2
+	tt := newTestTransport(t, cfg, reply{status: 503}, reply{err: errNetwork}, reply{status: 200})
+
+	for i := 0; i < 2; i++ {
+		tt.do(t, http.MethodGet)
+	}
+
+	// Con el circuito abierto no se llama al host
+	if _, _, err := tt.do(t, http.MethodGet); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, se esperaba ErrCircuitOpen", err)
+	}
+	if len(tt.base.requests) != 2 {
+		t.Fatalf("llamadas = %d, se esperaban 2", len(tt.base.requests))
+	}
+	stats := tt.Stats().Breakers["api.example.org"]
+	if stats.State != BreakerOpen || stats.Failures != 2 || !strings.Contains(stats.LastError, errNetwork.Error()) {
+		t.Errorf("breaker = %+v, se esperaba abierto con 2 fallos", stats)
+	}
+
+	// Pasado el enfriamiento la solicitud de prueba cierra el circuito
+	tt.clock = tt.clock.Add(cfg.BreakerCooldown)
+	resp, _, err := tt.do(t, http.MethodGet)
+	if err != nil || resp.StatusCode != 200 {
+		t.Fatalf("resp = %v, err = %v, se esperaba 200", resp, err)
+	}
+	if state := tt.Stats().Breakers["api.example.org"].State; state != BreakerClosed {
+		t.Errorf("estado = %q, se esperaba closed", state)
+	}
+}
+
+func TestTransportCache(t *testing.T) {
+	cfg := testConfig()
+	cfg.CacheSize = 8
+	cfg.CacheTTL = time.Minute
+	etag := http.Header{"Etag": {`"v1"`}}
+
+	cases := []struct {
+		name       string
+		replies    []reply // Después de la primera respuesta, que se guarda en el caché
+		advance    time.Duration
+		wantBody   string
+		wantCalls  int
+		wantHeader string // If-None-Match enviado en la segunda llamada
+		wantStats  CacheStats
+	}{
+		{
+			name:      "sirve la respuesta fresca sin llamar",
+			advance:   30 * time.Second,
+			wantBody:  "v1",
+			wantCalls: 1,
+			wantStats: CacheStats{Hits: 1, Misses: 1},
+		},
+		{
+			name:       "revalida con ETag y renueva con 304",
+			replies:    []reply{{status: 304}},
+			advance:    2 * time.Minute,
+			wantBody:   "v1",
+			wantCalls:  2,
+			wantHeader: `"v1"`,
+			wantStats:  CacheStats{Misses: 1, Revalidations: 1},
+		},
+		{
+			name:       "reemplaza la entrada si cambió",
+			replies:    []reply{{status: 200, header: http.Header{"Etag": {`"v2"`}}, body: "v2"}},
+			advance:    2 * time.Minute,
+			wantBody:   "v2",
+			wantCalls:  2,
+			wantHeader: `"v1"`,
+			wantStats:  CacheStats{Misses: 1},
+		},
+		{
+			name:       "sirve la entrada vencida si el host falla",
+			replies:    []reply{{status: 503}, {err: errNetwork}, {status: 500}},
+			advance:    2 * time.Minute,
+			wantBody:   "v1",
+			wantCalls:  4,
+			wantHeader: `"v1"`,
+			wantStats:  CacheStats{Misses: 1, StaleServed: 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			replies := append([]reply{{status: 200, header: etag, body: "v1"}}, tc.replies...)
+			tt := newTestTransport(t, cfg, replies...)
+
+			if _, body, err := tt.do(t, http.MethodGet); err != nil || body != "v1" {
+				t.Fatalf("primera llamada: body = %q, err = %v", body, err)
+			}
+
+			tt.clock = tt.clock.Add(tc.advance)
+			resp, body, err := tt.do(t, http.MethodGet)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			if resp.StatusCode != 200 || body != tc.wantBody {
+				t.Errorf("status = %d, body = %q, se esperaba 200 con %q", resp.StatusCode, body, tc.wantBody)
+			}
+			if len(tt.base.requests) != tc.wantCalls {
+				t.Fatalf("llamadas = %d, se esperaban %d", len(tt.base.requests), tc.wantCalls)
+			}
+			if tc.wantCalls > 1 {
+				if got := tt.base.requests[1].Header.Get("If-None-Match"); got != tc.wantHeader {
+					t.Errorf("If-None-Match = %q, se esperaba %q", got, tc.wantHeader)
+				}
+			}
+
+			stats := tt.Stats().Cache
+			if stats.Entries != 1 || stats.Hits != tc.wantStats.Hits || stats.Misses != tc.wantStats.Misses ||
+				stats.Revalidations != tc.wantStats.Revalidations || stats.StaleServed != tc.wantStats.StaleServed {
+				t.Errorf("cache = %+v, se esperaba %+v con 1 entrada", stats, tc.wantStats)
+			}
+		})
+	}
+}
+
+func TestTransportCacheRenewsAfterRevalidation(t *testing.T) {
+	cfg := testConfig()
+	cfg.CacheSize = 8
+	cfg.CacheTTL = time.Minute
+	tt := newTestTransport(t, cfg,
+		reply{status: 200, header: http.Header{"Last-Modified": {"Sat, 01 Jun 2024 09:00:00 GMT"}}, body: "v1"},
+		reply{status: 304},
+	)
+
+	tt.do(t, http.MethodGet)
+	tt.clock = tt.clock.Add(2 * time.Minute)
+	tt.do(t, http.MethodGet)
+
+	// Tras el 304 la entrada vuelve a estar fresca por un TTL completo
+	tt.clock = tt.clock.Add(30 * time.Second)
+	if _, body, err := tt.do(t, http.MethodGet); err != nil || body != "v1" {
+		t.Fatalf("body = %q, err = %v, se esperaba v1 desde el caché", body, err)
+	}
+	if len(tt.base.requests) != 2 {
+		t.Errorf("llamadas = %d, se esperaban 2", len(tt.base.requests))
+	}
+	if got := tt.base.requests[1].Header.Get("If-Modified-Since"); got != "Sat, 01 Jun 2024 09:00:00 GMT" {
+		t.Errorf("If-Modified-Since = %q", got)
+	}
+}
+
+func TestTransportCacheSkipsUncacheableResponses(t *testing.T) {
+	cfg := testConfig()
+	cfg.CacheSize = 8
+	cfg.CacheTTL = time.Minute
+
+	cases := []struct {
+		name   string
+		method string
+		first  reply
+	}{
+		{"no-store", http.MethodGet, reply{status: 200, header: http.Header{"Cache-Control": {"no-store"}}}},
+		{"respuesta distinta de 200", http.MethodGet, reply{status: 404}},
+		{"método POST", http.MethodPost, reply{status: 200}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tt := newTestTransport(t, cfg, tc.first, reply{status: 200})
+			tt.do(t, tc.method)
+			if entries := tt.Stats().Cache.Entries; entries != 0 {
+				t.Fatalf("entradas = %d, no se esperaba guardar la respuesta", entries)
+			}
+
+			// La segunda solicitud vuelve a llamar al host
+			tt.do(t, tc.method)
+			if len(tt.base.requests) != 2 {
+				t.Errorf("llamadas = %d, se esperaban 2", len(tt.base.requests))
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package permapeople implementa el cliente de la API de Permapeople y la
+// importación de su catálogo de plantas a plant_species.
+package permapeople
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL es la URL de la API pública de Permapeople
+const DefaultBaseURL = "https://permapeople.org/api"
+
+var (
+	// ErrUnauthorized se devuelve cuando Permapeople rechaza las credenciales
+	ErrUnauthorized = errors.New("credenciales de Permapeople inválidas")
+	// ErrNoCredentials se devuelve cuando el servidor no tiene credenciales configuradas
+	ErrNoCredentials = errors.New("credenciales de Permapeople no configuradas")
+)
+
+// Credentials son la clave y el secreto de la API de Permapeople
+type Credentials struct {
+	KeyID     string
+	KeySecret string
+}
+
+// CredentialProvider entrega las credenciales en cada solicitud, para que una
+// rotación tenga efecto sin recrear el cliente
+type CredentialProvider interface {
+	PermapeopleCredentials(ctx context.Context) (Credentials, error)
+}
+
+// Config configura el cliente. BaseURL y HTTPClient se pueden reemplazar
+// para apuntar a un servidor de pruebas (httptest). Si Credentials es nil se
+// usan KeyID y KeySecret.
+type Config struct {
+	BaseURL     string
+	KeyID       string
+	KeySecret   string
+	Credentials CredentialProvider
+	HTTPClient  *http.Client
+}
+
+// Client es el cliente HTTP de la API de Permapeople
+type Client struct {
+	baseURL     string
+	keyID       string
+	keySecret   string
+	credentials CredentialProvider
+	httpClient  *http.Client
+}
+
+// NewClient crea un cliente con los valores por defecto para lo que no se configure
+func NewClient(cfg Config) *Client {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &Client{
+		baseURL:     baseURL,
+		keyID:       cfg.KeyID,
+		keySecret:   cfg.KeySecret,
+		credentials: cfg.Credentials,
+		httpClient:  httpClient,
+	}
+}
+
+// Plant es una planta del catálogo de Permapeople
+type Plant struct {
+	ID             int        `json:"id"`
+	Name           string     `json:"name"`
+	ScientificName string     `json:"scientific_name"`
+	Slug           string     `json:"slug"`
+	Description    string     `json:"description"`
+	Data           []DataItem `json:"data"`
+}
+
+// DataItem es un atributo clave/valor de una planta ("Layer", "Height", ...)
+type DataItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Value devuelve el valor del atributo key (sin distinguir mayúsculas) o ""
+func (p Plant) Value(key string) string {
+	for _, item := range p.Data {
+		if strings.EqualFold(strings.TrimSpace(item.Key), key) {
+			return strings.TrimSpace(item.Value)
+		}
+	}
+	return ""
+}
+
+// PlantsPage es una página del catálogo. La API pagina por cursor: la siguiente
+// página se pide con el ID de la última planta recibida.
+type PlantsPage struct {
+	Plants []Plant `json:"plants"`
+}
+
+// LastID devuelve el cursor para pedir la página siguiente
+func (p *PlantsPage) LastID() int {
+	if len(p.Plants) == 0 {
+		return 0
+	}
+	return p.Plants[len(p.Plants)-1].ID
+}
+
+// ListPlants obtiene la página de plantas que sigue a lastID (0 para la primera)
+func (c *Client) ListPlants(ctx context.Context, lastID int) (*PlantsPage, error) {
+	query := url.Values{}
+	if lastID > 0 {
+		query.Set("last_id", strconv.Itoa(lastID))
+	}
+
+	var page PlantsPage
+	if err := c.get(ctx, "/plants", query, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Forward reenvía una consulta GET a la API y devuelve el código y el cuerpo tal
+// cual, con las credenciales del servidor. Se usa para el proxy de /api/plants.
+func (c *Client) Forward(ctx context.Context, path string, query url.Values) (int, []byte, error) {
+	resp, err := c.do(ctx, path, query)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("error leyendo respuesta de Permapeople: %w", err)
+	}
+	return resp.StatusCode, body, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	resp, err := c.do(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode >= 300:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Permapeople respondió %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("respuesta de Permapeople inválida: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	creds, err := c.resolveCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creando solicitud a Permapeople: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("x-permapeople-key-id", creds.KeyID)
+	req.Header.Set("x-permapeople-key-secret", creds.KeySecret)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error llamando a Permapeople: %w", err)
+	}
+	return resp, nil
+}
+
+// resolveCredentials obtiene las credenciales del proveedor o las fijas de la configuración
+func (c *Client) resolveCredentials(ctx context.Context) (Credentials, error) {
+	creds := Credentials{KeyID: c.keyID, KeySecret: c.keySecret}
+	if c.credentials != nil {
+		var err error
+		if creds, err = c.credentials.PermapeopleCredentials(ctx); err != nil {
+			return Credentials{}, err
+		}
+	}
+
+	if creds.KeyID == "" || creds.KeySecret == "" {
+		return Credentials{}, ErrNoCredentials
+	}
+	return creds, nil
+}
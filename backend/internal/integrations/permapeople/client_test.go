@@ -0,0 +1,192 @@
+package permapeople
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+const (
+	testKeyID     = "key-id"
+	testKeySecret = "key-secret"
+)
+
+// catalogServer sirve el catálogo en páginas de pageSize plantas, paginando
+// por last_id como la API real. Rechaza las solicitudes sin credenciales.
+func catalogServer(t *testing.T, plants []Plant, pageSize int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/plants" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("x-permapeople-key-id") != testKeyID || r.Header.Get("x-permapeople-key-secret") != testKeySecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		lastID := 0
+		if raw := r.URL.Query().Get("last_id"); raw != "" {
+			var err error
+			if lastID, err = strconv.Atoi(raw); err != nil {
+				http.Error(w, "last_id inválido", http.StatusBadRequest)
+				return
+			}
+		}
+
+		page := PlantsPage{Plants: []Plant{}}
+		for _, p := range plants {
+			if p.ID > lastID && len(page.Plants) < pageSize {
+				page.Plants = append(page.Plants, p)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testClient(server *httptest.Server) *Client {
+	return NewClient(Config{
+		BaseURL:    server.URL,
+		KeyID:      testKeyID,
+		KeySecret:  testKeySecret,
+		HTTPClient: server.Client(),
+	})
+}
+
+func TestListPlantsPaginatesByLastID(t *testing.T) {
+	server := catalogServer(t, []Plant{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 5, Name: "C"}}, 2)
+	client := testClient(server)
+
+	first, err := client.ListPlants(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("primera página: %v", err)
+	}
+	if len(first.Plants) != 2 || first.LastID() != 2 {
+		t.Fatalf("primera página = %+v, se esperaban las plantas 1 y 2", first.Plants)
+	}
+
+	second, err := client.ListPlants(context.Background(), first.LastID())
+	if err != nil {
+		t.Fatalf("segunda página: %v", err)
+	}
+	if len(second.Plants) != 1 || second.Plants[0].ID != 5 {
+		t.Fatalf("segunda página = %+v, se esperaba la planta 5", second.Plants)
+	}
+
+	last, err := client.ListPlants(context.Background(), second.LastID())
+	if err != nil {
+		t.Fatalf("última página: %v", err)
+	}
+	if len(last.Plants) != 0 || last.LastID() != 0 {
+		t.Fatalf("última página = %+v, se esperaba vacía", last.Plants)
+	}
+}
+
+func TestListPlantsUnauthorized(t *testing.T) {
+	server := catalogServer(t, []Plant{{ID: 1, Name: "A"}}, 10)
+	client := NewClient(Config{
+		BaseURL:    server.URL,
+		KeyID:      testKeyID,
+		KeySecret:  "otro",
+		HTTPClient: server.Client(),
+	})
+
+	if _, err := client.ListPlants(context.Background(), 0); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("err = %v, se esperaba ErrUnauthorized", err)
+	}
+}
+
+func TestListPlantsUpstreamError(t *testing.T) {
+	cases := map[string]http.HandlerFunc{
+		"error del servidor": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "caído", http.StatusBadGateway)
+		},
+		"json inválido": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"plants": [`))
+		},
+	}
+
+	for name, handler := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			_, err := testClient(server).ListPlants(context.Background(), 0)
+			if err == nil || errors.Is(err, ErrUnauthorized) {
+				t.Fatalf("err = %v, se esperaba un error distinto de ErrUnauthorized", err)
+			}
+		})
+	}
+}
+
+func TestListPlantsWithoutCredentials(t *testing.T) {
+	server := catalogServer(t, nil, 10)
+	client := NewClient(Config{BaseURL: server.URL, HTTPClient: server.Client()})
+
+	if _, err := client.ListPlants(context.Background(), 0); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("err = %v, se esperaba ErrNoCredentials", err)
+	}
+}
+
+// staticCredentials es un CredentialProvider con valores fijos
+type staticCredentials struct {
+	creds Credentials
+	err   error
+}
+
+func (s staticCredentials) PermapeopleCredentials(ctx context.Context) (Credentials, error) {
+	return s.creds, s.err
+}
+
+func TestListPlantsUsesCredentialProvider(t *testing.T) {
+	server := catalogServer(t, []Plant{{ID: 1, Name: "A"}}, 10)
+	providerErr := errors.New("vault no disponible")
+
+	cases := []struct {
+		name     string
+		provider CredentialProvider
+		wantErr  error
+	}{
+		{"credenciales del proveedor", staticCredentials{creds: Credentials{KeyID: testKeyID, KeySecret: testKeySecret}}, nil},
+		{"el proveedor tiene prioridad", staticCredentials{creds: Credentials{KeyID: testKeyID, KeySecret: "otro"}}, ErrUnauthorized},
+		{"proveedor sin credenciales", staticCredentials{}, ErrNoCredentials},
+		{"error del proveedor", staticCredentials{err: providerErr}, providerErr},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(Config{
+				BaseURL:     server.URL,
+				KeyID:       testKeyID,
+				KeySecret:   testKeySecret,
+				Credentials: tc.provider,
+				HTTPClient:  server.Client(),
+			})
+
+			_, err := client.ListPlants(context.Background(), 0)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, se esperaba %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPlantValueIgnoresCase(t *testing.T) {
+	p := Plant{Data: []DataItem{{Key: " layer ", Value: " Canopy "}}}
+
+	if got := p.Value("Layer"); got != "Canopy" {
+		t.Fatalf("Value(Layer) = %q, se esperaba %q", got, "Canopy")
+	}
+	if got := p.Value("Height"); got != "" {
+		t.Fatalf("Value(Height) = %q, se esperaba vacío", got)
+	}
+}
@@ -0,0 +1,182 @@
+package permapeople
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// SpeciesStore es lo que el importador necesita del catálogo local.
+// repositories.PlantRepository lo implementa.
+type SpeciesStore interface {
+	ExistsByExternalRef(externalRef string) (bool, error)
+	GetByExternalRef(externalRef string) (*models.PlantSpecies, error)
+	Create(plant *models.PlantSpecies) error
+	Update(id uint, updates map[string]interface{}, functions []models.SpeciesFunction) (*models.PlantSpecies, error)
+}
+
+// ImportOptions controla una ejecución de la importación
+type ImportOptions struct {
+	DryRun   bool // Calcula los cambios sin escribir en la base de datos
+	MaxPages int  // 0 = recorrer todo el catálogo
+}
+
+// ImportResult resume lo que hizo (o haría, en dry-run) la importación
+type ImportResult struct {
+	DryRun  bool     `json:"dry_run"`
+	Pages   int      `json:"pages"`
+	Fetched int      `json:"fetched"`
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Skipped int      `json:"skipped"`
+	Errors  []string `json:"errors,omitempty"` // Registros omitidos por datos inválidos o rechazados
+}
+
+// Importer sincroniza el catálogo de Permapeople con plant_species
+type Importer struct {
+	client *Client
+	store  SpeciesStore
+}
+
+// NewImporter crea un importador
+func NewImporter(client *Client, store SpeciesStore) *Importer {
+	return &Importer{client: client, store: store}
+}
+
+// Run recorre el catálogo remoto página por página y crea o actualiza cada
+// especie según su external_ref. Los registros sin cambios se cuentan como omitidos.
+func (i *Importer) Run(ctx context.Context, opts ImportOptions) (*ImportResult, error) {
+	result := &ImportResult{DryRun: opts.DryRun}
+
+	lastID := 0
+	for opts.MaxPages == 0 || result.Pages < opts.MaxPages {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		page, err := i.client.ListPlants(ctx, lastID)
+		if err != nil {
+			return result, fmt.Errorf("error obteniendo página %d del catálogo: %w", result.Pages+1, err)
+		}
+		if len(page.Plants) == 0 {
+			break
+		}
+
+		result.Pages++
+		result.Fetched += len(page.Plants)
+
+		for _, remote := range page.Plants {
+			if err := i.importPlant(remote, opts.DryRun, result); err != nil {
+				return result, err
+			}
+		}
+
+		// Cursor que no avanza: evitar un bucle infinito
+		if page.LastID() <= lastID {
+			break
+		}
+		lastID = page.LastID()
+	}
+
+	log.Printf("🌿 Importación Permapeople (dry-run=%t): %d páginas, %d creadas, %d actualizadas, %d omitidas",
+		result.DryRun, result.Pages, result.Created, result.Updated, result.Skipped)
+
+	return result, nil
+}
+
+// importPlant crea o actualiza una especie. Los registros inválidos, o que la
+// base de datos rechaza por sus restricciones, se omiten y se informan; solo
+// los demás errores (p. ej. una conexión caída) cortan la importación.
+func (i *Importer) importPlant(remote Plant, dryRun bool, result *ImportResult) error {
+	species, err := MapPlant(remote)
+	if err == nil {
+		err = species.Validate()
+	}
+	if err != nil {
+		skipPlant(remote, err, result)
+		return nil
+	}
+
+	exists, err := i.store.ExistsByExternalRef(species.ExternalRef)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if !dryRun {
+			if err := i.store.Create(species); err != nil {
+				return rejectedOrFatal(remote, err, result)
+			}
+		}
+		result.Created++
+		return nil
+	}
+
+	current, err := i.store.GetByExternalRef(species.ExternalRef)
+	if err != nil {
+		return err
+	}
+
+	updates := diffSpecies(current, species)
+	if len(updates) == 0 {
+		result.Skipped++
+		return nil
+	}
+
+	if !dryRun {
+		if _, err := i.store.Update(current.ID, updates, nil); err != nil {
+			return rejectedOrFatal(remote, err, result)
+		}
+	}
+	result.Updated++
+	return nil
+}
+
+// rejectedOrFatal omite el registro si la base de datos lo rechazó por sus
+// datos (restricciones o external_ref repetido) y devuelve el error en cualquier otro caso
+func rejectedOrFatal(remote Plant, err error, result *ImportResult) error {
+	if errors.Is(err, repositories.ErrSpeciesRejected) || errors.Is(err, repositories.ErrSpeciesExternalRefTaken) {
+		skipPlant(remote, err, result)
+		return nil
+	}
+	return err
+}
+
+func skipPlant(remote Plant, err error, result *ImportResult) {
+	result.Skipped++
+	result.Errors = append(result.Errors, fmt.Sprintf("planta %d: %v", remote.ID, err))
+}
+
+// diffSpecies devuelve los campos que Permapeople informa y difieren de la especie local.
+// Los valores que el catálogo remoto no trae no borran los cargados a mano, y las
+// notas y funciones solo se completan al crear la especie.
+func diffSpecies(current, remote *models.PlantSpecies) map[string]interface{} {
+	updates := make(map[string]interface{})
+
+	setString := func(column, local, incoming string) {
+		if incoming != "" && incoming != local {
+			updates[column] = incoming
+		}
+	}
+	setFloat := func(column string, local, incoming *float64) {
+		if incoming != nil && (local == nil || *local != *incoming) {
+			updates[column] = *incoming
+		}
+	}
+
+	setString("common_name", current.CommonName, remote.CommonName)
+	setString("scientific_name", current.ScientificName, remote.ScientificName)
+	setString("stratum", current.Stratum, remote.Stratum)
+	setString("light_requirement", current.LightRequirement, remote.LightRequirement)
+	setString("water_needs", current.WaterNeeds, remote.WaterNeeds)
+	setString("frost_tolerance", current.FrostTolerance, remote.FrostTolerance)
+	setFloat("mature_height_m", current.MatureHeightM, remote.MatureHeightM)
+	setFloat("canopy_diameter_m", current.CanopyDiameterM, remote.CanopyDiameterM)
+	setFloat("lifespan_years", current.LifespanYears, remote.LifespanYears)
+
+	return updates
+}
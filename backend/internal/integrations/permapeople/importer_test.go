@@ -0,0 +1,216 @@
+package permapeople
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// fakeStore es un catálogo en memoria que registra las escrituras. createErrs
+// permite simular que la base de datos rechaza una especie concreta.
+type fakeStore struct {
+	species    map[string]*models.PlantSpecies
+	createErrs map[string]error
+	created    []string
+	updated    []uint
+	nextID     uint
+}
+
+func newFakeStore(existing ...*models.PlantSpecies) *fakeStore {
+	s := &fakeStore{species: map[string]*models.PlantSpecies{}, createErrs: map[string]error{}, nextID: 100}
+	for _, sp := range existing {
+		s.species[sp.ExternalRef] = sp
+	}
+	return s
+}
+
+func (s *fakeStore) ExistsByExternalRef(externalRef string) (bool, error) {
+	_, ok := s.species[externalRef]
+	return ok, nil
+}
+
+func (s *fakeStore) GetByExternalRef(externalRef string) (*models.PlantSpecies, error) {
+	sp, ok := s.species[externalRef]
+	if !ok {
+		return nil, fmt.Errorf("especie %s no encontrada", externalRef)
+	}
+	return sp, nil
+}
+
+func (s *fakeStore) Create(plant *models.PlantSpecies) error {
+	if err := s.createErrs[plant.ExternalRef]; err != nil {
+		return err
+	}
+	s.nextID++
+	plant.ID = s.nextID
+	s.species[plant.ExternalRef] = plant
+	s.created = append(s.created, plant.ExternalRef)
+	return nil
+}
+
+func (s *fakeStore) Update(id uint, updates map[string]interface{}, functions []models.SpeciesFunction) (*models.PlantSpecies, error) {
+	s.updated = append(s.updated, id)
+	for _, sp := range s.species {
+		if sp.ID == id {
+			return sp, nil
+		}
+	}
+	return nil, fmt.Errorf("especie %d no encontrada", id)
+}
+
+func (s *fakeStore) writes() int {
+	return len(s.created) + len(s.updated)
+}
+
+// testCatalog tiene una especie nueva, una existente con cambios, una sin
+// cambios y una sin nombre que debe omitirse
+func testCatalog() ([]Plant, *fakeStore) {
+	plants := []Plant{
+		{ID: 1, Name: "Inga", ScientificName: "Inga edulis", Data: []DataItem{{Key: "Layer", Value: "Sub-canopy"}}},
+		{ID: 2, Name: "Banano", ScientificName: "Musa paradisiaca", Data: []DataItem{{Key: "Layer", Value: "Canopy"}}},
+		{ID: 3, Name: "Yuca", ScientificName: "Manihot esculenta"},
+		{ID: 4},
+		{ID: 5, Name: "Jengibre", ScientificName: "Zingiber officinale", Data: []DataItem{{Key: "Layer", Value: "Underground"}}},
+	}
+	store := newFakeStore(
+		&models.PlantSpecies{ID: 2, CommonName: "Banano", ScientificName: "Musa", ExternalRef: ExternalRef(2)},
+		&models.PlantSpecies{ID: 3, CommonName: "Yuca", ScientificName: "Manihot esculenta", ExternalRef: ExternalRef(3)},
+	)
+	return plants, store
+}
+
+func TestImporterCountsCreatedUpdatedAndSkipped(t *testing.T) {
+	plants, store := testCatalog()
+	server := catalogServer(t, plants, 2)
+
+	result, err := NewImporter(testClient(server), store).Run(context.Background(), ImportOptions{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if result.Pages != 3 || result.Fetched != 5 {
+		t.Errorf("pages=%d fetched=%d, se esperaban 3 y 5", result.Pages, result.Fetched)
+	}
+	if result.Created != 2 || result.Updated != 1 || result.Skipped != 2 {
+		t.Errorf("created=%d updated=%d skipped=%d, se esperaban 2, 1 y 2",
+			result.Created, result.Updated, result.Skipped)
+	}
+	if len(result.Errors) != 1 || !strings.HasPrefix(result.Errors[0], "planta 4:") {
+		t.Errorf("errors = %v, se esperaba solo la planta 4", result.Errors)
+	}
+	if len(store.created) != 2 || len(store.updated) != 1 || store.updated[0] != 2 {
+		t.Errorf("created=%v updated=%v, se esperaban 2 altas y la actualización de la especie 2",
+			store.created, store.updated)
+	}
+}
+
+func TestImporterDryRunDoesNotWrite(t *testing.T) {
+	plants, store := testCatalog()
+	server := catalogServer(t, plants, 2)
+
+	result, err := NewImporter(testClient(server), store).Run(context.Background(), ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if !result.DryRun || result.Created != 2 || result.Updated != 1 || result.Skipped != 2 {
+		t.Errorf("result = %+v, se esperaban los mismos conteos que sin dry-run", result)
+	}
+	if store.writes() != 0 {
+		t.Errorf("dry-run escribió: created=%v updated=%v", store.created, store.updated)
+	}
+}
+
+func TestImporterSkipsRejectedRecords(t *testing.T) {
+	plants, store := testCatalog()
+	store.createErrs[ExternalRef(1)] = fmt.Errorf("%w: violates check constraint", repositories.ErrSpeciesRejected)
+	server := catalogServer(t, plants, 2)
+
+	result, err := NewImporter(testClient(server), store).Run(context.Background(), ImportOptions{})
+	if err != nil {
+		t.Fatalf("un registro rechazado no debe cortar la importación: %v", err)
+	}
+
+	if result.Created != 1 || result.Skipped != 3 {
+		t.Errorf("created=%d skipped=%d, se esperaban 1 y 3", result.Created, result.Skipped)
+	}
+	if len(result.Errors) != 2 || !strings.HasPrefix(result.Errors[0], "planta 1:") {
+		t.Errorf("errors = %v, se esperaban las plantas 1 y 4", result.Errors)
+	}
+	if len(store.created) != 1 || store.created[0] != ExternalRef(5) {
+		t.Errorf("created = %v, se esperaba solo la planta 5", store.created)
+	}
+}
+
+func TestImporterAbortsOnStoreFailure(t *testing.T) {
+	plants, store := testCatalog()
+	connErr := errors.New("conexión cerrada")
+	store.createErrs[ExternalRef(1)] = connErr
+	server := catalogServer(t, plants, 2)
+
+	result, err := NewImporter(testClient(server), store).Run(context.Background(), ImportOptions{})
+	if !errors.Is(err, connErr) {
+		t.Fatalf("err = %v, se esperaba el error de conexión", err)
+	}
+	if result.Pages != 1 || len(store.created) != 0 {
+		t.Errorf("pages=%d created=%v, la importación debía cortarse en la primera planta", result.Pages, store.created)
+	}
+}
+
+func TestImporterRespectsMaxPages(t *testing.T) {
+	plants, store := testCatalog()
+	server := catalogServer(t, plants, 2)
+
+	result, err := NewImporter(testClient(server), store).Run(context.Background(), ImportOptions{MaxPages: 1})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Pages != 1 || result.Fetched != 2 {
+		t.Errorf("pages=%d fetched=%d, se esperaban 1 y 2", result.Pages, result.Fetched)
+	}
+}
+
+func TestMapPlantLayer(t *testing.T) {
+	cases := []struct {
+		layer string
+		want  string
+	}{
+		{"Sub-canopy", models.StratumMedium},
+		{"Subcanopy", models.StratumMedium},
+		{"Canopy", models.StratumHigh},
+		{"Emergent", models.StratumEmergent},
+		{"Underground", models.StratumRoot},
+		{"Ground cover", models.StratumGround},
+		{"Climber", models.StratumClimber},
+		{"Unknown", ""},
+	}
+
+	for _, tc := range cases {
+		species, err := MapPlant(Plant{ID: 1, Name: "Planta", Data: []DataItem{{Key: "Layer", Value: tc.layer}}})
+		if err != nil {
+			t.Fatalf("MapPlant(%q): %v", tc.layer, err)
+		}
+		if species.Stratum != tc.want {
+			t.Errorf("MapPlant(%q).Stratum = %q, se esperaba %q", tc.layer, species.Stratum, tc.want)
+		}
+	}
+}
+
+func TestMapPlantRequiresName(t *testing.T) {
+	if _, err := MapPlant(Plant{ID: 9}); err == nil {
+		t.Fatal("se esperaba error para una planta sin nombre")
+	}
+
+	species, err := MapPlant(Plant{ID: 9, ScientificName: "Inga edulis"})
+	if err != nil {
+		t.Fatalf("MapPlant: %v", err)
+	}
+	if species.CommonName != "Inga edulis" || species.ExternalRef != "permapeople:9" {
+		t.Errorf("species = %+v, se esperaba el nombre científico como común", species)
+	}
+}
@@ -0,0 +1,204 @@
+package permapeople
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// ExternalRefPrefix identifica en plant_species.external_ref las especies importadas
+const ExternalRefPrefix = "permapeople:"
+
+// ExternalRef devuelve la referencia externa de una planta de Permapeople
+func ExternalRef(id int) string {
+	return ExternalRefPrefix + strconv.Itoa(id)
+}
+
+// keywordMap traduce un texto libre de Permapeople a un valor del catálogo.
+// Las entradas se evalúan en orden y gana la primera que aparezca en el texto,
+// así que una palabra que contiene a otra ("sub-canopy" y "canopy") va antes.
+type keywordMap []struct {
+	keyword string
+	value   string
+}
+
+func (m keywordMap) lookup(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return ""
+	}
+	for _, entry := range m {
+		if strings.Contains(text, entry.keyword) {
+			return entry.value
+		}
+	}
+	return ""
+}
+
+// Equivalencias de la capa ("Layer") de Permapeople con los estratos sintrópicos
+var layerStrata = keywordMap{
+	{"emergent", models.StratumEmergent},
+	{"sub-canopy", models.StratumMedium},
+	{"subcanopy", models.StratumMedium},
+	{"sub canopy", models.StratumMedium},
+	{"canopy", models.StratumHigh},
+	{"low tree", models.StratumMedium},
+	{"understory", models.StratumMedium},
+	{"shrub", models.StratumLow},
+	{"herb", models.StratumLow},
+	{"underground", models.StratumRoot},
+	{"ground", models.StratumGround},
+	{"climber", models.StratumClimber},
+	{"vine", models.StratumClimber},
+	{"root", models.StratumRoot},
+}
+
+var lightRequirements = keywordMap{
+	{"full sun", models.LightFullSun},
+	{"partial", models.LightPartialShade},
+	{"semi", models.LightPartialShade},
+	{"shade", models.LightShade},
+}
+
+var waterNeeds = keywordMap{
+	{"dry", models.WaterNeedsLow},
+	{"low", models.WaterNeedsLow},
+	{"moist", models.WaterNeedsMedium},
+	{"medium", models.WaterNeedsMedium},
+	{"wet", models.WaterNeedsHigh},
+	{"high", models.WaterNeedsHigh},
+}
+
+var numberPattern = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+
+// MapPlant convierte una planta de Permapeople en una especie del catálogo.
+// Devuelve un error si el registro no tiene los datos mínimos.
+func MapPlant(p Plant) (*models.PlantSpecies, error) {
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		name = strings.TrimSpace(p.ScientificName)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("planta %d sin nombre", p.ID)
+	}
+
+	species := &models.PlantSpecies{
+		CommonName:       name,
+		ScientificName:   strings.TrimSpace(p.ScientificName),
+		ExternalRef:      ExternalRef(p.ID),
+		Notes:            strings.TrimSpace(p.Description),
+		Stratum:          layerStrata.lookup(p.Value("Layer")),
+		LightRequirement: lightRequirements.lookup(p.Value("Light requirement")),
+		WaterNeeds:       waterNeeds.lookup(p.Value("Water requirement")),
+		FrostTolerance:   frostFromHardiness(p.Value("USDA Hardiness zone")),
+		MatureHeightM:    parseMeters(p.Value("Height")),
+		CanopyDiameterM:  parseMeters(p.Value("Width")),
+		LifespanYears:    lifespanFromCycle(p.Value("Life cycle")),
+	}
+
+	var functions []string
+	if isTrue(p.Value("Nitrogen fixer")) {
+		functions = append(functions, models.FunctionNitrogenFixer)
+	}
+	if isTrue(p.Value("Edible")) {
+		functions = append(functions, models.FunctionFood)
+	}
+	if p.Value("Medicinal") != "" && !isFalse(p.Value("Medicinal")) {
+		functions = append(functions, models.FunctionMedicinal)
+	}
+	species.SetFunctions(functions, "")
+
+	// Los rasgos fuera de rango se descartan en lugar de rechazar la especie
+	if species.MatureHeightM != nil && *species.MatureHeightM > models.MaxMatureHeightM {
+		species.MatureHeightM = nil
+	}
+	if species.CanopyDiameterM != nil && *species.CanopyDiameterM > models.MaxCanopyDiameterM {
+		species.CanopyDiameterM = nil
+	}
+
+	return species, nil
+}
+
+// parseMeters interpreta valores como "3", "2-5 m", "150 cm" o "10 ft" y
+// devuelve el mayor número del rango convertido a metros
+func parseMeters(value string) *float64 {
+	numbers := numberPattern.FindAllString(value, -1)
+	if len(numbers) == 0 {
+		return nil
+	}
+
+	max := 0.0
+	for _, n := range numbers {
+		parsed, err := strconv.ParseFloat(strings.Replace(n, ",", ".", 1), 64)
+		if err == nil && parsed > max {
+			max = parsed
+		}
+	}
+	if max <= 0 {
+		return nil
+	}
+
+	lower := strings.ToLower(value)
+	switch {
+	case strings.Contains(lower, "cm"):
+		max /= 100
+	case strings.Contains(lower, "ft") || strings.Contains(lower, "feet"):
+		max *= 0.3048
+	}
+	return &max
+}
+
+// lifespanFromCycle traduce el ciclo de vida a años; las perennes no tienen valor fijo
+func lifespanFromCycle(value string) *float64 {
+	var years float64
+	switch lower := strings.ToLower(value); {
+	case strings.Contains(lower, "annual"):
+		years = 1
+	case strings.Contains(lower, "biennial"):
+		years = 2
+	default:
+		return nil
+	}
+	return &years
+}
+
+// frostFromHardiness usa la zona USDA más fría en la que sobrevive la planta
+func frostFromHardiness(value string) string {
+	numbers := numberPattern.FindAllString(value, -1)
+	if len(numbers) == 0 {
+		return ""
+	}
+
+	zone, err := strconv.ParseFloat(strings.Replace(numbers[0], ",", ".", 1), 64)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case zone <= 7:
+		return models.FrostHardy
+	case zone <= 9:
+		return models.FrostSemiHardy
+	default:
+		return models.FrostSensitive
+	}
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "1":
+		return true
+	}
+	return false
+}
+
+func isFalse(value string) bool {
+	switch strings.ToLower(value) {
+	case "false", "no", "0":
+		return true
+	}
+	return false
+}
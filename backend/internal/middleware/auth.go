@@ -1,97 +1,220 @@
 package middleware
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
 
+	"github.com/deibys/sintronia/internal/auth"
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
 	"github.com/deibys/sintronia/pkg/models"
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware verifica la existencia y validez de un token de autorización en el header.
-func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Espera que el header Authorization tenga el formato "Bearer <token>"
-		authHeader := c.GetHeader("Authorization")
-		keyID := c.GetHeader("x-permapeople-key-id")
+// Métodos de autenticación que AuthMiddleware deja en auth_method
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
 
-		log.Printf("authHeader: %s | keyID: %s", authHeader, keyID)
+// APIKeyHeader permite enviar una API key sin el header Authorization
+const APIKeyHeader = "X-API-Key"
 
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") || keyID == "" {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Error:   "Token de autorización requerido",
-			})
-			c.Abort()
-			return
-		}
+var apiKeyService *auth.APIKeyService
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" { //|| parts[1] != "your-secret-token" {
-			c.JSON(http.StatusUnauthorized, models.APIResponse{
-				Success: false,
-				Error:   "Formato de token inválido. Use: Bearer <token>",
-			})
-			c.Abort()
-			return
+// getAPIKeyService obtiene el servicio de API keys, inicializándolo si es necesario
+func getAPIKeyService() *auth.APIKeyService {
+	if apiKeyService == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
 		}
-
-		// Si el token es correcto, continúa con la solicitud
-		c.Next()
+		apiKeyService = auth.NewAPIKeyService(repositories.NewAPIKeyRepository(), repositories.NewUserRepository())
 	}
+	return apiKeyService
 }
 
-// AuthMiddleware middleware de autenticación
-func AuthMiddleware2() gin.HandlerFunc {
+// AuthMiddleware verifica el token de acceso JWT o la API key de la solicitud y
+// agrega user_id (uint), user_role y auth_method al contexto. Con una API key
+// también agrega api_key_id y api_key_scopes, que RequireScope verifica. El rol se
+// toma del usuario en la base de datos, no del token, y las cuentas desactivadas
+// pierden el acceso de inmediato.
+//
+// La API key se acepta en el header X-API-Key o como "Authorization: Bearer snt_...".
+func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Obtener el token del header Authorization
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		// Nunca registrar el header en los logs
+		credential, ok := requestCredential(c)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, models.APIResponse{
 				Success: false,
 				Error:   "Token de autorización requerido",
+				Code:    apperror.CodeUnauthorized,
 			})
 			c.Abort()
 			return
 		}
-
-		// Verificar formato Bearer token
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		if credential == "" {
 			c.JSON(http.StatusUnauthorized, models.APIResponse{
 				Success: false,
 				Error:   "Formato de token inválido. Use: Bearer <token>",
+				Code:    apperror.CodeUnauthorized,
 			})
 			c.Abort()
 			return
 		}
 
-		token := tokenParts[1]
+		if auth.IsAPIKey(credential) {
+			authenticateAPIKey(c, credential)
+			return
+		}
 
-		// Validar el token (por ahora simulamos la validación)
-		// En producción aquí validarías JWT, consultarías base de datos, etc.
-		if !isValidToken(token) {
+		claims, err := auth.DefaultTokenManager().ParseAccessToken(credential)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, models.APIResponse{
 				Success: false,
 				Error:   "Token inválido o expirado",
+				Code:    apperror.CodeUnauthorized,
 			})
 			c.Abort()
 			return
 		}
 
-		// Obtener información del usuario (simulado)
-		userID, userRole := getUserFromToken(token)
+		// El rol y el estado de la cuenta se leen en cada solicitud: desactivar un
+		// usuario o cambiar su rol tiene efecto sin esperar a que venza el token
+		user, err := loadActiveUser(claims.UserID)
+		if err != nil {
+			respondUserError(c, err)
+			return
+		}
 
 		// Agregar información del usuario al contexto
-		c.Set("user_id", userID)
-		c.Set("user_role", userRole)
+		c.Set("user_id", user.ID)
+		c.Set("user_role", user.Role)
+		c.Set("auth_method", AuthMethodJWT)
 
 		// Continuar con el siguiente handler
 		c.Next()
 	}
 }
 
+var userRepository *repositories.UserRepository
+
+// getUserRepository obtiene el repositorio de usuarios, inicializándolo si es necesario
+func getUserRepository() *repositories.UserRepository {
+	if userRepository == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		userRepository = repositories.NewUserRepository()
+	}
+	return userRepository
+}
+
+// loadActiveUser obtiene el usuario del token y verifica que siga activo
+func loadActiveUser(userID uint) (*models.User, error) {
+	users := getUserRepository()
+	if users == nil {
+		return nil, errDatabaseUnavailable
+	}
+
+	user, err := users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, auth.ErrUserInactive
+	}
+	return user, nil
+}
+
+// errDatabaseUnavailable indica que no se puede verificar el usuario sin base de datos
+var errDatabaseUnavailable = errors.New("base de datos no disponible")
+
+// respondUserError responde cuando el usuario del token no puede continuar
+func respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrUserNotFound), errors.Is(err, auth.ErrUserInactive):
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "Usuario inexistente o desactivado",
+			Code:    apperror.CodeUnauthorized,
+		})
+	case errors.Is(err, errDatabaseUnavailable):
+		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+			Success: false,
+			Error:   "Base de datos no disponible",
+			Code:    apperror.CodeUnavailable,
+			Message: "El servicio está funcionando en modo limitado",
+		})
+	default:
+		log.Printf("❌ Error verificando usuario: %v", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Error verificando usuario",
+			Code:    apperror.CodeInternal,
+		})
+	}
+	c.Abort()
+}
+
+// authenticateAPIKey verifica la API key y continúa con la cadena de handlers
+func authenticateAPIKey(c *gin.Context, credential string) {
+	svc := getAPIKeyService()
+	if svc == nil {
+		c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+			Success: false,
+			Error:   "Base de datos no disponible",
+			Code:    apperror.CodeUnavailable,
+			Message: "El servicio está funcionando en modo limitado",
+		})
+		c.Abort()
+		return
+	}
+
+	user, key, err := svc.Authenticate(credential)
+	if err != nil {
+		if !errors.Is(err, auth.ErrInvalidAPIKey) {
+			log.Printf("Error verificando API key: %v", err)
+		}
+		c.JSON(http.StatusUnauthorized, models.APIResponse{
+			Success: false,
+			Error:   "API key inválida o expirada",
+			Code:    apperror.CodeUnauthorized,
+		})
+		c.Abort()
+		return
+	}
+
+	setAPIKeyContext(c, user, key)
+	c.Next()
+}
+
+func setAPIKeyContext(c *gin.Context, user *models.User, key *models.APIKey) {
+	c.Set("user_id", user.ID)
+	c.Set("user_role", user.Role)
+	c.Set("auth_method", AuthMethodAPIKey)
+	c.Set("api_key_id", key.ID)
+	c.Set("api_key_scopes", key.Scopes)
+}
+
+// requestCredential obtiene la API key o el token de la solicitud. Devuelve
+// false si no hay credencial y una cadena vacía si el formato es inválido.
+func requestCredential(c *gin.Context) (string, bool) {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key, true
+	}
+
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return "", false
+	}
+	token, _ := bearerToken(header)
+	return token, true
+}
+
 // AdminMiddleware middleware que requiere rol de administrador
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -100,15 +223,17 @@ func AdminMiddleware() gin.HandlerFunc {
 			c.JSON(http.StatusUnauthorized, models.APIResponse{
 				Success: false,
 				Error:   "Usuario no autenticado",
+				Code:    apperror.CodeUnauthorized,
 			})
 			c.Abort()
 			return
 		}
 
-		if userRole != "admin" {
+		if userRole != models.UserRoleAdmin {
 			c.JSON(http.StatusForbidden, models.APIResponse{
 				Success: false,
 				Error:   "Acceso denegado. Se requieren permisos de administrador",
+				Code:    apperror.CodeForbidden,
 			})
 			c.Abort()
 			return
@@ -118,53 +243,37 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
-// Funciones auxiliares (simuladas - reemplazar con lógica real)
-func isValidToken(token string) bool {
-	// Por ahora aceptamos cualquier token que no esté vacío
-	// En producción: validar JWT, verificar en base de datos, etc.
-	validTokens := map[string]bool{
-		"test-token":  true,
-		"admin-token": true,
-		"user-token":  true,
-	}
-
-	return validTokens[token]
-}
-
-func getUserFromToken(token string) (int64, string) {
-	// Simulación - en producción extraer del JWT o consultar base de datos
-	tokenUsers := map[string]struct {
-		ID   int64
-		Role string
-	}{
-		"test-token":  {ID: 1, Role: "user"},
-		"admin-token": {ID: 2, Role: "admin"},
-		"user-token":  {ID: 3, Role: "user"},
-	}
-
-	if user, exists := tokenUsers[token]; exists {
-		return user.ID, user.Role
-	}
-
-	return 0, "guest"
-}
-
 // OptionalAuthMiddleware middleware de autenticación opcional
-// No bloquea si no hay token, pero agrega info del usuario si existe
+// No bloquea si no hay token, pero agrega info del usuario si es válido
 func OptionalAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			tokenParts := strings.Split(authHeader, " ")
-			if len(tokenParts) == 2 && tokenParts[0] == "Bearer" {
-				token := tokenParts[1]
-				if isValidToken(token) {
-					userID, userRole := getUserFromToken(token)
-					c.Set("user_id", userID)
-					c.Set("user_role", userRole)
+		credential, _ := requestCredential(c)
+		switch {
+		case credential == "":
+		case auth.IsAPIKey(credential):
+			if svc := getAPIKeyService(); svc != nil {
+				if user, key, err := svc.Authenticate(credential); err == nil {
+					setAPIKeyContext(c, user, key)
+				}
+			}
+		default:
+			if claims, err := auth.DefaultTokenManager().ParseAccessToken(credential); err == nil {
+				if user, err := loadActiveUser(claims.UserID); err == nil {
+					c.Set("user_id", user.ID)
+					c.Set("user_role", user.Role)
+					c.Set("auth_method", AuthMethodJWT)
 				}
 			}
 		}
 		c.Next()
 	}
 }
+
+// bearerToken extrae el token de un header "Bearer <token>"
+func bearerToken(header string) (string, bool) {
+	parts := strings.Split(header, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" || parts[1] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
@@ -10,9 +10,10 @@ import (
 // CustomLogger middleware personalizado para logging
 func CustomLogger() gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
+		return fmt.Sprintf("%s - [%s] %v \"%s %s %s %d %s \"%s\" %s\"\n",
 			param.ClientIP,
 			param.TimeStamp.Format(time.RFC1123),
+			param.Keys["request_id"], // Para cruzar el log con la auditoría
 			param.Method,
 			param.Path,
 			param.Request.Proto,
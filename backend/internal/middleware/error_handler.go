@@ -1,33 +1,73 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
+	"github.com/deibys/sintronia/pkg/apperror"
 	"github.com/deibys/sintronia/pkg/models"
 	"github.com/gin-gonic/gin"
 )
 
-// ErrorHandler middleware para manejo centralizado de errores
+// ErrorHandler middleware para manejo centralizado de errores. Responde el
+// último error que los handlers dejaron con c.Error si todavía no respondieron:
+// los errores del dominio (apperror) con su estado HTTP, código y detalles, y
+// el resto como error interno, sin exponer la causa.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
 		// Si hay errores, manejarlos
-		if len(c.Errors) > 0 {
-			err := c.Errors.Last()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		statusCode, response := errorResponse(c.Errors.Last().Err)
+		c.JSON(statusCode, response)
+	}
+}
+
+// Estado HTTP de cada tipo de error del dominio
+var errorStatus = []struct {
+	kind   error
+	status int
+}{
+	{apperror.ErrNotFound, http.StatusNotFound},
+	{apperror.ErrConflict, http.StatusConflict},
+	{apperror.ErrValidation, http.StatusBadRequest},
+	{apperror.ErrUnauthorized, http.StatusUnauthorized},
+	{apperror.ErrForbidden, http.StatusForbidden},
+}
+
+// errorResponse traduce un error a su estado HTTP y respuesta
+func errorResponse(err error) (int, models.APIResponse) {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		log.Printf("❌ Error no controlado: %v", err)
+		return http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Error interno del servidor",
+			Code:    apperror.CodeInternal,
+		}
+	}
 
-			response := models.APIResponse{
+	for _, s := range errorStatus {
+		if errors.Is(appErr.Kind, s.kind) {
+			return s.status, models.APIResponse{
 				Success: false,
+				// El mensaje completo conserva el contexto agregado al envolver el error
 				Error:   err.Error(),
+				Code:    appErr.Code,
+				Details: appErr.Details,
 			}
-
-			// Determinar código de estado basado en el tipo de error
-			statusCode := http.StatusInternalServerError
-			if c.Writer.Status() != http.StatusOK {
-				statusCode = c.Writer.Status()
-			}
-
-			c.JSON(statusCode, response)
 		}
 	}
+
+	log.Printf("❌ %s: %v", appErr.Message, appErr.Err)
+	return http.StatusInternalServerError, models.APIResponse{
+		Success: false,
+		Error:   appErr.Message,
+		Code:    appErr.Code,
+	}
 }
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// InFlight cuenta las solicitudes que se están atendiendo. Al apagar,
+// http.Server.Close cierra las conexiones pero no espera a los handlers: el
+// servidor usa Wait para no cerrar la base de datos mientras alguno la usa.
+type InFlight struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{} // Se cierra cuando count vuelve a cero
+}
+
+// Handler envuelve next y registra cada solicitud mientras su handler corre
+func (f *InFlight) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.start()
+		defer f.done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Count devuelve cuántas solicitudes se están atendiendo
+func (f *InFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// Wait espera a que terminen las solicitudes en curso. Devuelve false si ctx
+// vence antes.
+func (f *InFlight) Wait(ctx context.Context) bool {
+	f.mu.Lock()
+	if f.count == 0 {
+		f.mu.Unlock()
+		return true
+	}
+	idle := f.idle
+	f.mu.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (f *InFlight) start() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.count == 0 {
+		f.idle = make(chan struct{})
+	}
+	f.count++
+}
+
+func (f *InFlight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count--
+	if f.count == 0 {
+		close(f.idle)
+	}
+}
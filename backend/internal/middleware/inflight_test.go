@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInFlightWaitsForHandlers(t *testing.T) {
+	inFlight := &InFlight{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := inFlight.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}))
+
+	// Sin solicitudes no hay que esperar
+	if !inFlight.Wait(context.Background()) {
+		t.Fatal("Wait sin solicitudes en curso debe volver enseguida")
+	}
+
+	finished := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		go func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			finished <- struct{}{}
+		}()
+		<-started
+	}
+	if n := inFlight.Count(); n != 2 {
+		t.Fatalf("Count = %d, se esperaban 2", n)
+	}
+
+	// Con los handlers bloqueados, Wait respeta el plazo
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if inFlight.Wait(ctx) {
+		t.Fatal("Wait volvió con handlers en curso")
+	}
+
+	waited := make(chan bool, 1)
+	go func() { waited <- inFlight.Wait(context.Background()) }()
+
+	release <- struct{}{}
+	<-finished
+	select {
+	case <-waited:
+		t.Fatal("Wait volvió con un handler todavía en curso")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release <- struct{}{}
+	<-finished
+	select {
+	case ok := <-waited:
+		if !ok {
+			t.Error("Wait = false, se esperaba true")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait no volvió al terminar los handlers")
+	}
+
+	// El contador se puede reutilizar después de quedar en cero
+	go func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		finished <- struct{}{}
+	}()
+	<-started
+	if n := inFlight.Count(); n != 1 {
+		t.Errorf("Count = %d, se esperaba 1", n)
+	}
+	release <- struct{}{}
+	<-finished
+	if !inFlight.Wait(context.Background()) || inFlight.Count() != 0 {
+		t.Error("el contador no volvió a cero")
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope exige que una solicitud autenticada con API key tenga el scope.
+// Las sesiones con JWT y las solicitudes anónimas no se restringen: el resto de
+// los permisos lo verifican los demás middlewares. Debe ir después de AuthMiddleware
+// u OptionalAuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !scopeAllowed(c, scope) {
+			respondMissingScope(c, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireResourceScope exige el scope de lectura del recurso en GET y HEAD y el
+// de escritura en el resto de los métodos. Se aplica a un grupo de rutas para
+// que las rutas nuevas del grupo queden cubiertas sin declararlo en cada una.
+func RequireResourceScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		action := models.APIActionWrite
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			action = models.APIActionRead
+		}
+
+		scope := models.APIScope(action, resource)
+		if !scopeAllowed(c, scope) {
+			respondMissingScope(c, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession rechaza las API keys: la ruta solo se usa con una sesión
+// iniciada (p. ej. para que una clave no pueda crear otras claves)
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") == AuthMethodAPIKey {
+			c.JSON(http.StatusForbidden, models.APIResponse{
+				Success: false,
+				Error:   "Esta ruta requiere iniciar sesión; no acepta API keys",
+				Code:    apperror.CodeForbidden,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func scopeAllowed(c *gin.Context, scope string) bool {
+	if c.GetString("auth_method") != AuthMethodAPIKey {
+		return true
+	}
+	for _, s := range c.GetStringSlice("api_key_scopes") {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func respondMissingScope(c *gin.Context, scope string) {
+	c.JSON(http.StatusForbidden, models.APIResponse{
+		Success: false,
+		Error:   "Acceso denegado. La API key no tiene el scope " + scope,
+		Code:    apperror.CodeForbidden,
+	})
+	c.Abort()
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+var siteMemberRepo *repositories.SiteMemberRepository
+
+// getSiteMemberRepo obtiene el repository, inicializándolo si es necesario
+func getSiteMemberRepo() *repositories.SiteMemberRepository {
+	if siteMemberRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		siteMemberRepo = repositories.NewSiteMemberRepository()
+	}
+	return siteMemberRepo
+}
+
+// RequireSitePermission verifica que el usuario autenticado tenga el permiso en el
+// sitio al que pertenece el recurso del parámetro param (un sitio, una plantación,
+// una parcela o una instancia según scope). Debe ir después de AuthMiddleware y
+// TenantMiddleware: los recursos de otras organizaciones no se encuentran.
+// Los administradores globales tienen todos los permisos.
+//
+// Si el usuario no es miembro del sitio se responde 404, como si el recurso no
+// existiera; si es miembro pero su rol no alcanza, 403. Deja site_id y site_role
+// en el contexto.
+func RequireSitePermission(scope repositories.SiteScope, param, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		repo := getSiteMemberRepo()
+		if repo == nil {
+			c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+				Success: false,
+				Error:   "Base de datos no disponible",
+				Code:    apperror.CodeUnavailable,
+				Message: "El servicio está funcionando en modo limitado",
+			})
+			c.Abort()
+			return
+		}
+
+		id, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil || id == 0 {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "ID inválido",
+				Code:    apperror.CodeValidation,
+			})
+			c.Abort()
+			return
+		}
+
+		repo = repo.ForTenant(requestTenant(c))
+		siteID, err := repo.ResolveSiteID(scope, uint(id))
+		if err != nil {
+			respondSiteAccessError(c, scope, err)
+			return
+		}
+
+		role := models.SiteRoleOwner
+		if c.GetString("user_role") != models.UserRoleAdmin {
+			role, err = repo.GetRole(siteID, c.GetUint("user_id"))
+			if err != nil {
+				respondSiteAccessError(c, scope, err)
+				return
+			}
+		}
+
+		if !models.SiteRoleAllows(role, permission) {
+			c.JSON(http.StatusForbidden, models.APIResponse{
+				Success: false,
+				Error:   "Acceso denegado. Tu rol en este sitio no permite esta acción",
+				Code:    apperror.CodeForbidden,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("site_id", siteID)
+		c.Set("site_role", role)
+		c.Next()
+	}
+}
+
+// scopeNotFound es el mensaje 404 de cada tipo de recurso
+var scopeNotFound = map[repositories.SiteScope]string{
+	repositories.ScopeSite:       "Sitio no encontrado",
+	repositories.ScopePlantation: "Plantación no encontrada",
+	repositories.ScopePlot:       "Parcela no encontrada",
+	repositories.ScopeInstance:   "Instancia de planta no encontrada",
+}
+
+// respondSiteAccessError responde 404 cuando el recurso no existe o el usuario
+// no pertenece a su sitio, para no revelar recursos de otras fincas
+func respondSiteAccessError(c *gin.Context, scope repositories.SiteScope, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrSiteNotFound),
+		errors.Is(err, repositories.ErrPlantationNotFound),
+		errors.Is(err, repositories.ErrPlotNotFound),
+		errors.Is(err, repositories.ErrPlantInstanceNotFound),
+		errors.Is(err, repositories.ErrSiteMemberNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   scopeNotFound[scope],
+			Code:    apperror.CodeNotFound,
+		})
+	default:
+		log.Printf("Error verificando permisos del sitio: %v", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Error verificando permisos",
+			Code:    apperror.CodeInternal,
+		})
+	}
+	c.Abort()
+}
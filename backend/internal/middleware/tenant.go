@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHeader indica con qué organización se trabaja en la solicitud
+const OrganizationHeader = "X-Organization-ID"
+
+var organizationRepo *repositories.OrganizationRepository
+
+// getOrganizationRepo obtiene el repository, inicializándolo si es necesario
+func getOrganizationRepo() *repositories.OrganizationRepository {
+	if organizationRepo == nil {
+		if db.DB == nil {
+			return nil // DB no disponible
+		}
+		organizationRepo = repositories.NewOrganizationRepository()
+	}
+	return organizationRepo
+}
+
+// TenantMiddleware resuelve la organización de la solicitud y la deja en el
+// contexto para que los repositorios filtren por ella. Debe ir después de
+// AuthMiddleware.
+//
+// La organización se toma del header X-Organization-ID y el usuario debe ser
+// miembro. Sin header se usa la única organización del usuario; si tiene varias
+// debe indicarla. Los administradores globales pueden indicar cualquier
+// organización y, sin header, acceden a los datos de todas.
+func TenantMiddleware() gin.HandlerFunc {
+	return tenantMiddleware(true)
+}
+
+// OptionalTenantMiddleware es TenantMiddleware para rutas públicas: sin usuario,
+// o si el usuario no tiene una organización clara, solo se ve el catálogo global
+// de especies
+func OptionalTenantMiddleware() gin.HandlerFunc {
+	return tenantMiddleware(false)
+}
+
+func tenantMiddleware(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("user_id")
+		if userID == 0 {
+			if required {
+				c.JSON(http.StatusUnauthorized, models.APIResponse{
+					Success: false,
+					Error:   "Token de autorización requerido",
+					Code:    apperror.CodeUnauthorized,
+				})
+				c.Abort()
+				return
+			}
+			setTenant(c, tenant.Catalog(), "")
+			c.Next()
+			return
+		}
+
+		repo := getOrganizationRepo()
+		if repo == nil {
+			c.JSON(http.StatusServiceUnavailable, models.APIResponse{
+				Success: false,
+				Error:   "Base de datos no disponible",
+				Code:    apperror.CodeUnavailable,
+				Message: "El servicio está funcionando en modo limitado",
+			})
+			c.Abort()
+			return
+		}
+
+		admin := c.GetString("user_role") == models.UserRoleAdmin
+
+		if header := c.GetHeader(OrganizationHeader); header != "" {
+			orgID, err := strconv.ParseUint(header, 10, 32)
+			if err != nil || orgID == 0 {
+				c.JSON(http.StatusBadRequest, models.APIResponse{
+					Success: false,
+					Error:   "Header " + OrganizationHeader + " inválido",
+					Code:    apperror.CodeValidation,
+				})
+				c.Abort()
+				return
+			}
+
+			role, err := organizationRole(repo, uint(orgID), userID, admin)
+			if err != nil {
+				respondTenantError(c, err)
+				return
+			}
+			setTenant(c, tenant.Organization(uint(orgID)), role)
+			c.Next()
+			return
+		}
+
+		if admin {
+			setTenant(c, tenant.All(), models.OrganizationRoleOwner)
+			c.Next()
+			return
+		}
+
+		orgs, err := repo.GetForUser(userID)
+		if err != nil {
+			respondTenantError(c, err)
+			return
+		}
+
+		switch {
+		case len(orgs) == 1:
+			setTenant(c, tenant.Organization(orgs[0].ID), orgs[0].Role)
+		case !required:
+			setTenant(c, tenant.Catalog(), "")
+		case len(orgs) == 0:
+			c.JSON(http.StatusForbidden, models.APIResponse{
+				Success: false,
+				Error:   "No perteneces a ninguna organización",
+				Code:    apperror.CodeForbidden,
+			})
+			c.Abort()
+			return
+		default:
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Error:   "Perteneces a varias organizaciones: indica cuál con el header " + OrganizationHeader,
+				Code:    apperror.CodeValidation,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// organizationRole obtiene el rol del usuario en la organización. Los
+// administradores globales actúan como owner de cualquier organización existente.
+func organizationRole(repo *repositories.OrganizationRepository, orgID, userID uint, admin bool) (string, error) {
+	role, err := repo.GetRole(orgID, userID)
+	if !admin || !errors.Is(err, repositories.ErrOrganizationMemberNotFound) {
+		return role, err
+	}
+	if _, err := repo.GetByID(orgID); err != nil {
+		return "", err
+	}
+	return models.OrganizationRoleOwner, nil
+}
+
+// setTenant deja el tenant en el contexto de la solicitud, donde lo leen los
+// repositorios, y la organización en el contexto de Gin
+func setTenant(c *gin.Context, t tenant.Tenant, role string) {
+	c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), t))
+	if t.OrganizationID != 0 {
+		c.Set("organization_id", t.OrganizationID)
+		c.Set("organization_role", role)
+	}
+}
+
+// requestTenant obtiene el tenant que dejó TenantMiddleware. Sin él se usa el
+// catálogo, que no da acceso a datos de ninguna organización.
+func requestTenant(c *gin.Context) tenant.Tenant {
+	if t, ok := tenant.FromContext(c.Request.Context()); ok {
+		return t
+	}
+	return tenant.Catalog()
+}
+
+// respondTenantError responde 404 si la organización no existe o el usuario no
+// es miembro, para no revelar organizaciones ajenas
+func respondTenantError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repositories.ErrOrganizationNotFound),
+		errors.Is(err, repositories.ErrOrganizationMemberNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Error:   "Organización no encontrada",
+			Code:    "organization_not_found",
+		})
+	default:
+		log.Printf("Error resolviendo la organización: %v", err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Error:   "Error resolviendo la organización",
+			Code:    apperror.CodeInternal,
+		})
+	}
+	c.Abort()
+}
@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// Usage describe el subcomando migrate
+const Usage = `Uso: migrate <comando>
+
+Comandos:
+  status     Lista las migraciones y su estado
+  up         Aplica las migraciones pendientes
+  down [n]   Revierte las últimas n migraciones (1 por defecto)
+  redo       Revierte y vuelve a aplicar la última migración
+  baseline v Registra como aplicadas, sin ejecutarlas, las migraciones hasta v
+             (para bases de datos cuyo esquema se creó a mano)`
+
+// Run ejecuta el subcomando migrate con sus argumentos (status, up, down [n],
+// redo, baseline v)
+func Run(ctx context.Context, m *Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("falta el comando\n\n%s", Usage)
+	}
+
+	switch args[0] {
+	case "status":
+		return printStatus(ctx, m, out)
+
+	case "up":
+		count, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			fmt.Fprintln(out, "✅ El esquema está actualizado")
+		} else {
+			fmt.Fprintf(out, "✅ %d migraciones aplicadas\n", count)
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("down: la cantidad debe ser un entero positivo")
+			}
+			steps = n
+		}
+		count, err := m.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "✅ %d migraciones revertidas\n", count)
+		return nil
+
+	case "redo":
+		if err := m.Redo(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "✅ Última migración reaplicada")
+		return nil
+
+	case "baseline":
+		if len(args) < 2 {
+			return fmt.Errorf("baseline: falta la versión\n\n%s", Usage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 1 {
+			return fmt.Errorf("baseline: la versión debe ser un entero positivo")
+		}
+		count, err := m.Baseline(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "✅ %d migraciones registradas como aplicadas\n", count)
+		return nil
+	}
+
+	return fmt.Errorf("comando desconocido %q\n\n%s", args[0], Usage)
+}
+
+func printStatus(ctx context.Context, m *Migrator, out io.Writer) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MIGRACIÓN\tESTADO\tAPLICADA")
+	pending := 0
+	for _, s := range statuses {
+		appliedAt := "-"
+		if s.Applied() {
+			appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		} else {
+			pending++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.State(), appliedAt)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%d migraciones, %d pendientes\n", len(statuses), pending)
+	return nil
+}
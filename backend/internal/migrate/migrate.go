@@ -0,0 +1,498 @@
+// Package migrate aplica las migraciones SQL versionadas del esquema. Registra
+// las aplicadas en schema_migrations con el checksum de su archivo, para detectar
+// migraciones editadas después de aplicarse, y toma un advisory lock de
+// PostgreSQL para que varias réplicas no migren a la vez.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrChecksumMismatch indica que se editó una migración ya aplicada
+	ErrChecksumMismatch = errors.New("hay migraciones aplicadas que fueron modificadas")
+	// ErrNoApplied se devuelve al revertir sin migraciones aplicadas
+	ErrNoApplied = errors.New("no hay migraciones aplicadas")
+	// ErrUnknownVersion se devuelve al revertir una versión que este binario no conoce
+	ErrUnknownVersion = errors.New("la migración aplicada no existe en este binario")
+	// ErrUnversionedSchema indica un esquema creado sin el migrador (por ejemplo
+	// aplicando 002 a mano): hay que registrarlo con baseline antes de migrar
+	ErrUnversionedSchema = errors.New("la base de datos ya tiene el esquema pero schema_migrations está vacía: registra las migraciones aplicadas con \"migrate baseline <versión>\"")
+	// ErrBaselineVersion indica una versión de baseline que no existe o ya fue superada
+	ErrBaselineVersion = errors.New("versión de baseline inválida")
+)
+
+// Clave del advisory lock que comparten todas las réplicas
+const lockKey int64 = 0x53494e54524f4e49 // "SINTRONI"
+
+// Tabla que crea 002: si existe sin migraciones registradas, el esquema se
+// creó a mano
+const schemaMarkerTable = "plant_species"
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+)`
+
+// Nombre de los archivos: 016_nombre_descriptivo.up.sql / .down.sql
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration es una versión del esquema con su aplicación y su reversión
+type Migration struct {
+	Version  int64
+	Name     string // Nombre del archivo sin la dirección (p. ej. "003_sites_climate")
+	Up       string
+	Down     string
+	Checksum string // SHA-256 del archivo up
+}
+
+// Status es el estado de una migración en la base de datos
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	Modified  bool // El archivo up cambió después de aplicarse
+	Missing   bool // Aplicada pero sin archivo en este binario
+}
+
+// Applied indica si la migración está aplicada
+func (s Status) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+// State describe el estado para listados
+func (s Status) State() string {
+	switch {
+	case s.Missing:
+		return "sin archivo"
+	case s.Modified:
+		return "modificada"
+	case s.Applied():
+		return "aplicada"
+	}
+	return "pendiente"
+}
+
+type appliedMigration struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator aplica las migraciones de un directorio a una base de datos
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New lee las migraciones de fsys (un archivo up y uno down por versión)
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo migraciones: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nombre de migración inválido %q (se espera NNN_nombre.up.sql o NNN_nombre.down.sql)", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("versión inválida en %q: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo %s: %w", entry.Name(), err)
+		}
+
+		name := match[1] + "_" + match[2]
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: name}
+			byVersion[version] = mig
+		} else if mig.Name != name {
+			return nil, fmt.Errorf("versión %d duplicada: %s y %s", version, mig.Name, name)
+		}
+
+		if match[3] == "up" {
+			sum := sha256.Sum256(content)
+			mig.Up = string(content)
+			mig.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Checksum == "" {
+			return nil, fmt.Errorf("la migración %s no tiene archivo up", mig.Name)
+		}
+		if strings.TrimSpace(mig.Down) == "" {
+			return nil, fmt.Errorf("la migración %s no tiene archivo down", mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrations devuelve las migraciones conocidas, ordenadas por versión
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status devuelve el estado de cada migración conocida o aplicada. No toma el
+// lock: puede ejecutarse mientras otra réplica migra.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return m.status(applied), nil
+}
+
+// Pending devuelve cuántas migraciones faltan aplicar
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, s := range statuses {
+		if !s.Applied() {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// Up aplica las migraciones pendientes en orden, cada una en su transacción, y
+// devuelve cuántas aplicó. Falla sin aplicar nada si alguna aplicada fue modificada.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			var exists bool
+			if err := conn.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", schemaMarkerTable).Scan(&exists); err != nil {
+				return fmt.Errorf("error consultando el esquema: %w", err)
+			}
+			if exists {
+				return ErrUnversionedSchema
+			}
+		}
+
+		var modified []string
+		for _, s := range m.status(applied) {
+			switch {
+			case s.Missing:
+				// Una réplica más nueva ya migró: este binario no la necesita
+				log.Printf("⚠️ Migración %s aplicada pero desconocida para este binario", s.Name)
+			case s.Modified:
+				modified = append(modified, s.Name)
+			}
+		}
+		if len(modified) > 0 {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.Join(modified, ", "))
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Baseline registra como aplicadas, sin ejecutarlas, las migraciones hasta
+// version inclusive. Sirve para adoptar una base de datos cuyo esquema se creó
+// a mano; devuelve cuántas registró.
+func (m *Migrator) Baseline(ctx context.Context, version int64) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		targets, err := m.baselineTargets(applied, version)
+		if err != nil {
+			return err
+		}
+		for _, mig := range targets {
+			if _, err := conn.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+				mig.Version, mig.Name, mig.Checksum); err != nil {
+				return fmt.Errorf("error registrando migración %s: %w", mig.Name, err)
+			}
+			log.Printf("📌 Migración %s registrada como aplicada", mig.Name)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// baselineTargets devuelve las migraciones hasta version que faltan registrar.
+// La versión debe existir y no puede haber aplicadas posteriores: el baseline
+// no deja huecos en la historia.
+func (m *Migrator) baselineTargets(applied map[int64]appliedMigration, version int64) ([]Migration, error) {
+	if _, ok := m.find(version); !ok {
+		return nil, fmt.Errorf("%w: %d no es una migración conocida", ErrBaselineVersion, version)
+	}
+	if last, ok := lastApplied(applied); ok && last.version > version {
+		return nil, fmt.Errorf("%w: ya está aplicada %s", ErrBaselineVersion, last.name)
+	}
+
+	var targets []Migration
+	for _, mig := range m.migrations {
+		if mig.Version > version {
+			break
+		}
+		if _, ok := applied[mig.Version]; !ok {
+			targets = append(targets, mig)
+		}
+	}
+	return targets, nil
+}
+
+// Down revierte las últimas steps migraciones aplicadas y devuelve cuántas revirtió
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		count, err = m.down(ctx, conn, steps)
+		return err
+	})
+	return count, err
+}
+
+// Redo revierte y vuelve a aplicar la última migración aplicada (con el
+// contenido actual de su archivo)
+func (m *Migrator) Redo(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		last, ok := lastApplied(applied)
+		if !ok {
+			return ErrNoApplied
+		}
+		mig, ok := m.find(last.version)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownVersion, last.name)
+		}
+		if _, err := m.down(ctx, conn, 1); err != nil {
+			return err
+		}
+		return m.apply(ctx, conn, mig)
+	})
+}
+
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn, steps int) (int, error) {
+	count := 0
+	for ; count < steps; count++ {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return count, err
+		}
+		last, ok := lastApplied(applied)
+		if !ok {
+			if count == 0 {
+				return 0, ErrNoApplied
+			}
+			break
+		}
+		mig, ok := m.find(last.version)
+		if !ok {
+			return count, fmt.Errorf("%w: %s", ErrUnknownVersion, last.name)
+		}
+		if err := m.revert(ctx, conn, mig); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// apply ejecuta el archivo up y lo registra en la misma transacción
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	start := time.Now()
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+			mig.Version, mig.Name, mig.Checksum)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error aplicando migración %s: %w", mig.Name, err)
+	}
+	log.Printf("⬆️ Migración %s aplicada (%v)", mig.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// revert ejecuta el archivo down y borra el registro en la misma transacción
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	start := time.Now()
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error revirtiendo migración %s: %w", mig.Name, err)
+	}
+	log.Printf("⬇️ Migración %s revertida (%v)", mig.Name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// withLock ejecuta fn en una conexión que tiene el advisory lock de las
+// migraciones. Las demás réplicas esperan a que termine.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error obteniendo conexión para migrar: %w", err)
+	}
+	defer conn.Close()
+
+	// El lock es de la sesión: se toma y se libera en la misma conexión
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("error tomando el lock de migraciones: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			log.Printf("⚠️ Error liberando el lock de migraciones: %v", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("error creando schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// applied lee las migraciones registradas; sin la tabla no hay ninguna
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]appliedMigration, error) {
+	var exists bool
+	if err := q.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error consultando schema_migrations: %w", err)
+	}
+	applied := make(map[int64]appliedMigration)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error consultando schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.version, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("error leyendo schema_migrations: %w", err)
+		}
+		applied[a.version] = a
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) status(applied map[int64]appliedMigration) []Status {
+	statuses := make([]Status, 0, len(m.migrations)+len(applied))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if a, ok := applied[mig.Version]; ok {
+			appliedAt := a.appliedAt
+			s.AppliedAt = &appliedAt
+			s.Modified = a.checksum != mig.Checksum
+		}
+		statuses = append(statuses, s)
+	}
+	for version, a := range applied {
+		if _, ok := m.find(version); !ok {
+			appliedAt := a.appliedAt
+			statuses = append(statuses, Status{Version: version, Name: a.name, AppliedAt: &appliedAt, Missing: true})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+func lastApplied(applied map[int64]appliedMigration) (appliedMigration, bool) {
+	var last appliedMigration
+	found := false
+	for _, a := range applied {
+		if !found || a.version > last.version {
+			last, found = a, true
+		}
+	}
+	return last, found
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,227 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// testMigrator tiene las versiones 2, 3 y 10, con los archivos desordenados
+func testMigrator(t *testing.T) *Migrator {
+	t.Helper()
+	m, err := New(nil, fstest.MapFS{
+		"010_api_keys.up.sql":           file("CREATE TABLE api_keys ();"),
+		"010_api_keys.down.sql":         file("DROP TABLE api_keys;"),
+		"002_new_model_schema.up.sql":   file("CREATE TABLE sites ();"),
+		"002_new_model_schema.down.sql": file("DROP TABLE sites;"),
+		"003_sites_climate.up.sql":      file("ALTER TABLE sites ADD climate TEXT;"),
+		"003_sites_climate.down.sql":    file("ALTER TABLE sites DROP climate;"),
+		"README.md":                     file("# Migraciones"),
+		"20250717135942_old.sql.old":    file("-- ignorado"),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return m
+}
+
+func TestLoad(t *testing.T) {
+	m := testMigrator(t)
+
+	want := []struct {
+		version int64
+		name    string
+	}{
+		{2, "002_new_model_schema"},
+		{3, "003_sites_climate"},
+		{10, "010_api_keys"},
+	}
+	got := m.Migrations()
+	if len(got) != len(want) {
+		t.Fatalf("migraciones = %+v, se esperaban %d", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Version != w.version || got[i].Name != w.name {
+			t.Errorf("migración %d = %d %s, se esperaba %d %s", i, got[i].Version, got[i].Name, w.version, w.name)
+		}
+	}
+
+	sites := got[0]
+	if sites.Up != "CREATE TABLE sites ();" || sites.Down != "DROP TABLE sites;" {
+		t.Errorf("002: up = %q, down = %q", sites.Up, sites.Down)
+	}
+	if sites.Checksum != checksum("CREATE TABLE sites ();") {
+		t.Errorf("002: checksum = %s, se esperaba el SHA-256 del archivo up", sites.Checksum)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "nombre inválido",
+			fsys: fstest.MapFS{"002_schema.sql": file("SELECT 1;")},
+			want: "nombre de migración inválido",
+		},
+		{
+			name: "nombre con mayúsculas",
+			fsys: fstest.MapFS{"002_Schema.up.sql": file("SELECT 1;")},
+			want: "nombre de migración inválido",
+		},
+		{
+			name: "sin archivo up",
+			fsys: fstest.MapFS{"002_schema.down.sql": file("SELECT 1;")},
+			want: "no tiene archivo up",
+		},
+		{
+			name: "sin archivo down",
+			fsys: fstest.MapFS{"002_schema.up.sql": file("SELECT 1;")},
+			want: "no tiene archivo down",
+		},
+		{
+			name: "archivo down vacío",
+			fsys: fstest.MapFS{
+				"002_schema.up.sql":   file("SELECT 1;"),
+				"002_schema.down.sql": file("  \n"),
+			},
+			want: "no tiene archivo down",
+		},
+		{
+			name: "versión duplicada",
+			fsys: fstest.MapFS{
+				"002_schema.up.sql":   file("SELECT 1;"),
+				"002_schema.down.sql": file("SELECT 1;"),
+				"02_otro.up.sql":      file("SELECT 2;"),
+				"02_otro.down.sql":    file("SELECT 2;"),
+			},
+			want: "duplicada",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(nil, tc.fsys)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, se esperaba %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	m := testMigrator(t)
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	applied := map[int64]appliedMigration{
+		2:  {version: 2, name: "002_new_model_schema", checksum: checksum("CREATE TABLE sites ();"), appliedAt: at},
+		3:  {version: 3, name: "003_sites_climate", checksum: "editada", appliedAt: at},
+		16: {version: 16, name: "016_de_una_replica_nueva", checksum: "x", appliedAt: at},
+	}
+
+	want := []struct {
+		name    string
+		state   string
+		applied bool
+	}{
+		{"002_new_model_schema", "aplicada", true},
+		{"003_sites_climate", "modificada", true},
+		{"010_api_keys", "pendiente", false},
+		{"016_de_una_replica_nueva", "sin archivo", true},
+	}
+
+	got := m.status(applied)
+	if len(got) != len(want) {
+		t.Fatalf("status = %+v, se esperaban %d", got, len(want))
+	}
+	for i, w := range want {
+		s := got[i]
+		if s.Name != w.name || s.State() != w.state || s.Applied() != w.applied {
+			t.Errorf("status[%d] = %s %s (aplicada %t), se esperaba %s %s (aplicada %t)",
+				i, s.Name, s.State(), s.Applied(), w.name, w.state, w.applied)
+		}
+	}
+}
+
+func TestBaselineTargets(t *testing.T) {
+	m := testMigrator(t)
+	applied := func(versions ...int64) map[int64]appliedMigration {
+		result := make(map[int64]appliedMigration)
+		for _, v := range versions {
+			mig, _ := m.find(v)
+			result[v] = appliedMigration{version: v, name: mig.Name, checksum: mig.Checksum}
+		}
+		return result
+	}
+
+	cases := []struct {
+		name    string
+		applied map[int64]appliedMigration
+		version int64
+		want    []int64
+		wantErr error
+	}{
+		{"esquema creado a mano", applied(), 2, []int64{2}, nil},
+		{"hasta una versión intermedia", applied(), 3, []int64{2, 3}, nil},
+		{"completa lo que falta", applied(2), 10, []int64{3, 10}, nil},
+		{"ya registrada", applied(2, 3), 3, nil, nil},
+		{"versión desconocida", applied(), 4, nil, ErrBaselineVersion},
+		{"anterior a una aplicada", applied(2, 3), 2, nil, ErrBaselineVersion},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			targets, err := m.baselineTargets(tc.applied, tc.version)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, se esperaba %v", err, tc.wantErr)
+			}
+			var got []int64
+			for _, mig := range targets {
+				got = append(got, mig.Version)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("versiones = %v, se esperaba %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("versiones = %v, se esperaba %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunValidatesArguments(t *testing.T) {
+	cases := map[string][]string{
+		"sin comando":          nil,
+		"comando desconocido":  {"sideways"},
+		"down no numérico":     {"down", "dos"},
+		"down cero":            {"down", "0"},
+		"baseline sin versión": {"baseline"},
+		"baseline no numérico": {"baseline", "v2"},
+	}
+
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Los argumentos inválidos fallan antes de tocar la base de datos
+			if err := Run(t.Context(), &Migrator{}, args, &strings.Builder{}); err == nil {
+				t.Error("se esperaba un error")
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrAPIKeyNotFound se devuelve cuando la API key no existe o es de otro usuario
+var ErrAPIKeyNotFound = apperror.NotFound("api_key_not_found", "API key no encontrada")
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository() *APIKeyRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &APIKeyRepository{
+		db: db.DB,
+	}
+}
+
+// Create guarda una API key nueva
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	if err := r.db.Create(key).Error; err != nil {
+		return fmt.Errorf("error guardando API key: %w", err)
+	}
+	return nil
+}
+
+// GetByHash obtiene una API key por el hash de la clave
+func (r *APIKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo API key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// GetByUser obtiene las API keys del usuario, incluidas las revocadas y vencidas
+func (r *APIKeyRepository) GetByUser(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("error obteniendo API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke revoca una API key del usuario
+func (r *APIKeyRepository) Revoke(userID, id uint, now time.Time) error {
+	result := r.db.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", now))
+	if result.Error != nil {
+		return fmt.Errorf("error revocando API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed registra el último uso de la clave
+func (r *APIKeyRepository) TouchLastUsed(id uint, at time.Time) error {
+	if err := r.db.Model(&models.APIKey{}).Where("id = ?", id).Update("last_used_at", at).Error; err != nil {
+		return fmt.Errorf("error actualizando API key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+)
+
+// AuditRepository consulta la auditoría. Los eventos los escriben los callbacks
+// del paquete audit; este repositorio solo los lee.
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository() *AuditRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &AuditRepository{
+		db: db.DB,
+	}
+}
+
+// GetAll obtiene los eventos de auditoría con filtros opcionales, del más reciente al más antiguo
+func (r *AuditRepository) GetAll(filters AuditFilters) ([]models.AuditEvent, int64, error) {
+	var events []models.AuditEvent
+	var total int64
+
+	query := r.db.Model(&models.AuditEvent{})
+
+	// Aplicar filtros
+	if filters.EntityType != "" {
+		query = query.Where("entity_type = ?", filters.EntityType)
+	}
+	if filters.EntityID != "" {
+		query = query.Where("entity_id = ?", filters.EntityID)
+	}
+	if filters.ActorID != 0 {
+		query = query.Where("actor_id = ?", filters.ActorID)
+	}
+	if filters.Action != "" {
+		query = query.Where("action = ?", filters.Action)
+	}
+	if filters.Field != "" {
+		// Eventos que modificaron la columna (equivale al operador ? de jsonb)
+		query = query.Where("jsonb_exists(changes, ?)", filters.Field)
+	}
+	if filters.RequestID != "" {
+		query = query.Where("request_id = ?", filters.RequestID)
+	}
+	if filters.Since != nil {
+		query = query.Where("created_at >= ?", *filters.Since)
+	}
+	if filters.Until != nil {
+		query = query.Where("created_at < ?", *filters.Until)
+	}
+
+	// Contar total antes de paginación
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("error contando eventos de auditoría: %w", err)
+	}
+
+	// Aplicar paginación
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Find(&events).Error; err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo eventos de auditoría: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// AuditFilters estructura para filtros de búsqueda de eventos de auditoría
+type AuditFilters struct {
+	EntityType string
+	EntityID   string
+	ActorID    uint
+	Action     string
+	Field      string // Columna modificada, p. ej. "stratum"
+	RequestID  string
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+	Offset     int
+}
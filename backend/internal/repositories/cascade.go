@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+)
+
+// El ON DELETE CASCADE del esquema SQL solo se dispara con borrados físicos.
+// Como todos los modelos usan soft delete, las funciones de este archivo
+// propagan el deleted_at a los hijos dentro de la misma transacción.
+
+// cascadeDeleteSites elimina (soft delete) sitios junto con sus plantaciones
+func cascadeDeleteSites(tx *gorm.DB, siteIDs []uint) error {
+	if len(siteIDs) == 0 {
+		return nil
+	}
+
+	var plantationIDs []uint
+	if err := tx.Model(&models.Plantation{}).Where("site_id IN ?", siteIDs).Pluck("id", &plantationIDs).Error; err != nil {
+		return fmt.Errorf("error obteniendo plantaciones del sitio: %w", err)
+	}
+
+	if err := cascadeDeletePlantations(tx, plantationIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Where("id IN ?", siteIDs).Delete(&models.Site{}).Error; err != nil {
+		return fmt.Errorf("error eliminando sitios: %w", err)
+	}
+
+	return nil
+}
+
+// cascadeDeletePlantations elimina (soft delete) plantaciones junto con sus
+// parcelas, instancias de plantas y plantillas de sugerencias
+func cascadeDeletePlantations(tx *gorm.DB, plantationIDs []uint) error {
+	if len(plantationIDs) == 0 {
+		return nil
+	}
+
+	var plotIDs []uint
+	if err := tx.Model(&models.Plot{}).Where("plantation_id IN ?", plantationIDs).Pluck("id", &plotIDs).Error; err != nil {
+		return fmt.Errorf("error obteniendo parcelas de la plantación: %w", err)
+	}
+
+	if err := cascadeDeletePlots(tx, plotIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Where("plantation_id IN ?", plantationIDs).Delete(&models.SuggestionTemplate{}).Error; err != nil {
+		return fmt.Errorf("error eliminando plantillas de sugerencias: %w", err)
+	}
+
+	if err := tx.Where("id IN ?", plantationIDs).Delete(&models.Plantation{}).Error; err != nil {
+		return fmt.Errorf("error eliminando plantaciones: %w", err)
+	}
+
+	return nil
+}
+
+// cascadeDeletePlots elimina (soft delete) parcelas junto con sus instancias de plantas
+func cascadeDeletePlots(tx *gorm.DB, plotIDs []uint) error {
+	if len(plotIDs) == 0 {
+		return nil
+	}
+
+	if err := tx.Where("plot_id IN ?", plotIDs).Delete(&models.PlantInstance{}).Error; err != nil {
+		return fmt.Errorf("error eliminando instancias de plantas: %w", err)
+	}
+
+	if err := tx.Where("id IN ?", plotIDs).Delete(&models.Plot{}).Error; err != nil {
+		return fmt.Errorf("error eliminando parcelas: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCredentialNotFound se devuelve cuando la integración no tiene credenciales guardadas
+var ErrCredentialNotFound = apperror.NotFound("credentials_not_configured", "credenciales no configuradas")
+
+type CredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewCredentialRepository() *CredentialRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &CredentialRepository{
+		db: db.DB,
+	}
+}
+
+// GetByProvider obtiene las credenciales (con el secreto cifrado) de una integración
+func (r *CredentialRepository) GetByProvider(provider string) (*models.IntegrationCredential, error) {
+	var credential models.IntegrationCredential
+
+	if err := r.db.Where("provider = ?", provider).First(&credential).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCredentialNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo credenciales: %w", err)
+	}
+
+	return &credential, nil
+}
+
+// Save crea o reemplaza las credenciales de la integración
+func (r *CredentialRepository) Save(credential *models.IntegrationCredential) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider"}},
+		DoUpdates: clause.AssignmentColumns([]string{"key_id", "secret_encrypted", "updated_by", "updated_at"}),
+	}).Create(credential).Error
+	if err != nil {
+		return fmt.Errorf("error guardando credenciales: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"gorm.io/gorm"
+)
+
+// purgeSteps borra físicamente los registros con soft delete anterior al corte.
+// Van de los hijos a los padres y un padre solo se borra si ya no le quedan
+// hijos (activos o eliminados hace poco): el ON DELETE CASCADE del esquema
+// borraría también esos hijos.
+var purgeSteps = []struct {
+	table string
+	sql   string
+}{
+	{"plant_instances", `DELETE FROM plant_instances WHERE deleted_at < ?`},
+	{"plots", `DELETE FROM plots WHERE deleted_at < ?
+		AND NOT EXISTS (SELECT 1 FROM plant_instances pi WHERE pi.plot_id = plots.id)`},
+	{"suggestion_templates", `DELETE FROM suggestion_templates WHERE deleted_at < ?`},
+	{"plantations", `DELETE FROM plantations WHERE deleted_at < ?
+		AND NOT EXISTS (SELECT 1 FROM plots p WHERE p.plantation_id = plantations.id)
+		AND NOT EXISTS (SELECT 1 FROM suggestion_templates st WHERE st.plantation_id = plantations.id)`},
+	{"sites", `DELETE FROM sites WHERE deleted_at < ?
+		AND NOT EXISTS (SELECT 1 FROM plantations pl WHERE pl.site_id = sites.id)`},
+	{"plant_species", `DELETE FROM plant_species WHERE deleted_at < ?
+		AND NOT EXISTS (SELECT 1 FROM plant_instances pi WHERE pi.species_id = plant_species.id)`},
+	{"users", `DELETE FROM users WHERE deleted_at < ?`},
+	{"organizations", `DELETE FROM organizations WHERE deleted_at < ?
+		AND NOT EXISTS (SELECT 1 FROM sites s WHERE s.organization_id = organizations.id)
+		AND NOT EXISTS (SELECT 1 FROM plant_species ps WHERE ps.organization_id = organizations.id)`},
+}
+
+// errPurgeDryRun revierte la transacción de una purga en modo dry-run
+var errPurgeDryRun = errors.New("dry-run")
+
+// PurgeCount es la cantidad de registros borrados de una tabla
+type PurgeCount struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// MaintenanceRepository agrupa las tareas de mantenimiento de la base de datos.
+// Usa SQL directo: no pasa por los filtros de organización ni por la auditoría.
+type MaintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository() *MaintenanceRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &MaintenanceRepository{
+		db: db.DB,
+	}
+}
+
+// PurgeDeleted borra físicamente los registros eliminados (soft delete) antes
+// de before, en una sola transacción. Con dryRun cuenta lo que borraría y
+// revierte la transacción.
+func (r *MaintenanceRepository) PurgeDeleted(before time.Time, dryRun bool) ([]PurgeCount, error) {
+	counts := make([]PurgeCount, 0, len(purgeSteps))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, step := range purgeSteps {
+			result := tx.Exec(step.sql, before)
+			if result.Error != nil {
+				return fmt.Errorf("error purgando %s: %w", step.table, result.Error)
+			}
+			counts = append(counts, PurgeCount{Table: step.table, Rows: result.RowsAffected})
+		}
+
+		if dryRun {
+			return errPurgeDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPurgeDryRun) {
+		return nil, err
+	}
+
+	return counts, nil
+}
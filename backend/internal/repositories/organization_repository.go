@@ -0,0 +1,269 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrOrganizationNotFound se devuelve cuando la organización no existe o fue eliminada
+	ErrOrganizationNotFound = apperror.NotFound("organization_not_found", "organización no encontrada")
+	// ErrOrganizationSlugTaken se devuelve cuando el slug pedido ya está en uso
+	ErrOrganizationSlugTaken = apperror.Conflict("organization_slug_taken", "ya existe una organización con ese slug")
+	// ErrOrganizationMemberNotFound se devuelve cuando el usuario no pertenece a la organización
+	ErrOrganizationMemberNotFound = apperror.NotFound("organization_member_not_found", "el usuario no es miembro de la organización")
+	// ErrOrganizationMemberExists se devuelve al agregar a alguien que ya es miembro
+	ErrOrganizationMemberExists = apperror.Conflict("organization_member_exists", "el usuario ya es miembro de la organización")
+	// ErrLastOrganizationOwner se devuelve al quitar al único owner de la organización
+	ErrLastOrganizationOwner = apperror.Conflict("last_organization_owner", "la organización debe tener al menos un owner")
+)
+
+// Las organizaciones son la raíz del aislamiento entre tenants, así que sus
+// tablas no se filtran por tenant: cada consulta indica la organización o el
+// usuario explícitamente.
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository() *OrganizationRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &OrganizationRepository{
+		db: db.DB,
+	}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría
+func (r *OrganizationRepository) WithContext(ctx context.Context) *OrganizationRepository {
+	return &OrganizationRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create crea una organización con ownerID como owner. Sin slug se genera uno
+// a partir del nombre.
+func (r *OrganizationRepository) Create(org *models.Organization, ownerID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createOrganization(tx, org, ownerID)
+	})
+}
+
+// GetByID obtiene una organización por ID
+func (r *OrganizationRepository) GetByID(id uint) (*models.Organization, error) {
+	var org models.Organization
+
+	if err := r.db.First(&org, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo organización: %w", err)
+	}
+
+	return &org, nil
+}
+
+// GetBySlug obtiene una organización por su slug
+func (r *OrganizationRepository) GetBySlug(slug string) (*models.Organization, error) {
+	var org models.Organization
+
+	if err := r.db.Where("slug = ?", slug).First(&org).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo organización: %w", err)
+	}
+
+	return &org, nil
+}
+
+// GetForUser obtiene las organizaciones del usuario con su rol en cada una
+func (r *OrganizationRepository) GetForUser(userID uint) ([]models.Organization, error) {
+	var orgs []models.Organization
+
+	err := r.db.Model(&models.Organization{}).
+		Select("organizations.*, organization_members.role AS role").
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name ASC").
+		Scan(&orgs).Error
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo organizaciones: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// GetRole obtiene el rol del usuario en la organización
+func (r *OrganizationRepository) GetRole(orgID, userID uint) (string, error) {
+	var roles []string
+
+	err := r.db.Model(&models.OrganizationMember{}).
+		Joins("JOIN organizations ON organizations.id = organization_members.organization_id AND organizations.deleted_at IS NULL").
+		Where("organization_members.organization_id = ? AND organization_members.user_id = ?", orgID, userID).
+		Limit(1).
+		Pluck("organization_members.role", &roles).Error
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo miembro de la organización: %w", err)
+	}
+	if len(roles) == 0 {
+		return "", ErrOrganizationMemberNotFound
+	}
+
+	return roles[0], nil
+}
+
+// GetMembers obtiene los miembros de una organización con sus usuarios
+func (r *OrganizationRepository) GetMembers(orgID uint) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+
+	err := r.db.Preload("User").
+		Where("organization_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo miembros de la organización: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddMember agrega un miembro a la organización
+func (r *OrganizationRepository) AddMember(member *models.OrganizationMember) error {
+	var count int64
+	err := r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", member.OrganizationID, member.UserID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("error verificando miembro de la organización: %w", err)
+	}
+	if count > 0 {
+		return ErrOrganizationMemberExists
+	}
+
+	if err := r.db.Create(member).Error; err != nil {
+		return fmt.Errorf("error agregando miembro de la organización: %w", err)
+	}
+
+	return r.db.Preload("User").
+		Where("organization_id = ? AND user_id = ?", member.OrganizationID, member.UserID).
+		First(member).Error
+}
+
+// RemoveMember quita a un miembro de la organización junto con sus membresías en
+// los sitios de la organización. No permite quitar al último owner.
+func (r *OrganizationRepository) RemoveMember(orgID, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var owners []models.OrganizationMember
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("organization_id = ? AND (user_id = ? OR role = ?)", orgID, userID, models.OrganizationRoleOwner).
+			Find(&owners).Error
+		if err != nil {
+			return fmt.Errorf("error obteniendo miembro de la organización: %w", err)
+		}
+
+		var member *models.OrganizationMember
+		otherOwners := 0
+		for i := range owners {
+			switch {
+			case owners[i].UserID == userID:
+				member = &owners[i]
+			case owners[i].Role == models.OrganizationRoleOwner:
+				otherOwners++
+			}
+		}
+		if member == nil {
+			return ErrOrganizationMemberNotFound
+		}
+		if member.Role == models.OrganizationRoleOwner && otherOwners == 0 {
+			return ErrLastOrganizationOwner
+		}
+
+		// Con el tenant de la organización el borrado alcanza solo a sus sitios
+		orgTx := tx.WithContext(tenant.WithTenant(tx.Statement.Context, tenant.Organization(orgID)))
+		err = orgTx.Where("user_id = ?", userID).Delete(&models.SiteMember{}).Error
+		if err != nil {
+			return fmt.Errorf("error quitando miembro de los sitios: %w", err)
+		}
+
+		err = tx.Where("organization_id = ? AND user_id = ?", orgID, userID).
+			Delete(&models.OrganizationMember{}).Error
+		if err != nil {
+			return fmt.Errorf("error quitando miembro de la organización: %w", err)
+		}
+		return nil
+	})
+}
+
+// createOrganization crea la organización y su owner dentro de la transacción tx
+func createOrganization(tx *gorm.DB, org *models.Organization, ownerID uint) error {
+	slug, err := availableSlug(tx, org.Slug, org.Name)
+	if err != nil {
+		return err
+	}
+	org.Slug = slug
+
+	if err := tx.Create(org).Error; err != nil {
+		return fmt.Errorf("error creando organización: %w", err)
+	}
+
+	owner := models.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         ownerID,
+		Role:           models.OrganizationRoleOwner,
+	}
+	if err := tx.Create(&owner).Error; err != nil {
+		return fmt.Errorf("error registrando owner de la organización: %w", err)
+	}
+	return nil
+}
+
+var slugInvalidChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// availableSlug valida el slug pedido o, si está vacío, genera uno libre a partir
+// del nombre agregando un sufijo numérico si hace falta
+func availableSlug(tx *gorm.DB, requested, name string) (string, error) {
+	explicit := strings.TrimSpace(requested) != ""
+	base := requested
+	if !explicit {
+		base = name
+	}
+	base = strings.Trim(slugInvalidChars.ReplaceAllString(strings.ToLower(base), "-"), "-")
+	if len(base) > 90 {
+		base = strings.TrimRight(base[:90], "-")
+	}
+	if base == "" {
+		base = "organizacion"
+	}
+
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&models.Organization{}).Where("slug = ?", candidate).Count(&count).Error; err != nil {
+			return "", fmt.Errorf("error verificando slug: %w", err)
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		if explicit {
+			return "", ErrOrganizationSlugTaken
+		}
+	}
+	return "", ErrOrganizationSlugTaken
+}
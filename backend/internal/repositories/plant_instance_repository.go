@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPlantInstanceNotFound se devuelve cuando la instancia no existe o fue eliminada
+var ErrPlantInstanceNotFound = apperror.NotFound("plant_instance_not_found", "instancia de planta no encontrada")
+
+type PlantInstanceRepository struct {
+	db *gorm.DB
+}
+
+func NewPlantInstanceRepository() *PlantInstanceRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &PlantInstanceRepository{
+		db: db.DB,
+	}
+}
+
+// ForTenant devuelve el repositorio limitado a los datos del tenant t
+func (r *PlantInstanceRepository) ForTenant(t tenant.Tenant) *PlantInstanceRepository {
+	return &PlantInstanceRepository{db: withTenant(r.db, t)}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría. Debe llamarse antes de ForTenant.
+func (r *PlantInstanceRepository) WithContext(ctx context.Context) *PlantInstanceRepository {
+	return &PlantInstanceRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create crea una nueva instancia verificando que existan la parcela y la especie
+func (r *PlantInstanceRepository) Create(instance *models.PlantInstance) error {
+	if err := r.db.Select("id").First(&models.Plot{}, instance.PlotID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPlotNotFound
+		}
+		return fmt.Errorf("error obteniendo parcela: %w", err)
+	}
+
+	if err := r.db.Select("id").First(&models.PlantSpecies{}, instance.SpeciesID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSpeciesNotFound
+		}
+		return fmt.Errorf("error obteniendo especie: %w", err)
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(instance).Error; err != nil {
+			return fmt.Errorf("error creando instancia de planta: %w", err)
+		}
+
+		// El alta abre la historia de estados de la instancia
+		occurredAt := instance.CreatedAt
+		if instance.StatusChangedAt != nil {
+			occurredAt = *instance.StatusChangedAt
+		}
+		event := models.PlantInstanceEvent{
+			PlantInstanceID: instance.ID,
+			ToStatus:        instance.Status,
+			Actor:           instance.StatusChangedBy,
+			Source:          models.EventSourceAPI,
+			OccurredAt:      occurredAt,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return fmt.Errorf("error registrando evento de estado: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByPlot obtiene las instancias de una parcela con filtros opcionales
+func (r *PlantInstanceRepository) GetByPlot(plotID uint, filters PlantInstanceFilters) ([]models.PlantInstance, int64, error) {
+	var instances []models.PlantInstance
+	var total int64
+
+	if err := r.db.Select("id").First(&models.Plot{}, plotID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrPlotNotFound
+		}
+		return nil, 0, fmt.Errorf("error obteniendo parcela: %w", err)
+	}
+
+	query := r.db.Model(&models.PlantInstance{}).Where("plot_id = ?", plotID)
+
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+
+	if filters.Role != "" {
+		query = query.Where("role = ?", filters.Role)
+	}
+
+	if filters.SpeciesID > 0 {
+		query = query.Where("species_id = ?", filters.SpeciesID)
+	}
+
+	// Contar total antes de paginación
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("error contando instancias de plantas: %w", err)
+	}
+
+	// Aplicar paginación
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	query = query.Preload("Species").Order(`"order" ASC, created_at ASC`)
+
+	if err := query.Find(&instances).Error; err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo instancias de plantas: %w", err)
+	}
+
+	return instances, total, nil
+}
+
+// GetByID obtiene una instancia de planta por ID, incluyendo su especie
+func (r *PlantInstanceRepository) GetByID(id uint) (*models.PlantInstance, error) {
+	var instance models.PlantInstance
+
+	if err := r.db.Preload("Species").First(&instance, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlantInstanceNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo instancia de planta: %w", err)
+	}
+
+	return &instance, nil
+}
+
+// Update actualiza los datos de una instancia (nunca su estado)
+func (r *PlantInstanceRepository) Update(id uint, updates map[string]interface{}) (*models.PlantInstance, error) {
+	instance, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Model(instance).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("error actualizando instancia de planta: %w", err)
+	}
+
+	return r.GetByID(id)
+}
+
+// Transition cambia el estado de una instancia aplicando la máquina de estados
+// y deja el cambio registrado en plant_instance_events.
+// La fila se bloquea para que dos transiciones concurrentes no partan del mismo estado.
+func (r *PlantInstanceRepository) Transition(id uint, status, actor, notes string, at time.Time) (*models.PlantInstance, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var instance models.PlantInstance
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&instance, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPlantInstanceNotFound
+			}
+			return fmt.Errorf("error obteniendo instancia de planta: %w", err)
+		}
+
+		previous := instance.Status
+		if err := instance.TransitionTo(status, actor, at); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":            instance.Status,
+			"planted_at":        instance.PlantedAt,
+			"status_changed_at": instance.StatusChangedAt,
+			"status_changed_by": instance.StatusChangedBy,
+		}
+		if err := tx.Model(&instance).Updates(updates).Error; err != nil {
+			return fmt.Errorf("error actualizando estado de la instancia: %w", err)
+		}
+
+		event := models.PlantInstanceEvent{
+			PlantInstanceID: instance.ID,
+			FromStatus:      previous,
+			ToStatus:        instance.Status,
+			Actor:           actor,
+			Source:          models.EventSourceAPI,
+			Notes:           notes,
+			OccurredAt:      at,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return fmt.Errorf("error registrando evento de estado: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+// Events obtiene la historia de estados de una instancia en orden cronológico
+func (r *PlantInstanceRepository) Events(id uint) ([]models.PlantInstanceEvent, error) {
+	var events []models.PlantInstanceEvent
+
+	err := r.db.Where("plant_instance_id = ?", id).
+		Order("occurred_at ASC, id ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo historia de estados: %w", err)
+	}
+
+	return events, nil
+}
+
+// Delete elimina una instancia de planta (soft delete)
+func (r *PlantInstanceRepository) Delete(id uint) error {
+	instance, err := r.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.db.Delete(instance).Error; err != nil {
+		return fmt.Errorf("error eliminando instancia de planta: %w", err)
+	}
+
+	return nil
+}
+
+// PlantInstanceFilters estructura para filtros de búsqueda de instancias
+type PlantInstanceFilters struct {
+	Status    string
+	Role      string
+	SpeciesID uint
+	Limit     int
+	Offset    int
+}
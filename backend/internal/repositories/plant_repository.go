@@ -1,16 +1,50 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
 	"github.com/deibys/sintronia/pkg/models"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// Códigos de error de PostgreSQL que indican que se rechazó el registro y no
+// que falló la conexión
+const (
+	pgUniqueViolation    = "23505"
+	pgClassIntegrity     = "23" // Restricciones: unique, foreign key, check, not null
+	pgClassDataException = "22" // Valores fuera de rango o mal formados
+)
+
+var (
+	// ErrSpeciesNotFound se devuelve cuando la especie no existe o no es visible para la organización
+	ErrSpeciesNotFound = apperror.NotFound("species_not_found", "especie no encontrada")
+	// ErrSpeciesExternalRefTaken se devuelve cuando ya hay una especie con el mismo external_ref
+	ErrSpeciesExternalRefTaken = apperror.Conflict("species_external_ref_taken", "ya existe una especie con ese external_ref")
+	// ErrSpeciesRejected se devuelve cuando la base de datos rechaza los datos de la especie
+	// (restricciones CHECK, valores fuera de rango)
+	ErrSpeciesRejected = apperror.BadRequest("species_rejected", "la base de datos rechazó los datos de la especie")
+	// ErrSpeciesReadOnly se devuelve al modificar una especie del catálogo global desde una organización
+	ErrSpeciesReadOnly = apperror.Forbidden("species_read_only", "las especies del catálogo global son de solo lectura; crea un override para modificarla")
+	// ErrSpeciesNotGlobal se devuelve al sobrescribir una especie que no es del catálogo global
+	ErrSpeciesNotGlobal = apperror.Conflict("species_not_global", "solo se pueden sobrescribir especies del catálogo global")
+	// ErrSpeciesOverrideExists se devuelve cuando la organización ya sobrescribió la especie
+	ErrSpeciesOverrideExists = apperror.Conflict("species_override_exists", "la organización ya tiene un override de esta especie")
+)
+
+// PlantRepository accede al catálogo de especies. Con el tenant de una
+// organización ve el catálogo global más sus especies privadas, y solo puede
+// modificar las privadas.
 type PlantRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	tenant tenant.Tenant
 }
 
 func NewPlantRepository() *PlantRepository {
@@ -25,20 +59,47 @@ func NewPlantRepository() *PlantRepository {
 	}
 }
 
+// ForTenant devuelve el repositorio limitado al catálogo que ve el tenant t
+func (r *PlantRepository) ForTenant(t tenant.Tenant) *PlantRepository {
+	return &PlantRepository{db: withTenant(r.db, t), tenant: t}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría. Debe llamarse antes de ForTenant.
+func (r *PlantRepository) WithContext(ctx context.Context) *PlantRepository {
+	return &PlantRepository{db: r.db.WithContext(ctx), tenant: r.tenant}
+}
+
 // Create crea una nueva planta
 func (r *PlantRepository) Create(plant *models.PlantSpecies) error {
 	if err := r.db.Create(plant).Error; err != nil {
-		return fmt.Errorf("error creando planta: %w", err)
+		return speciesWriteError("error creando planta", err)
 	}
 	return nil
 }
 
+// speciesWriteError traduce los rechazos de un registro (external_ref repetido,
+// restricciones CHECK) a errores del dominio. Los demás, como una conexión
+// caída, se devuelven envueltos con message.
+func speciesWriteError(message string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == pgUniqueViolation && strings.Contains(pgErr.ConstraintName, "external_ref"):
+			return fmt.Errorf("%w: %v", ErrSpeciesExternalRefTaken, err)
+		case strings.HasPrefix(pgErr.Code, pgClassIntegrity), strings.HasPrefix(pgErr.Code, pgClassDataException):
+			return fmt.Errorf("%w: %v", ErrSpeciesRejected, err)
+		}
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
 // GetAll obtiene todas las plantas con filtros opcionales
 func (r *PlantRepository) GetAll(filters PlantFilters) ([]models.PlantSpecies, int64, error) {
 	var plants []models.PlantSpecies
 	var total int64
 
-	query := r.db.Model(&models.PlantSpecies{})
+	query := r.hideOverridden(r.db.Model(&models.PlantSpecies{}))
 
 	// Aplicar filtros
 	if filters.Search != "" {
@@ -50,14 +111,37 @@ func (r *PlantRepository) GetAll(filters PlantFilters) ([]models.PlantSpecies, i
 		query = query.Where("stratum = ?", filters.Stratum)
 	}
 
-	if filters.FunctionEcol != "" {
-		query = query.Where("function_ecol = ?", filters.FunctionEcol)
+	if len(filters.Functions) > 0 {
+		query = query.Where("id IN (?)", speciesWithFunctions(r.db, filters.Functions, filters.FunctionMatch))
 	}
 
 	if filters.SuccessionStage != "" {
 		query = query.Where("succession_stage = ?", filters.SuccessionStage)
 	}
 
+	// Filtros por rango de rasgos
+	query = applyRangeFilter(query, "mature_height_m", filters.MinHeightM, filters.MaxHeightM)
+	query = applyRangeFilter(query, "canopy_diameter_m", filters.MinCanopyM, filters.MaxCanopyM)
+	query = applyRangeFilter(query, "spacing_m", filters.MinSpacingM, filters.MaxSpacingM)
+	query = applyRangeFilter(query, "lifespan_years", filters.MinLifespanYears, filters.MaxLifespanYears)
+	query = applyRangeFilter(query, "time_to_harvest_months", filters.MinHarvestMonths, filters.MaxHarvestMonths)
+
+	if filters.LightRequirement != "" {
+		query = query.Where("light_requirement = ?", filters.LightRequirement)
+	}
+
+	if filters.RootDepth != "" {
+		query = query.Where("root_depth = ?", filters.RootDepth)
+	}
+
+	if filters.WaterNeeds != "" {
+		query = query.Where("water_needs = ?", filters.WaterNeeds)
+	}
+
+	if filters.FrostTolerance != "" {
+		query = query.Where("frost_tolerance = ?", filters.FrostTolerance)
+	}
+
 	// Contar total antes de paginación
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("error contando plantas: %w", err)
@@ -76,7 +160,7 @@ func (r *PlantRepository) GetAll(filters PlantFilters) ([]models.PlantSpecies, i
 	query = query.Order("created_at DESC")
 
 	// Ejecutar consulta
-	if err := query.Find(&plants).Error; err != nil {
+	if err := query.Preload("Functions", orderSpeciesFunctions).Find(&plants).Error; err != nil {
 		return nil, 0, fmt.Errorf("error obteniendo plantas: %w", err)
 	}
 
@@ -87,9 +171,9 @@ func (r *PlantRepository) GetAll(filters PlantFilters) ([]models.PlantSpecies, i
 func (r *PlantRepository) GetByID(id uint) (*models.PlantSpecies, error) {
 	var plant models.PlantSpecies
 
-	if err := r.db.First(&plant, id).Error; err != nil {
+	if err := r.db.Preload("Functions", orderSpeciesFunctions).First(&plant, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("planta no encontrada")
+			return nil, ErrSpeciesNotFound
 		}
 		return nil, fmt.Errorf("error obteniendo planta: %w", err)
 	}
@@ -97,26 +181,52 @@ func (r *PlantRepository) GetByID(id uint) (*models.PlantSpecies, error) {
 	return &plant, nil
 }
 
-// Update actualiza una planta
-func (r *PlantRepository) Update(id uint, updates map[string]interface{}) (*models.PlantSpecies, error) {
+// Update actualiza una planta. Si functions no es nil reemplaza todas sus
+// funciones ecológicas en la misma transacción.
+func (r *PlantRepository) Update(id uint, updates map[string]interface{}, functions []models.SpeciesFunction) (*models.PlantSpecies, error) {
 	var plant models.PlantSpecies
 
-	// Verificar que la planta existe
-	if err := r.db.First(&plant, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("planta no encontrada")
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Verificar que la planta existe
+		if err := tx.First(&plant, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSpeciesNotFound
+			}
+			return fmt.Errorf("error obteniendo planta: %w", err)
+		}
+		if err := r.checkWritable(&plant); err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("error obteniendo planta: %w", err)
-	}
 
-	// Actualizar campos
-	if err := r.db.Model(&plant).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("error actualizando planta: %w", err)
-	}
+		// Actualizar campos
+		if len(updates) > 0 {
+			if err := tx.Model(&plant).Updates(updates).Error; err != nil {
+				return speciesWriteError("error actualizando planta", err)
+			}
+		}
 
-	// Recargar la planta actualizada
-	if err := r.db.First(&plant, id).Error; err != nil {
-		return nil, fmt.Errorf("error recargando planta: %w", err)
+		if functions != nil {
+			if err := tx.Where("species_id = ?", id).Delete(&models.SpeciesFunction{}).Error; err != nil {
+				return fmt.Errorf("error reemplazando funciones ecológicas: %w", err)
+			}
+			for i := range functions {
+				functions[i].SpeciesID = id
+			}
+			if len(functions) > 0 {
+				if err := tx.Create(&functions).Error; err != nil {
+					return fmt.Errorf("error reemplazando funciones ecológicas: %w", err)
+				}
+			}
+		}
+
+		// Recargar la planta actualizada
+		if err := tx.Preload("Functions", orderSpeciesFunctions).First(&plant, id).Error; err != nil {
+			return fmt.Errorf("error recargando planta: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &plant, nil
@@ -128,10 +238,13 @@ func (r *PlantRepository) Delete(id uint) error {
 	var plant models.PlantSpecies
 	if err := r.db.First(&plant, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("planta no encontrada")
+			return ErrSpeciesNotFound
 		}
 		return fmt.Errorf("error obteniendo planta: %w", err)
 	}
+	if err := r.checkWritable(&plant); err != nil {
+		return err
+	}
 
 	// Verificar que no esté siendo usada en plantaciones
 	// var plantingCount int64
@@ -151,26 +264,238 @@ func (r *PlantRepository) Delete(id uint) error {
 	return nil
 }
 
-// ExistsByExternalRef verifica si existe una planta con el external_id dado
+// ExistsByExternalRef verifica si existe una planta con el external_id dado en el
+// catálogo propio del tenant (el global con All o sin organización)
 func (r *PlantRepository) ExistsByExternalRef(externalRef string) (bool, error) {
 	if externalRef == "" {
 		return false, nil
 	}
 
 	var count int64
-	if err := r.db.Model(&models.PlantSpecies{}).Where("external_ref = ?", externalRef).Count(&count).Error; err != nil {
+	query := r.ownCatalog(r.db.Model(&models.PlantSpecies{}))
+	if err := query.Where("external_ref = ?", externalRef).Count(&count).Error; err != nil {
 		return false, fmt.Errorf("error verificando external_ref: %w", err)
 	}
 
 	return count > 0, nil
 }
 
+// GetByExternalRef obtiene una planta por su referencia externa
+func (r *PlantRepository) GetByExternalRef(externalRef string) (*models.PlantSpecies, error) {
+	var plant models.PlantSpecies
+
+	err := r.ownCatalog(r.db.Preload("Functions", orderSpeciesFunctions)).
+		Where("external_ref = ?", externalRef).
+		First(&plant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSpeciesNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo planta: %w", err)
+	}
+
+	return &plant, nil
+}
+
+// GetByScientificName obtiene una planta del catálogo propio del tenant por su
+// nombre científico (sin distinguir mayúsculas)
+func (r *PlantRepository) GetByScientificName(scientificName string) (*models.PlantSpecies, error) {
+	var plant models.PlantSpecies
+
+	err := r.ownCatalog(r.db.Preload("Functions", orderSpeciesFunctions)).
+		Where("LOWER(scientific_name) = LOWER(?)", strings.TrimSpace(scientificName)).
+		Order("id").
+		First(&plant).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSpeciesNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo planta: %w", err)
+	}
+
+	return &plant, nil
+}
+
+// Override crea para la organización una copia privada de una especie global,
+// con sus funciones ecológicas. La copia reemplaza a la global en los listados
+// de la organización y se puede modificar libremente.
+func (r *PlantRepository) Override(id uint) (*models.PlantSpecies, error) {
+	if r.tenant.IsAll() || r.tenant.OrganizationID == 0 {
+		return nil, tenant.ErrTenantRequired
+	}
+
+	var override models.PlantSpecies
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var global models.PlantSpecies
+		if err := tx.Preload("Functions").First(&global, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSpeciesNotFound
+			}
+			return fmt.Errorf("error obteniendo planta: %w", err)
+		}
+		if global.OrganizationID != nil {
+			return ErrSpeciesNotGlobal
+		}
+
+		var count int64
+		if err := tx.Model(&models.PlantSpecies{}).Where("overrides_species_id = ?", id).Count(&count).Error; err != nil {
+			return fmt.Errorf("error verificando override: %w", err)
+		}
+		if count > 0 {
+			return ErrSpeciesOverrideExists
+		}
+
+		override = global
+		override.ID = 0
+		override.CreatedAt, override.UpdatedAt = time.Time{}, time.Time{}
+		override.OrganizationID = nil // Lo completa el tenant al crear
+		override.OverridesSpeciesID = &global.ID
+		override.PlantInstances = nil
+		override.Functions = make([]models.SpeciesFunction, len(global.Functions))
+		for i, f := range global.Functions {
+			override.Functions[i] = models.SpeciesFunction{Function: f.Function, IsPrimary: f.IsPrimary}
+		}
+
+		if err := tx.Create(&override).Error; err != nil {
+			return fmt.Errorf("error creando override: %w", err)
+		}
+		return tx.Preload("Functions", orderSpeciesFunctions).First(&override, override.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &override, nil
+}
+
+// checkWritable impide modificar especies globales sin acceso administrativo completo
+func (r *PlantRepository) checkWritable(plant *models.PlantSpecies) error {
+	if plant.OrganizationID == nil && !r.tenant.IsAll() {
+		return ErrSpeciesReadOnly
+	}
+	return nil
+}
+
+// hideOverridden oculta las especies globales que la organización sobrescribió
+func (r *PlantRepository) hideOverridden(query *gorm.DB) *gorm.DB {
+	if r.tenant.IsAll() || r.tenant.OrganizationID == 0 {
+		return query
+	}
+	return query.Where("plant_species.id NOT IN (?)", r.db.Model(&models.PlantSpecies{}).
+		Select("overrides_species_id").
+		Where("organization_id = ? AND overrides_species_id IS NOT NULL", r.tenant.OrganizationID))
+}
+
+// ownCatalog limita la consulta a las especies propias del tenant: las privadas
+// de la organización o, sin organización, las del catálogo global
+func (r *PlantRepository) ownCatalog(query *gorm.DB) *gorm.DB {
+	if r.tenant.IsAll() || r.tenant.OrganizationID == 0 {
+		return query.Where("plant_species.organization_id IS NULL")
+	}
+	return query.Where("plant_species.organization_id = ?", r.tenant.OrganizationID)
+}
+
+// FindCandidates obtiene las especies del catálogo que cubren al menos uno de los
+// estratos, etapas sucesionales o funciones indicados, sin incluir excludeIDs
+func (r *PlantRepository) FindCandidates(filters CandidateFilters) ([]models.PlantSpecies, error) {
+	var plants []models.PlantSpecies
+
+	if len(filters.Strata) == 0 && len(filters.SuccessionStages) == 0 && len(filters.Functions) == 0 {
+		return plants, nil
+	}
+
+	// Las condiciones se combinan con OR: basta con cubrir un hueco
+	match := r.db.Where("1 = 0")
+	if len(filters.Strata) > 0 {
+		match = match.Or("stratum IN ?", filters.Strata)
+	}
+	if len(filters.SuccessionStages) > 0 {
+		match = match.Or("succession_stage IN ?", filters.SuccessionStages)
+	}
+	if len(filters.Functions) > 0 {
+		match = match.Or("id IN (?)", speciesWithFunctions(r.db, filters.Functions, FunctionMatchAny))
+	}
+
+	query := r.hideOverridden(r.db.Model(&models.PlantSpecies{}).Where(match))
+	if len(filters.ExcludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", filters.ExcludeIDs)
+	}
+
+	if err := query.Preload("Functions", orderSpeciesFunctions).Order("common_name ASC").Find(&plants).Error; err != nil {
+		return nil, fmt.Errorf("error obteniendo especies candidatas: %w", err)
+	}
+
+	return plants, nil
+}
+
+// CandidateFilters estructura para buscar especies que cubran huecos de una parcela
+type CandidateFilters struct {
+	Strata           []string
+	SuccessionStages []string
+	Functions        []string
+	ExcludeIDs       []uint
+}
+
+// Semántica del filtro por varias funciones ecológicas
+const (
+	FunctionMatchAny = "any" // La especie cumple al menos una de las funciones
+	FunctionMatchAll = "all" // La especie cumple todas las funciones
+)
+
+// speciesWithFunctions devuelve la subconsulta de IDs de especies que cumplen
+// alguna (any) o todas (all) las funciones indicadas
+func speciesWithFunctions(db *gorm.DB, functions []string, match string) *gorm.DB {
+	sub := db.Model(&models.SpeciesFunction{}).
+		Select("species_id").
+		Where("function IN ?", functions)
+
+	if match == FunctionMatchAll {
+		sub = sub.Group("species_id").Having("COUNT(DISTINCT function) = ?", len(functions))
+	}
+	return sub
+}
+
+// orderSpeciesFunctions ordena las funciones precargadas con la principal primero
+func orderSpeciesFunctions(db *gorm.DB) *gorm.DB {
+	return db.Order("is_primary DESC, function ASC")
+}
+
+// applyRangeFilter agrega los límites inferior y superior de un rasgo numérico.
+// Las especies sin el rasgo cargado quedan fuera cuando se filtra por él.
+func applyRangeFilter(query *gorm.DB, column string, min, max *float64) *gorm.DB {
+	if min != nil {
+		query = query.Where(column+" >= ?", *min)
+	}
+	if max != nil {
+		query = query.Where(column+" <= ?", *max)
+	}
+	return query
+}
+
 // PlantFilters estructura para filtros de búsqueda
 type PlantFilters struct {
 	Search          string
 	Stratum         string
-	FunctionEcol    string
+	Functions       []string // function_ecol: una o varias funciones
+	FunctionMatch   string   // FunctionMatchAny (por defecto) o FunctionMatchAll
 	SuccessionStage string
 	Limit           int
 	Offset          int
+
+	// Rangos de rasgos (nil = sin límite)
+	MinHeightM       *float64
+	MaxHeightM       *float64
+	MinCanopyM       *float64
+	MaxCanopyM       *float64
+	MinSpacingM      *float64
+	MaxSpacingM      *float64
+	MinLifespanYears *float64
+	MaxLifespanYears *float64
+	MinHarvestMonths *float64
+	MaxHarvestMonths *float64
+
+	LightRequirement string
+	RootDepth        string
+	WaterNeeds       string
+	FrostTolerance   string
 }
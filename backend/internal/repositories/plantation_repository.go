@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrPlantationNotFound se devuelve cuando la plantación no existe o fue eliminada
+	ErrPlantationNotFound = apperror.NotFound("plantation_not_found", "plantación no encontrada")
+	// ErrPlantationAreaExceeded se devuelve cuando el área de la plantación supera
+	// el área del sitio que aún no está asignada a otras plantaciones
+	ErrPlantationAreaExceeded = apperror.Conflict("plantation_area_exceeded", "el área de la plantación excede el área disponible del sitio")
+)
+
+type PlantationRepository struct {
+	db *gorm.DB
+}
+
+func NewPlantationRepository() *PlantationRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &PlantationRepository{
+		db: db.DB,
+	}
+}
+
+// ForTenant devuelve el repositorio limitado a los datos del tenant t
+func (r *PlantationRepository) ForTenant(t tenant.Tenant) *PlantationRepository {
+	return &PlantationRepository{db: withTenant(r.db, t)}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría. Debe llamarse antes de ForTenant.
+func (r *PlantationRepository) WithContext(ctx context.Context) *PlantationRepository {
+	return &PlantationRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create crea una nueva plantación verificando que el sitio exista
+// y que tenga área suficiente sin asignar
+func (r *PlantationRepository) Create(plantation *models.Plantation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		site, err := lockSite(tx, plantation.SiteID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkSiteArea(tx, site, plantation.AreaM2, 0); err != nil {
+			return err
+		}
+
+		if err := tx.Create(plantation).Error; err != nil {
+			return fmt.Errorf("error creando plantación: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetBySite obtiene las plantaciones de un sitio con filtros opcionales
+func (r *PlantationRepository) GetBySite(siteID uint, filters PlantationFilters) ([]models.Plantation, int64, error) {
+	var plantations []models.Plantation
+	var total int64
+
+	if err := r.db.Select("id").First(&models.Site{}, siteID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrSiteNotFound
+		}
+		return nil, 0, fmt.Errorf("error obteniendo sitio: %w", err)
+	}
+
+	query := r.db.Model(&models.Plantation{}).Where("site_id = ?", siteID)
+
+	// Aplicar filtros
+	if filters.Search != "" {
+		searchTerm := "%" + filters.Search + "%"
+		query = query.Where("name ILIKE ? OR notes ILIKE ?", searchTerm, searchTerm)
+	}
+
+	// Contar total antes de paginación
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("error contando plantaciones: %w", err)
+	}
+
+	// Aplicar paginación
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	query = query.Order("created_at DESC")
+
+	if err := query.Find(&plantations).Error; err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo plantaciones: %w", err)
+	}
+
+	return plantations, total, nil
+}
+
+// GetByID obtiene una plantación por ID
+func (r *PlantationRepository) GetByID(id uint) (*models.Plantation, error) {
+	var plantation models.Plantation
+
+	if err := r.db.First(&plantation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlantationNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo plantación: %w", err)
+	}
+
+	return &plantation, nil
+}
+
+// GetWithLayout obtiene una plantación con sus parcelas, las instancias de cada
+// parcela y la especie de cada instancia, para los análisis que recorren el diseño
+func (r *PlantationRepository) GetWithLayout(id uint) (*models.Plantation, error) {
+	var plantation models.Plantation
+
+	err := r.db.
+		Preload("Plots", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Plots.PlantInstances", func(db *gorm.DB) *gorm.DB {
+			return db.Order(`"order" ASC, created_at ASC`)
+		}).
+		Preload("Plots.PlantInstances.Species").
+		Preload("Plots.PlantInstances.Species.Functions", orderSpeciesFunctions).
+		First(&plantation, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlantationNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo plantación: %w", err)
+	}
+
+	return &plantation, nil
+}
+
+// Update actualiza una plantación. Si cambia el área se vuelve a verificar
+// contra el área disponible del sitio.
+func (r *PlantationRepository) Update(id uint, updates map[string]interface{}) (*models.Plantation, error) {
+	var plantation models.Plantation
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&plantation, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPlantationNotFound
+			}
+			return fmt.Errorf("error obteniendo plantación: %w", err)
+		}
+
+		if area, ok := updates["area_m2"].(float64); ok {
+			site, err := lockSite(tx, plantation.SiteID)
+			if err != nil {
+				return err
+			}
+			if err := checkSiteArea(tx, site, area, plantation.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&plantation).Updates(updates).Error; err != nil {
+			return fmt.Errorf("error actualizando plantación: %w", err)
+		}
+
+		// Recargar la plantación actualizada
+		if err := tx.First(&plantation, id).Error; err != nil {
+			return fmt.Errorf("error recargando plantación: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &plantation, nil
+}
+
+// Delete elimina una plantación (soft delete) junto con sus parcelas,
+// instancias de plantas y plantillas de sugerencias
+func (r *PlantationRepository) Delete(id uint) error {
+	if _, err := r.GetByID(id); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return cascadeDeletePlantations(tx, []uint{id})
+	})
+}
+
+// lockSite obtiene el sitio bloqueando la fila hasta el fin de la transacción,
+// para que dos plantaciones concurrentes no reserven la misma área
+func lockSite(tx *gorm.DB, siteID uint) (*models.Site, error) {
+	var site models.Site
+
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&site, siteID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSiteNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo sitio: %w", err)
+	}
+
+	return &site, nil
+}
+
+// checkSiteArea verifica que area quepa en el área del sitio no asignada a otras
+// plantaciones. excludeID permite ignorar la plantación que se está actualizando.
+// Los sitios sin área conocida no imponen límite.
+func checkSiteArea(tx *gorm.DB, site *models.Site, area float64, excludeID uint) error {
+	siteArea := site.CalculateArea()
+	if siteArea <= 0 || area <= 0 {
+		return nil
+	}
+
+	var allocated float64
+	query := tx.Model(&models.Plantation{}).
+		Where("site_id = ?", site.ID).
+		Select("COALESCE(SUM(area_m2), 0)")
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Scan(&allocated).Error; err != nil {
+		return fmt.Errorf("error calculando área asignada del sitio: %w", err)
+	}
+
+	available := siteArea - allocated
+	if available < 0 {
+		available = 0
+	}
+	if area > available {
+		return fmt.Errorf("%w: disponible %.2f m², solicitado %.2f m²", ErrPlantationAreaExceeded, available, area)
+	}
+
+	return nil
+}
+
+// PlantationFilters estructura para filtros de búsqueda de plantaciones
+type PlantationFilters struct {
+	Search string
+	Limit  int
+	Offset int
+}
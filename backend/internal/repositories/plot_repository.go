@@ -0,0 +1,197 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrPlotNotFound se devuelve cuando la parcela no existe o fue eliminada
+var ErrPlotNotFound = apperror.NotFound("plot_not_found", "parcela no encontrada")
+
+type PlotRepository struct {
+	db *gorm.DB
+}
+
+func NewPlotRepository() *PlotRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &PlotRepository{
+		db: db.DB,
+	}
+}
+
+// ForTenant devuelve el repositorio limitado a los datos del tenant t
+func (r *PlotRepository) ForTenant(t tenant.Tenant) *PlotRepository {
+	return &PlotRepository{db: withTenant(r.db, t)}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría. Debe llamarse antes de ForTenant.
+func (r *PlotRepository) WithContext(ctx context.Context) *PlotRepository {
+	return &PlotRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create crea una nueva parcela verificando que la plantación exista
+func (r *PlotRepository) Create(plot *models.Plot) error {
+	if err := r.db.Select("id").First(&models.Plantation{}, plot.PlantationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPlantationNotFound
+		}
+		return fmt.Errorf("error obteniendo plantación: %w", err)
+	}
+
+	if err := r.db.Create(plot).Error; err != nil {
+		return fmt.Errorf("error creando parcela: %w", err)
+	}
+	return nil
+}
+
+// GetByPlantation obtiene las parcelas de una plantación con filtros opcionales
+func (r *PlotRepository) GetByPlantation(plantationID uint, filters PlotFilters) ([]models.Plot, int64, error) {
+	var plots []models.Plot
+	var total int64
+
+	if err := r.db.Select("id").First(&models.Plantation{}, plantationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrPlantationNotFound
+		}
+		return nil, 0, fmt.Errorf("error obteniendo plantación: %w", err)
+	}
+
+	query := r.db.Model(&models.Plot{}).Where("plantation_id = ?", plantationID)
+
+	if filters.PlotType != "" {
+		query = query.Where("plot_type = ?", filters.PlotType)
+	}
+
+	// Contar total antes de paginación
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("error contando parcelas: %w", err)
+	}
+
+	// Aplicar paginación
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	query = query.Order("created_at ASC")
+
+	if err := query.Find(&plots).Error; err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo parcelas: %w", err)
+	}
+
+	return plots, total, nil
+}
+
+// GetByID obtiene una parcela por ID
+func (r *PlotRepository) GetByID(id uint) (*models.Plot, error) {
+	var plot models.Plot
+
+	if err := r.db.First(&plot, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlotNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo parcela: %w", err)
+	}
+
+	return &plot, nil
+}
+
+// GetWithInstances obtiene una parcela con sus instancias de plantas y la especie de cada una
+func (r *PlotRepository) GetWithInstances(id uint) (*models.Plot, error) {
+	var plot models.Plot
+
+	err := r.db.
+		Preload("PlantInstances", func(db *gorm.DB) *gorm.DB {
+			return db.Order(`"order" ASC, created_at ASC`)
+		}).
+		Preload("PlantInstances.Species").
+		Preload("PlantInstances.Species.Functions", orderSpeciesFunctions).
+		First(&plot, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlotNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo parcela: %w", err)
+	}
+
+	return &plot, nil
+}
+
+// Update actualiza una parcela
+func (r *PlotRepository) Update(id uint, updates map[string]interface{}) (*models.Plot, error) {
+	plot, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Model(plot).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("error actualizando parcela: %w", err)
+	}
+
+	// Recargar la parcela actualizada
+	if err := r.db.First(plot, id).Error; err != nil {
+		return nil, fmt.Errorf("error recargando parcela: %w", err)
+	}
+
+	return plot, nil
+}
+
+// Delete elimina una parcela (soft delete) junto con sus instancias de plantas
+func (r *PlotRepository) Delete(id uint) error {
+	if _, err := r.GetByID(id); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return cascadeDeletePlots(tx, []uint{id})
+	})
+}
+
+// PlantCounts devuelve la cantidad total de plantas vivas por parcela
+func (r *PlotRepository) PlantCounts(plotIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(plotIDs))
+	if len(plotIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		PlotID uint
+		Total  int
+	}
+	err := r.db.Model(&models.PlantInstance{}).
+		Select("plot_id, COALESCE(SUM(quantity), 0) AS total").
+		Where("plot_id IN ? AND status <> ?", plotIDs, models.PlantStatusDead).
+		Group("plot_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("error contando plantas por parcela: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.PlotID] = row.Total
+	}
+	return counts, nil
+}
+
+// PlotFilters estructura para filtros de búsqueda de parcelas
+type PlotFilters struct {
+	PlotType string
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,260 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrSiteMemberNotFound se devuelve cuando el usuario no es miembro del sitio
+	ErrSiteMemberNotFound = apperror.NotFound("site_member_not_found", "el usuario no es miembro del sitio")
+	// ErrSiteMemberExists se devuelve al invitar a alguien que ya es miembro
+	ErrSiteMemberExists = apperror.Conflict("site_member_exists", "el usuario ya es miembro del sitio")
+	// ErrLastSiteOwner se devuelve al quitar o degradar al único owner del sitio
+	ErrLastSiteOwner = apperror.Conflict("last_site_owner", "el sitio debe tener al menos un owner")
+	// ErrSiteMemberOutsideOrganization se devuelve al invitar a alguien que no es de la organización del sitio
+	ErrSiteMemberOutsideOrganization = apperror.Conflict("user_outside_organization", "el usuario no pertenece a la organización del sitio")
+)
+
+// SiteScope indica a qué tipo de recurso pertenece un ID para resolver su sitio
+type SiteScope string
+
+const (
+	ScopeSite       SiteScope = "site"
+	ScopePlantation SiteScope = "plantation"
+	ScopePlot       SiteScope = "plot"
+	ScopeInstance   SiteScope = "instance"
+)
+
+type SiteMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewSiteMemberRepository() *SiteMemberRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &SiteMemberRepository{
+		db: db.DB,
+	}
+}
+
+// ForTenant devuelve el repositorio limitado a los datos del tenant t
+func (r *SiteMemberRepository) ForTenant(t tenant.Tenant) *SiteMemberRepository {
+	return &SiteMemberRepository{db: withTenant(r.db, t)}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría. Debe llamarse antes de ForTenant.
+func (r *SiteMemberRepository) WithContext(ctx context.Context) *SiteMemberRepository {
+	return &SiteMemberRepository{db: r.db.WithContext(ctx)}
+}
+
+// ResolveSiteID obtiene el sitio al que pertenece un recurso. Devuelve el error
+// "no encontrado" del recurso si no existe o alguno de sus padres fue eliminado.
+func (r *SiteMemberRepository) ResolveSiteID(scope SiteScope, id uint) (uint, error) {
+	var query *gorm.DB
+	var notFound error
+
+	switch scope {
+	case ScopeSite:
+		notFound = ErrSiteNotFound
+		query = r.db.Model(&models.Site{}).
+			Where("sites.id = ?", id)
+	case ScopePlantation:
+		notFound = ErrPlantationNotFound
+		query = r.db.Model(&models.Plantation{}).
+			Joins("JOIN sites ON sites.id = plantations.site_id AND sites.deleted_at IS NULL").
+			Where("plantations.id = ?", id)
+	case ScopePlot:
+		notFound = ErrPlotNotFound
+		query = r.db.Model(&models.Plot{}).
+			Joins("JOIN plantations ON plantations.id = plots.plantation_id AND plantations.deleted_at IS NULL").
+			Joins("JOIN sites ON sites.id = plantations.site_id AND sites.deleted_at IS NULL").
+			Where("plots.id = ?", id)
+	case ScopeInstance:
+		notFound = ErrPlantInstanceNotFound
+		query = r.db.Model(&models.PlantInstance{}).
+			Joins("JOIN plots ON plots.id = plant_instances.plot_id AND plots.deleted_at IS NULL").
+			Joins("JOIN plantations ON plantations.id = plots.plantation_id AND plantations.deleted_at IS NULL").
+			Joins("JOIN sites ON sites.id = plantations.site_id AND sites.deleted_at IS NULL").
+			Where("plant_instances.id = ?", id)
+	default:
+		return 0, fmt.Errorf("alcance de sitio desconocido: %s", scope)
+	}
+
+	var siteIDs []uint
+	if err := query.Limit(1).Pluck("sites.id", &siteIDs).Error; err != nil {
+		return 0, fmt.Errorf("error resolviendo sitio: %w", err)
+	}
+	if len(siteIDs) == 0 {
+		return 0, notFound
+	}
+	return siteIDs[0], nil
+}
+
+// GetRole obtiene el rol del usuario en el sitio
+func (r *SiteMemberRepository) GetRole(siteID, userID uint) (string, error) {
+	var member models.SiteMember
+
+	err := r.db.Select("role").
+		Where("site_id = ? AND user_id = ?", siteID, userID).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrSiteMemberNotFound
+		}
+		return "", fmt.Errorf("error obteniendo miembro del sitio: %w", err)
+	}
+
+	return member.Role, nil
+}
+
+// GetBySite obtiene los miembros de un sitio con sus usuarios
+func (r *SiteMemberRepository) GetBySite(siteID uint) ([]models.SiteMember, error) {
+	var members []models.SiteMember
+
+	err := r.db.Preload("User").
+		Where("site_id = ?", siteID).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo miembros del sitio: %w", err)
+	}
+
+	return members, nil
+}
+
+// Add agrega un miembro al sitio. El usuario debe pertenecer a la organización del sitio.
+func (r *SiteMemberRepository) Add(member *models.SiteMember) error {
+	var site models.Site
+	if err := r.db.Select("id", "organization_id").First(&site, member.SiteID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSiteNotFound
+		}
+		return fmt.Errorf("error obteniendo sitio: %w", err)
+	}
+
+	var inOrganization int64
+	err := r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", site.OrganizationID, member.UserID).
+		Count(&inOrganization).Error
+	if err != nil {
+		return fmt.Errorf("error verificando miembro de la organización: %w", err)
+	}
+	if inOrganization == 0 {
+		return ErrSiteMemberOutsideOrganization
+	}
+
+	var count int64
+	err = r.db.Model(&models.SiteMember{}).
+		Where("site_id = ? AND user_id = ?", member.SiteID, member.UserID).
+		Count(&count).Error
+	if err != nil {
+		return fmt.Errorf("error verificando miembro del sitio: %w", err)
+	}
+	if count > 0 {
+		return ErrSiteMemberExists
+	}
+
+	if err := r.db.Create(member).Error; err != nil {
+		return fmt.Errorf("error agregando miembro del sitio: %w", err)
+	}
+
+	return r.db.Preload("User").First(member, member.ID).Error
+}
+
+// UpdateRole cambia el rol de un miembro. No permite dejar al sitio sin owner.
+func (r *SiteMemberRepository) UpdateRole(siteID, userID uint, role string) (*models.SiteMember, error) {
+	var member models.SiteMember
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockMember(tx, siteID, userID, &member); err != nil {
+			return err
+		}
+
+		if member.Role == models.SiteRoleOwner && role != models.SiteRoleOwner {
+			if err := ensureAnotherOwner(tx, siteID, userID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&member).Update("role", role).Error; err != nil {
+			return fmt.Errorf("error actualizando miembro del sitio: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Preload("User").First(&member, member.ID).Error; err != nil {
+		return nil, fmt.Errorf("error recargando miembro del sitio: %w", err)
+	}
+	return &member, nil
+}
+
+// Remove quita a un miembro del sitio. No permite quitar al último owner.
+func (r *SiteMemberRepository) Remove(siteID, userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var member models.SiteMember
+		if err := lockMember(tx, siteID, userID, &member); err != nil {
+			return err
+		}
+
+		if member.Role == models.SiteRoleOwner {
+			if err := ensureAnotherOwner(tx, siteID, userID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Delete(&member).Error; err != nil {
+			return fmt.Errorf("error quitando miembro del sitio: %w", err)
+		}
+		return nil
+	})
+}
+
+// lockMember obtiene el miembro bloqueando los owners del sitio, para que dos
+// cambios simultáneos no dejen al sitio sin owner
+func lockMember(tx *gorm.DB, siteID, userID uint, member *models.SiteMember) error {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("site_id = ? AND (user_id = ? OR role = ?)", siteID, userID, models.SiteRoleOwner).
+		Find(&[]models.SiteMember{}).Error
+	if err != nil {
+		return fmt.Errorf("error obteniendo miembro del sitio: %w", err)
+	}
+
+	if err := tx.Where("site_id = ? AND user_id = ?", siteID, userID).First(member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSiteMemberNotFound
+		}
+		return fmt.Errorf("error obteniendo miembro del sitio: %w", err)
+	}
+	return nil
+}
+
+func ensureAnotherOwner(tx *gorm.DB, siteID, userID uint) error {
+	var owners int64
+	err := tx.Model(&models.SiteMember{}).
+		Where("site_id = ? AND role = ? AND user_id <> ?", siteID, models.SiteRoleOwner, userID).
+		Count(&owners).Error
+	if err != nil {
+		return fmt.Errorf("error contando owners del sitio: %w", err)
+	}
+	if owners == 0 {
+		return ErrLastSiteOwner
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrSiteNotFound se devuelve cuando el sitio no existe o fue eliminado
+var ErrSiteNotFound = apperror.NotFound("site_not_found", "sitio no encontrado")
+
+type SiteRepository struct {
+	db *gorm.DB
+}
+
+func NewSiteRepository() *SiteRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &SiteRepository{
+		db: db.DB,
+	}
+}
+
+// ForTenant devuelve el repositorio limitado a los datos del tenant t
+func (r *SiteRepository) ForTenant(t tenant.Tenant) *SiteRepository {
+	return &SiteRepository{db: withTenant(r.db, t)}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría. Debe llamarse antes de ForTenant.
+func (r *SiteRepository) WithContext(ctx context.Context) *SiteRepository {
+	return &SiteRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create crea un nuevo sitio y registra a ownerID como su owner.
+// Con ownerID 0 el sitio se crea sin miembros (solo visible para administradores).
+func (r *SiteRepository) Create(site *models.Site, ownerID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(site).Error; err != nil {
+			return fmt.Errorf("error creando sitio: %w", err)
+		}
+
+		if ownerID == 0 {
+			return nil
+		}
+		owner := models.SiteMember{SiteID: site.ID, UserID: ownerID, Role: models.SiteRoleOwner}
+		if err := tx.Create(&owner).Error; err != nil {
+			return fmt.Errorf("error registrando owner del sitio: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetAll obtiene todos los sitios con filtros opcionales
+func (r *SiteRepository) GetAll(filters SiteFilters) ([]models.Site, int64, error) {
+	var sites []models.Site
+	var total int64
+
+	query := r.db.Model(&models.Site{})
+
+	// Solo los sitios de los que el usuario es miembro
+	if filters.MemberID > 0 {
+		query = query.Where("id IN (?)", r.db.Model(&models.SiteMember{}).
+			Select("site_id").
+			Where("user_id = ?", filters.MemberID))
+	}
+
+	// Aplicar filtros
+	if filters.Search != "" {
+		searchTerm := "%" + filters.Search + "%"
+		query = query.Where("name ILIKE ? OR notes ILIKE ?", searchTerm, searchTerm)
+	}
+
+	if filters.Climate != "" {
+		query = query.Where("climate = ?", filters.Climate)
+	}
+
+	if filters.MinAreaM2 > 0 {
+		query = query.Where("area_m2 >= ?", filters.MinAreaM2)
+	}
+
+	if filters.MaxAreaM2 > 0 {
+		query = query.Where("area_m2 <= ?", filters.MaxAreaM2)
+	}
+
+	// Contar total antes de paginación
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("error contando sitios: %w", err)
+	}
+
+	// Aplicar paginación
+	if filters.Limit > 0 {
+		query = query.Limit(filters.Limit)
+	}
+
+	if filters.Offset > 0 {
+		query = query.Offset(filters.Offset)
+	}
+
+	// Ordenar por fecha de creación (más recientes primero)
+	query = query.Order("created_at DESC")
+
+	if err := query.Find(&sites).Error; err != nil {
+		return nil, 0, fmt.Errorf("error obteniendo sitios: %w", err)
+	}
+
+	return sites, total, nil
+}
+
+// GetByID obtiene un sitio por ID
+func (r *SiteRepository) GetByID(id uint) (*models.Site, error) {
+	var site models.Site
+
+	if err := r.db.First(&site, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSiteNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo sitio: %w", err)
+	}
+
+	return &site, nil
+}
+
+// Update actualiza un sitio
+func (r *SiteRepository) Update(id uint, updates map[string]interface{}) (*models.Site, error) {
+	site, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Actualizar campos
+	if err := r.db.Model(site).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("error actualizando sitio: %w", err)
+	}
+
+	// Recargar el sitio actualizado
+	if err := r.db.First(site, id).Error; err != nil {
+		return nil, fmt.Errorf("error recargando sitio: %w", err)
+	}
+
+	return site, nil
+}
+
+// Delete elimina un sitio (soft delete) junto con todas sus plantaciones,
+// parcelas, instancias de plantas y plantillas de sugerencias
+func (r *SiteRepository) Delete(id uint) error {
+	if _, err := r.GetByID(id); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return cascadeDeleteSites(tx, []uint{id})
+	})
+}
+
+// SiteFilters estructura para filtros de búsqueda de sitios
+type SiteFilters struct {
+	MemberID  uint // 0 = todos los sitios (administradores)
+	Search    string
+	Climate   string
+	MinAreaM2 float64
+	MaxAreaM2 float64
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/internal/tenant"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+)
+
+// ErrSuggestionTemplateNotFound se devuelve cuando la plantilla no existe,
+// fue eliminada o pertenece a otra plantación
+var ErrSuggestionTemplateNotFound = apperror.NotFound("suggestion_template_not_found", "plantilla no encontrada")
+
+type SuggestionTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewSuggestionTemplateRepository() *SuggestionTemplateRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &SuggestionTemplateRepository{
+		db: db.DB,
+	}
+}
+
+// ForTenant devuelve el repositorio limitado a los datos del tenant t
+func (r *SuggestionTemplateRepository) ForTenant(t tenant.Tenant) *SuggestionTemplateRepository {
+	return &SuggestionTemplateRepository{db: withTenant(r.db, t)}
+}
+
+// WithContext devuelve el repository con el contexto de la solicitud, que lleva
+// el autor de los cambios para la auditoría. Debe llamarse antes de ForTenant.
+func (r *SuggestionTemplateRepository) WithContext(ctx context.Context) *SuggestionTemplateRepository {
+	return &SuggestionTemplateRepository{db: r.db.WithContext(ctx)}
+}
+
+// Create crea una nueva plantilla verificando que la plantación exista
+func (r *SuggestionTemplateRepository) Create(template *models.SuggestionTemplate) error {
+	if err := r.db.Select("id").First(&models.Plantation{}, template.PlantationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPlantationNotFound
+		}
+		return fmt.Errorf("error obteniendo plantación: %w", err)
+	}
+
+	if err := r.db.Create(template).Error; err != nil {
+		return fmt.Errorf("error creando plantilla: %w", err)
+	}
+	return nil
+}
+
+// GetByPlantation obtiene las plantillas de una plantación
+func (r *SuggestionTemplateRepository) GetByPlantation(plantationID uint) ([]models.SuggestionTemplate, error) {
+	var templates []models.SuggestionTemplate
+
+	if err := r.db.Select("id").First(&models.Plantation{}, plantationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlantationNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo plantación: %w", err)
+	}
+
+	err := r.db.Where("plantation_id = ?", plantationID).
+		Order("created_at ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo plantillas: %w", err)
+	}
+
+	return templates, nil
+}
+
+// GetByID obtiene una plantilla de una plantación concreta
+func (r *SuggestionTemplateRepository) GetByID(plantationID, id uint) (*models.SuggestionTemplate, error) {
+	var template models.SuggestionTemplate
+
+	err := r.db.Where("plantation_id = ?", plantationID).First(&template, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSuggestionTemplateNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo plantilla: %w", err)
+	}
+
+	return &template, nil
+}
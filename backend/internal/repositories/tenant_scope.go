@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"github.com/deibys/sintronia/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// Los repositorios de sitios y todo lo que cuelga de ellos (y el de especies)
+// solo consultan a través de ForTenant: los callbacks del paquete tenant filtran
+// cada consulta por la organización del contexto y rechazan las que no la traen,
+// así que un handler no puede leer ni modificar filas de otra organización
+// aunque olvide filtrar.
+
+// withTenant devuelve la conexión con el tenant en su contexto
+func withTenant(conn *gorm.DB, t tenant.Tenant) *gorm.DB {
+	return conn.WithContext(tenant.WithTenant(conn.Statement.Context, t))
+}
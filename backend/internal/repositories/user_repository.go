@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deibys/sintronia/internal/db"
+	"github.com/deibys/sintronia/pkg/apperror"
+	"github.com/deibys/sintronia/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrUserNotFound se devuelve cuando el usuario no existe o fue eliminado
+	ErrUserNotFound = apperror.NotFound("user_not_found", "usuario no encontrado")
+	// ErrUserEmailTaken se devuelve cuando ya hay una cuenta con el mismo email
+	ErrUserEmailTaken = apperror.Conflict("user_email_taken", "ya existe un usuario con ese email")
+	// ErrRefreshTokenNotFound se devuelve cuando el token de renovación no existe
+	ErrRefreshTokenNotFound = apperror.NotFound("refresh_token_not_found", "token de renovación no encontrado")
+	// ErrRefreshTokenInactive se devuelve al rotar un token ya revocado o vencido
+	ErrRefreshTokenInactive = apperror.Unauthorized("refresh_token_inactive", "token de renovación revocado o vencido")
+)
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository() *UserRepository {
+
+	// Verificar que la conexión DB esté inicializada
+	if db.DB == nil {
+		panic("Base de datos no inicializada. Asegúrate de llamar db.InitDatabase() antes de crear repositorios")
+	}
+
+	return &UserRepository{
+		db: db.DB,
+	}
+}
+
+// Create crea un usuario. El email se guarda en minúsculas.
+func (r *UserRepository) Create(user *models.User) error {
+	return createUser(r.db, user)
+}
+
+// CreateWithOrganization crea un usuario junto con una organización de la que
+// queda como owner, en una sola transacción
+func (r *UserRepository) CreateWithOrganization(user *models.User, org *models.Organization) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createUser(tx, user); err != nil {
+			return err
+		}
+		return createOrganization(tx, org, user.ID)
+	})
+}
+
+func createUser(tx *gorm.DB, user *models.User) error {
+	user.Email = normalizeEmail(user.Email)
+
+	var count int64
+	if err := tx.Unscoped().Model(&models.User{}).Where("email = ?", user.Email).Count(&count).Error; err != nil {
+		return fmt.Errorf("error verificando email: %w", err)
+	}
+	if count > 0 {
+		return ErrUserEmailTaken
+	}
+
+	if err := tx.Create(user).Error; err != nil {
+		return fmt.Errorf("error creando usuario: %w", err)
+	}
+	return nil
+}
+
+// GetByID obtiene un usuario por ID
+func (r *UserRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+
+	if err := r.db.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo usuario: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetByEmail obtiene un usuario por email (sin distinguir mayúsculas)
+func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+
+	if err := r.db.Where("email = ?", normalizeEmail(email)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo usuario: %w", err)
+	}
+
+	return &user, nil
+}
+
+// TouchLastLogin registra el momento del último inicio de sesión
+func (r *UserRepository) TouchLastLogin(id uint, at time.Time) error {
+	if err := r.db.Model(&models.User{}).Where("id = ?", id).Update("last_login_at", at).Error; err != nil {
+		return fmt.Errorf("error actualizando usuario: %w", err)
+	}
+	return nil
+}
+
+// UpdatePassword reemplaza el hash de la contraseña del usuario
+func (r *UserRepository) UpdatePassword(id uint, passwordHash string) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", id).Update("password_hash", passwordHash)
+	if result.Error != nil {
+		return fmt.Errorf("error actualizando contraseña: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// CreateRefreshToken guarda un token de renovación nuevo
+func (r *UserRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		return fmt.Errorf("error guardando token de renovación: %w", err)
+	}
+	return nil
+}
+
+// GetRefreshToken obtiene un token de renovación por su hash
+func (r *UserRepository) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, fmt.Errorf("error obteniendo token de renovación: %w", err)
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken revoca el token usado y guarda su reemplazo en una sola
+// transacción. Bloquea la fila para que dos renovaciones simultáneas con el mismo
+// token no obtengan ambas un reemplazo: la segunda ve el token ya revocado.
+func (r *UserRepository) RotateRefreshToken(oldID uint, replacement *models.RefreshToken, now time.Time) error {
+	var old models.RefreshToken
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&old, oldID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshTokenNotFound
+			}
+			return fmt.Errorf("error obteniendo token de renovación: %w", err)
+		}
+		if !old.IsActive(now) {
+			return ErrRefreshTokenInactive
+		}
+
+		if err := tx.Create(replacement).Error; err != nil {
+			return fmt.Errorf("error guardando token de renovación: %w", err)
+		}
+
+		err := tx.Model(&old).Updates(map[string]interface{}{
+			"revoked_at":     now,
+			"replaced_by_id": replacement.ID,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("error revocando token de renovación: %w", err)
+		}
+		return nil
+	})
+}
+
+// RevokeRefreshToken revoca un token de renovación del usuario
+func (r *UserRepository) RevokeRefreshToken(userID uint, tokenHash string, now time.Time) error {
+	err := r.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND token_hash = ? AND revoked_at IS NULL", userID, tokenHash).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return fmt.Errorf("error revocando token de renovación: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllRefreshTokens revoca todas las sesiones activas del usuario
+func (r *UserRepository) RevokeAllRefreshTokens(userID uint, now time.Time) error {
+	err := r.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return fmt.Errorf("error revocando tokens de renovación: %w", err)
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -2,17 +2,14 @@ package routes
 
 import (
 	"fmt"
-	"io"
-	"log"
 	"net/http"
-	"os"
 	"regexp"
-	"strings"
-	"time"
 
+	"github.com/deibys/sintronia/internal/config"
 	"github.com/deibys/sintronia/internal/handlers"
 	"github.com/deibys/sintronia/internal/middleware"
-	"github.com/gin-contrib/cors"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/pkg/models"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -38,50 +35,27 @@ type Plant struct {
 // Lista que simula la persistencia en memoria.
 var PlantasRegistradas []Plant
 
-// NewRouter configura el router con Gin y define los endpoints.
 // NewRouter configura el router con todas las rutas y middlewares globales.
-func NewRouter() *gin.Engine {
-	// Obtiene el valor de la variable de entorno GIN_MODE.
-	// Si no está definida, por defecto será "debug".
-	mode := os.Getenv("GIN_MODE")
-	if mode == gin.ReleaseMode {
-		gin.SetMode(gin.ReleaseMode)
-	}
+func NewRouter(cfg *config.Config) *gin.Engine {
+	gin.SetMode(cfg.Server.Mode)
+	handlers.Configure(cfg)
+
 	// Con gin.New() evitamos el logger y recovery por defecto para usar los nuestros
 	router := gin.New()
 
 	// Middlewares globales
+	router.Use(middleware.RequestIDMiddleware())
 	router.Use(middleware.CustomLogger())
 	router.Use(middleware.ErrorHandler())
-	// Use the proper CORS middleware instead of hardcoded configuration
-	//router.Use(middleware.CORSMiddleware())
-	// Configuración básica de CORS:
-	router.Use(cors.New(cors.Config{
-		AllowAllOrigins: true,
-		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{
-			"Origin", "Content-Type", "Accept", "Authorization",
-			"x-permapeople-key-id", "Cache-Control", "ngrok-skip-browser-warning", // <- agregamos este
-		},
-		AllowCredentials: false, // ⚠️ debe estar en false si AllowAllOrigins es true
-		MaxAge:           12 * time.Hour,
-
-		// // Permite todos los orígenes; en producción es mejor especificar tus orígenes permitidos.
-		// AllowOrigins: []string{"*"},
-		// //AllowOrigins: []string{"https://stackblitz.com", "http://localhost:5173"},
-		// // Permite métodos comunes:
-		// AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		// // Permite encabezados comunes:
-		// AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "x-permapeople-key-id"},
-		// // Opcional: permite el envío de credenciales.
-		// AllowCredentials: true,
-		// // Establece un tiempo de vida para la configuración de CORS
-		// MaxAge: 12 * time.Hour,
-	}))
+	router.Use(middleware.CORSMiddleware(cfg.CORS))
 	// Aplicar el AuthMiddleware globalmente si se considera necesario,
 	// o solo en rutas específicas
 	// router.Use(middleware.AuthMiddleware())
 
+	// Sondas de Docker/Kubernetes, fuera de /api/v1 y sin autenticación
+	router.GET("/livez", handlers.LivezHandler)
+	router.GET("/readyz", handlers.ReadyzHandler)
+
 	RegisterRoutes(router)
 
 	router.GET("/error", func(c *gin.Context) {
@@ -90,46 +64,9 @@ func NewRouter() *gin.Engine {
 		// Luego, no se llama a c.Next() o se deja caer sin responder
 	})
 
-	router.GET("/api/plants", middleware.AuthMiddleware(), func(c *gin.Context) {
-		log.Println("**************************************   ")
-		// Llamada a la API de Permapeople
-		req, err := http.NewRequest("GET", "https://permapeople.org/api/plants", nil)
-		if err != nil {
-			c.Error(fmt.Errorf("error creando request"))
-
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error al crear la solicitud"})
-			return
-		}
-
-		authHeader := c.GetHeader("Authorization") // Bearer token
-		keyID := c.GetHeader("x-permapeople-key-id")
-		authHeader = strings.TrimPrefix(authHeader, "Bearer ")
-		// Si necesitás pasar una API key (ejemplo)
-		req.Header.Set("x-permapeople-key-id", keyID)
-		req.Header.Set("x-permapeople-key-secret", authHeader)
-
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			c.Error(fmt.Errorf("error haciendo request"))
-			c.Error(err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error al llamar a la API externa"})
-			return
-		}
-		defer resp.Body.Close()
-
-		// Leer respuesta y reenviarla
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			c.Error(fmt.Errorf("error leyendo respuesta"))
-			c.Error(err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "error al leer la respuesta"})
-			return
-		}
-
-		c.Data(resp.StatusCode, "application/json", body)
-
-	})
+	// Proxy del catálogo de Permapeople con las credenciales guardadas en el servidor
+	router.GET("/api/plants", middleware.AuthMiddleware(),
+		middleware.RequireScope(models.APIScope(models.APIActionRead, models.APIResourceCatalog)), handlers.ProxyPermapeoplePlantsHandler)
 
 	// Endpoint POST /plant
 	router.POST("/plants2", middleware.AuthMiddleware(), func(c *gin.Context) {
@@ -157,24 +94,149 @@ func RegisterRoutes(router *gin.Engine) {
 
 	}
 
+	autenticacion := api.Group("/auth")
+	{
+		autenticacion.POST("/register", handlers.RegisterHandler)
+		autenticacion.POST("/login", handlers.LoginHandler)
+		autenticacion.POST("/refresh", handlers.RefreshHandler)
+
+		autenticacionAuth := autenticacion.Group("")
+		autenticacionAuth.Use(middleware.AuthMiddleware())
+		{
+			autenticacionAuth.POST("/logout", handlers.LogoutHandler)
+			autenticacionAuth.GET("/me", handlers.MeHandler)
+
+			// API keys personales: solo se gestionan con una sesión iniciada
+			apiKeys := autenticacionAuth.Group("/api-keys")
+			apiKeys.Use(middleware.RequireSession())
+			{
+				apiKeys.GET("", handlers.GetAPIKeysHandler)
+				apiKeys.POST("", handlers.CreateAPIKeyHandler)
+				apiKeys.DELETE("/:id", handlers.RevokeAPIKeyHandler)
+			}
+		}
+	}
+
+	// Organizaciones del usuario y sus miembros
+	organizaciones := api.Group("/organizations")
+	organizaciones.Use(middleware.AuthMiddleware(), middleware.RequireResourceScope(models.APIResourceOrganizations))
+	{
+		organizaciones.GET("", handlers.GetOrganizationsHandler)
+		organizaciones.POST("", handlers.CreateOrganizationHandler)
+		organizaciones.GET("/:id/members", handlers.GetOrganizationMembersHandler)
+		organizaciones.POST("/:id/members", handlers.AddOrganizationMemberHandler)
+		organizaciones.DELETE("/:id/members/:user_id", handlers.RemoveOrganizationMemberHandler)
+	}
+
 	// Grupo para la API protegida (por ejemplo, para plantas)
 	plantas := api.Group("/plantas")
 	{
-		// Rutas públicas (sin autenticación)
-
-		plantas.GET("", handlers.GetPlantsSpeciesHandler)
-		plantas.GET("/:id", handlers.GetPlantSpeciesHandler)
+		// Rutas públicas (sin autenticación): el catálogo global y, con un usuario
+		// autenticado, las especies privadas de su organización
+		plantasPublic := plantas.Group("")
+		plantasPublic.Use(middleware.OptionalAuthMiddleware(), middleware.RequireResourceScope(models.APIResourceCatalog), middleware.OptionalTenantMiddleware())
+		{
+			plantasPublic.GET("", handlers.GetPlantsSpeciesHandler)
+			plantasPublic.GET("/:id", handlers.GetPlantSpeciesHandler)
+		}
 
 		// Rutas protegidas (con autenticación)
 		plantasAuth := plantas.Group("")
-		plantasAuth.Use(middleware.AuthMiddleware())
+		plantasAuth.Use(middleware.AuthMiddleware(), middleware.RequireResourceScope(models.APIResourceCatalog), middleware.TenantMiddleware())
 		{
 			plantasAuth.POST("", handlers.CreatePlantSpeciesHandler)
 			plantasAuth.PUT("/:id", handlers.UpdatePlantSpeciesHandler)
 			plantasAuth.DELETE("/:id", handlers.DeletePlantSpeciesHandler)
+			plantasAuth.POST("/:id/override", handlers.OverridePlantSpeciesHandler)
 		}
 	}
 
+	// Todo lo que cuelga de un sitio requiere autenticación, una organización
+	// (X-Organization-ID) y un permiso sobre ese sitio. Con una API key, además,
+	// el scope del grupo (read:<recurso> en GET, write:<recurso> en el resto). Cada ruta declara el
+	// permiso según el tipo de recurso de :id.
+	site := func(permission string) gin.HandlerFunc {
+		return middleware.RequireSitePermission(repositories.ScopeSite, "id", permission)
+	}
+	plantation := func(permission string) gin.HandlerFunc {
+		return middleware.RequireSitePermission(repositories.ScopePlantation, "id", permission)
+	}
+	plot := func(permission string) gin.HandlerFunc {
+		return middleware.RequireSitePermission(repositories.ScopePlot, "id", permission)
+	}
+	instance := func(permission string) gin.HandlerFunc {
+		return middleware.RequireSitePermission(repositories.ScopeInstance, "id", permission)
+	}
+
+	sitios := api.Group("/sites")
+	sitios.Use(middleware.AuthMiddleware(), middleware.RequireResourceScope(models.APIResourceSites), middleware.TenantMiddleware())
+	{
+		// Lista solo los sitios de los que el usuario es miembro
+		sitios.GET("", handlers.GetSitesHandler)
+		// Quien crea el sitio queda como owner
+		sitios.POST("", handlers.CreateSiteHandler)
+
+		sitios.GET("/:id", site(models.SitePermView), handlers.GetSiteHandler)
+		sitios.PUT("/:id", site(models.SitePermManage), handlers.UpdateSiteHandler)
+		sitios.DELETE("/:id", site(models.SitePermManage), handlers.DeleteSiteHandler)
+		sitios.GET("/:id/plantations", site(models.SitePermView), handlers.GetSitePlantationsHandler)
+		sitios.POST("/:id/plantations", site(models.SitePermDesign), handlers.CreatePlantationHandler)
+
+		// Miembros del sitio
+		sitios.GET("/:id/members", site(models.SitePermView), handlers.GetSiteMembersHandler)
+		sitios.POST("/:id/members", site(models.SitePermMembers), handlers.InviteSiteMemberHandler)
+		sitios.PUT("/:id/members/:user_id", site(models.SitePermMembers), handlers.UpdateSiteMemberHandler)
+		sitios.DELETE("/:id/members/:user_id", site(models.SitePermMembers), handlers.RemoveSiteMemberHandler)
+	}
+
+	plantaciones := api.Group("/plantations")
+	plantaciones.Use(middleware.AuthMiddleware(), middleware.RequireResourceScope(models.APIResourcePlantations), middleware.TenantMiddleware())
+	{
+		plantaciones.GET("/:id", plantation(models.SitePermView), handlers.GetPlantationHandler)
+		plantaciones.PUT("/:id", plantation(models.SitePermDesign), handlers.UpdatePlantationHandler)
+		plantaciones.DELETE("/:id", plantation(models.SitePermDesign), handlers.DeletePlantationHandler)
+		plantaciones.GET("/:id/plots", plantation(models.SitePermView), handlers.GetPlantationPlotsHandler)
+		plantaciones.POST("/:id/plots", plantation(models.SitePermDesign), handlers.CreatePlotHandler)
+		plantaciones.GET("/:id/occupancy", plantation(models.SitePermView), handlers.GetPlantationOccupancyHandler)
+		plantaciones.GET("/:id/templates", plantation(models.SitePermView), handlers.GetPlantationTemplatesHandler)
+		plantaciones.POST("/:id/templates", plantation(models.SitePermDesign), handlers.CreatePlantationTemplateHandler)
+		// La evaluación no modifica datos, solo calcula el informe de cumplimiento
+		plantaciones.POST("/:id/templates/:tid/evaluate", plantation(models.SitePermView), handlers.EvaluatePlantationTemplateHandler)
+	}
+
+	parcelas := api.Group("/plots")
+	parcelas.Use(middleware.AuthMiddleware(), middleware.RequireResourceScope(models.APIResourcePlots), middleware.TenantMiddleware())
+	{
+		parcelas.GET("/:id", plot(models.SitePermView), handlers.GetPlotHandler)
+		parcelas.PUT("/:id", plot(models.SitePermDesign), handlers.UpdatePlotHandler)
+		parcelas.DELETE("/:id", plot(models.SitePermDesign), handlers.DeletePlotHandler)
+		parcelas.GET("/:id/instances", plot(models.SitePermView), handlers.GetPlotInstancesHandler)
+		parcelas.POST("/:id/instances", plot(models.SitePermDesign), handlers.CreatePlantInstanceHandler)
+		parcelas.GET("/:id/recommendations", plot(models.SitePermView), handlers.GetPlotRecommendationsHandler)
+		parcelas.GET("/:id/occupancy", plot(models.SitePermView), handlers.GetPlotOccupancyHandler)
+	}
+
+	instancias := api.Group("/instances")
+	instancias.Use(middleware.AuthMiddleware(), middleware.RequireResourceScope(models.APIResourceInstances), middleware.TenantMiddleware())
+	{
+		instancias.GET("/:id", instance(models.SitePermView), handlers.GetPlantInstanceHandler)
+		instancias.GET("/:id/timeline", instance(models.SitePermView), handlers.GetPlantInstanceTimelineHandler)
+		instancias.PUT("/:id", instance(models.SitePermField), handlers.UpdatePlantInstanceHandler)
+		instancias.DELETE("/:id", instance(models.SitePermDesign), handlers.DeletePlantInstanceHandler)
+		instancias.POST("/:id/transitions", instance(models.SitePermField), handlers.TransitionPlantInstanceHandler)
+	}
+
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.RequireResourceScope(models.APIResourceAdmin), middleware.AdminMiddleware())
+	{
+		admin.POST("/permapeople/import", handlers.ImportPermapeopleHandler)
+		admin.GET("/integrations/:provider/credentials", handlers.GetIntegrationCredentialsHandler)
+		admin.PUT("/integrations/:provider/credentials", handlers.RotateIntegrationCredentialsHandler)
+		admin.GET("/diagnostics/http", handlers.GetHTTPClientDiagnosticsHandler)
+		admin.GET("/diagnostics/readiness", handlers.GetReadinessDiagnosticsHandler)
+		admin.GET("/audit", handlers.GetAuditEventsHandler)
+	}
+
 	// ubicaciones := api.Group("/locations")
 	// {
 	// 	// Rutas de ubicaciones
@@ -205,13 +267,8 @@ func RegisterRoutes(router *gin.Engine) {
 	// Rutas de utilidad
 	api.GET("/constants", handlers.GetConstantsHandler)
 
-	// Ruta de salud
-	api.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "ok",
-			"service": "sintropia-api",
-		})
-	})
+	// Ruta de salud (compatibilidad; los orquestadores deben usar /livez y /readyz)
+	api.GET("/health", handlers.HealthHandler)
 
 	// Grupo de rutas para usuarios (si llegas a implementarlo)
 	// userGroup := router.Group("/usuarios")
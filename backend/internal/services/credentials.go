@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deibys/sintronia/internal/integrations/permapeople"
+	"github.com/deibys/sintronia/internal/repositories"
+	"github.com/deibys/sintronia/internal/vault"
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// Origen de las credenciales de una integración. "env" son las credenciales
+// de respaldo de la configuración (archivo o variables de entorno).
+const (
+	CredentialSourceVault = "vault"
+	CredentialSourceEnv   = "env"
+)
+
+// Credentials son las credenciales de respaldo de una integración
+type Credentials struct {
+	KeyID     string
+	KeySecret string
+}
+
+// CredentialStore es lo que el servicio necesita para persistir credenciales.
+// repositories.CredentialRepository lo implementa.
+type CredentialStore interface {
+	GetByProvider(provider string) (*models.IntegrationCredential, error)
+	Save(credential *models.IntegrationCredential) error
+}
+
+// CredentialService guarda las credenciales de integraciones cifradas y las
+// entrega descifradas solo a los clientes del servidor
+type CredentialService struct {
+	store    CredentialStore
+	vault    *vault.Vault
+	fallback map[string]Credentials
+}
+
+// NewCredentialService crea el servicio. vault puede ser nil si no hay clave de
+// cifrado: en ese caso solo se pueden usar las credenciales de respaldo, que
+// vienen de la configuración y se indexan por proveedor.
+func NewCredentialService(store CredentialStore, v *vault.Vault, fallback map[string]Credentials) *CredentialService {
+	return &CredentialService{store: store, vault: v, fallback: fallback}
+}
+
+// Rotate reemplaza las credenciales de la integración, cifrando el secreto
+func (s *CredentialService) Rotate(provider, keyID, keySecret, actor string) (*models.IntegrationCredentialInfo, error) {
+	if s.vault == nil {
+		return nil, vault.ErrKeyNotConfigured
+	}
+
+	sealed, err := s.vault.Seal(keySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	credential := &models.IntegrationCredential{
+		Provider:        provider,
+		KeyID:           keyID,
+		SecretEncrypted: sealed,
+		UpdatedBy:       actor,
+	}
+	if err := s.store.Save(credential); err != nil {
+		return nil, err
+	}
+
+	return s.Info(provider)
+}
+
+// Info describe las credenciales activas de la integración sin exponer el secreto
+func (s *CredentialService) Info(provider string) (*models.IntegrationCredentialInfo, error) {
+	info := &models.IntegrationCredentialInfo{Provider: provider}
+
+	credential, err := s.store.GetByProvider(provider)
+	switch {
+	case err == nil:
+		info.Configured = true
+		info.Source = CredentialSourceVault
+		info.KeyID = vault.Mask(credential.KeyID)
+		info.UpdatedBy = credential.UpdatedBy
+		info.UpdatedAt = &credential.UpdatedAt
+	case errors.Is(err, repositories.ErrCredentialNotFound):
+		if fallback := s.fallback[provider]; fallback.KeyID != "" && fallback.KeySecret != "" {
+			info.Configured = true
+			info.Source = CredentialSourceEnv
+			info.KeyID = vault.Mask(fallback.KeyID)
+		}
+	default:
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Resolve devuelve las credenciales descifradas. Las guardadas en la base de
+// datos tienen prioridad sobre las de respaldo de la configuración.
+func (s *CredentialService) Resolve(provider string) (keyID, keySecret string, err error) {
+	credential, err := s.store.GetByProvider(provider)
+	if errors.Is(err, repositories.ErrCredentialNotFound) {
+		fallback := s.fallback[provider]
+		return fallback.KeyID, fallback.KeySecret, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if s.vault == nil {
+		return "", "", vault.ErrKeyNotConfigured
+	}
+	keySecret, err = s.vault.Open(credential.SecretEncrypted)
+	if err != nil {
+		return "", "", fmt.Errorf("credenciales de %s: %w", provider, err)
+	}
+
+	return credential.KeyID, keySecret, nil
+}
+
+// PermapeopleCredentials implementa permapeople.CredentialProvider
+func (s *CredentialService) PermapeopleCredentials(ctx context.Context) (permapeople.Credentials, error) {
+	keyID, keySecret, err := s.Resolve(models.IntegrationPermapeople)
+	if err != nil {
+		return permapeople.Credentials{}, err
+	}
+	return permapeople.Credentials{KeyID: keyID, KeySecret: keySecret}, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"math"
+
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// Ocupación de copa objetivo por estrato (porcentaje del área de la parcela)
+var stratumOccupancyTargets = []struct {
+	Stratum   string
+	TargetPct float64
+}{
+	{models.StratumEmergent, 20},
+	{models.StratumHigh, 40},
+	{models.StratumMedium, 60},
+	{models.StratumLow, 80},
+}
+
+// Diámetro de copa (m) que se asume cuando la especie no tiene uno propio
+var defaultCanopyDiameterM = map[string]float64{
+	models.StratumEmergent: 10,
+	models.StratumHigh:     6,
+	models.StratumMedium:   4,
+	models.StratumLow:      1.5,
+}
+
+// occupancyTolerancePct es el margen, en puntos porcentuales, alrededor del objetivo
+// dentro del cual la ocupación se considera adecuada
+const occupancyTolerancePct = 10
+
+// stratumCanopy acumula la cobertura de copa de un estrato
+type stratumCanopy struct {
+	areaM2    float64
+	plants    int
+	estimated int
+}
+
+// AnalyzePlotOccupancy calcula la ocupación real frente a la objetivo de cada
+// estrato de la parcela. Requiere PlantInstances con Species cargadas.
+func AnalyzePlotOccupancy(plot *models.Plot) models.PlotOccupancy {
+	area := plot.CalculateArea()
+	canopy := plotCanopy(plot)
+
+	return models.PlotOccupancy{
+		PlotID:   plot.ID,
+		PlotType: plot.PlotType,
+		AreaM2:   area,
+		Strata:   buildStrataOccupancy(canopy, area),
+	}
+}
+
+// AnalyzePlantationOccupancy calcula la ocupación de cada parcela y la agrega
+// para toda la plantación sobre la suma de las áreas de las parcelas.
+// Requiere la plantación cargada con PlantationRepository.GetWithLayout.
+func AnalyzePlantationOccupancy(plantation *models.Plantation) models.PlantationOccupancy {
+	total := make(map[string]*stratumCanopy)
+	totalArea := 0.0
+	plots := []models.PlotOccupancy{}
+
+	for i := range plantation.Plots {
+		plot := &plantation.Plots[i]
+		area := plot.CalculateArea()
+		canopy := plotCanopy(plot)
+
+		totalArea += area
+		for stratum, c := range canopy {
+			if total[stratum] == nil {
+				total[stratum] = &stratumCanopy{}
+			}
+			total[stratum].areaM2 += c.areaM2
+			total[stratum].plants += c.plants
+			total[stratum].estimated += c.estimated
+		}
+
+		plots = append(plots, models.PlotOccupancy{
+			PlotID:   plot.ID,
+			PlotType: plot.PlotType,
+			AreaM2:   area,
+			Strata:   buildStrataOccupancy(canopy, area),
+		})
+	}
+
+	return models.PlantationOccupancy{
+		PlantationID: plantation.ID,
+		AreaM2:       totalArea,
+		Strata:       buildStrataOccupancy(total, totalArea),
+		Plots:        plots,
+	}
+}
+
+// plotCanopy suma el área de copa de las plantas vivas de la parcela por estrato
+func plotCanopy(plot *models.Plot) map[string]*stratumCanopy {
+	canopy := make(map[string]*stratumCanopy)
+
+	for _, instance := range plot.PlantInstances {
+		if instance.Status == models.PlantStatusDead {
+			continue
+		}
+
+		stratum := instance.Species.Stratum
+		diameter, estimated := canopyDiameter(instance.Species)
+		if diameter <= 0 {
+			continue
+		}
+
+		if canopy[stratum] == nil {
+			canopy[stratum] = &stratumCanopy{}
+		}
+		radius := diameter / 2
+		canopy[stratum].areaM2 += float64(instance.Quantity) * math.Pi * radius * radius
+		canopy[stratum].plants += instance.Quantity
+		if estimated {
+			canopy[stratum].estimated += instance.Quantity
+		}
+	}
+
+	return canopy
+}
+
+// canopyDiameter devuelve el diámetro de copa de la especie o, si no lo tiene,
+// el valor por defecto de su estrato. estimated indica que se usó el valor por defecto.
+func canopyDiameter(species models.PlantSpecies) (diameter float64, estimated bool) {
+	if species.CanopyDiameterM != nil && *species.CanopyDiameterM > 0 {
+		return *species.CanopyDiameterM, false
+	}
+	return defaultCanopyDiameterM[species.Stratum], true
+}
+
+func buildStrataOccupancy(canopy map[string]*stratumCanopy, area float64) []models.StratumOccupancy {
+	strata := make([]models.StratumOccupancy, 0, len(stratumOccupancyTargets))
+
+	for _, target := range stratumOccupancyTargets {
+		occupancy := models.StratumOccupancy{
+			Stratum:   target.Stratum,
+			TargetPct: target.TargetPct,
+		}
+
+		if c := canopy[target.Stratum]; c != nil {
+			occupancy.CanopyAreaM2 = c.areaM2
+			occupancy.PlantCount = c.plants
+			occupancy.EstimatedPlants = c.estimated
+			if area > 0 {
+				occupancy.ActualPct = c.areaM2 / area * 100
+			}
+		}
+
+		switch {
+		case occupancy.ActualPct < target.TargetPct-occupancyTolerancePct:
+			occupancy.Status = models.OccupancyUnder
+		case occupancy.ActualPct > target.TargetPct+occupancyTolerancePct:
+			occupancy.Status = models.OccupancyOver
+		default:
+			occupancy.Status = models.OccupancyOK
+		}
+
+		strata = append(strata, occupancy)
+	}
+
+	return strata
+}
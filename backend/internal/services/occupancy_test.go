@@ -0,0 +1,102 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+func speciesWithCanopy(stratum string, diameter float64) models.PlantSpecies {
+	species := models.PlantSpecies{Stratum: stratum}
+	if diameter > 0 {
+		species.CanopyDiameterM = &diameter
+	}
+	return species
+}
+
+func plantedInstance(quantity int, species models.PlantSpecies) models.PlantInstance {
+	return models.PlantInstance{Quantity: quantity, Status: models.PlantStatusPlanted, Species: species}
+}
+
+// squarePlot es una línea de 10 x 10 m (100 m²)
+func squarePlot(id uint, instances ...models.PlantInstance) models.Plot {
+	return models.Plot{ID: id, PlotType: models.PlotTypeLine, LengthM: 10, WidthM: 10, PlantInstances: instances}
+}
+
+func TestAnalyzePlotOccupancy(t *testing.T) {
+	dead := plantedInstance(10, speciesWithCanopy(models.StratumEmergent, 0))
+	dead.Status = models.PlantStatusDead
+
+	plot := squarePlot(1,
+		plantedInstance(3, speciesWithCanopy(models.StratumHigh, 4)),   // 3 × π·2² ≈ 37.7 m²
+		plantedInstance(1, speciesWithCanopy(models.StratumMedium, 0)), // Diámetro por defecto de 4 m
+		plantedInstance(60, speciesWithCanopy(models.StratumLow, 0)),   // 60 × π·0.75² ≈ 106 m²
+		plantedInstance(5, speciesWithCanopy(models.StratumRoot, 0)),   // Sin diámetro: no ocupa copa
+		dead,
+	)
+
+	want := []struct {
+		stratum   string
+		actualPct float64
+		plants    int
+		estimated int
+		status    string
+	}{
+		{models.StratumEmergent, 0, 0, 0, models.OccupancyUnder},
+		{models.StratumHigh, 3 * math.Pi * 4, 3, 0, models.OccupancyOK},
+		{models.StratumMedium, math.Pi * 4, 1, 1, models.OccupancyUnder},
+		{models.StratumLow, 60 * math.Pi * 0.5625, 60, 60, models.OccupancyOver},
+	}
+
+	occupancy := AnalyzePlotOccupancy(&plot)
+	if occupancy.PlotID != 1 || occupancy.AreaM2 != 100 {
+		t.Fatalf("occupancy = %+v, se esperaba la parcela 1 con 100 m²", occupancy)
+	}
+	if len(occupancy.Strata) != len(want) {
+		t.Fatalf("strata = %+v, se esperaban %d estratos", occupancy.Strata, len(want))
+	}
+
+	for i, w := range want {
+		got := occupancy.Strata[i]
+		if got.Stratum != w.stratum || math.Abs(got.ActualPct-w.actualPct) > 1e-6 ||
+			got.PlantCount != w.plants || got.EstimatedPlants != w.estimated || got.Status != w.status {
+			t.Errorf("strata[%d] = %+v, se esperaba %+v", i, got, w)
+		}
+	}
+}
+
+func TestAnalyzePlotOccupancyWithoutArea(t *testing.T) {
+	plot := models.Plot{PlotType: models.PlotTypeLine, PlantInstances: []models.PlantInstance{
+		plantedInstance(2, speciesWithCanopy(models.StratumHigh, 3)),
+	}}
+
+	for _, s := range AnalyzePlotOccupancy(&plot).Strata {
+		if s.ActualPct != 0 {
+			t.Errorf("%s: actual_pct = %v, se esperaba 0 sin área", s.Stratum, s.ActualPct)
+		}
+	}
+}
+
+func TestAnalyzePlantationOccupancy(t *testing.T) {
+	plantation := models.Plantation{ID: 5, Plots: []models.Plot{
+		squarePlot(1, plantedInstance(4, speciesWithCanopy(models.StratumHigh, 4))),
+		squarePlot(2, plantedInstance(2, speciesWithCanopy(models.StratumHigh, 4))),
+	}}
+
+	occupancy := AnalyzePlantationOccupancy(&plantation)
+	if occupancy.PlantationID != 5 || occupancy.AreaM2 != 200 || len(occupancy.Plots) != 2 {
+		t.Fatalf("occupancy = %+v, se esperaban 2 parcelas y 200 m²", occupancy)
+	}
+
+	high := occupancy.Strata[1]
+	wantPct := 6 * math.Pi * 4 / 200 * 100
+	if high.Stratum != models.StratumHigh || high.PlantCount != 6 || math.Abs(high.ActualPct-wantPct) > 1e-6 {
+		t.Errorf("estrato alto = %+v, se esperaba %.2f%% con 6 plantas", high, wantPct)
+	}
+
+	// Cada parcela conserva su propio análisis
+	if got := occupancy.Plots[0].Strata[1].ActualPct; math.Abs(got-4*math.Pi*4) > 1e-6 {
+		t.Errorf("parcela 1: actual_pct = %v, se esperaba %v", got, 4*math.Pi*4)
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// Estratos que un diseño sintrópico completo debe ocupar, de arriba hacia abajo
+var recommendedStrata = []string{
+	models.StratumEmergent,
+	models.StratumHigh,
+	models.StratumMedium,
+	models.StratumLow,
+	models.StratumGround,
+}
+
+// Etapas sucesionales en orden, de la placenta al clímax
+var recommendedSuccession = []string{
+	models.SuccessionPlacenta,
+	models.SuccessionPioneer,
+	models.SuccessionSecondary,
+	models.SuccessionClimax,
+}
+
+// Funciones de servicio que toda parcela debería tener cubiertas
+var recommendedFunctions = []string{
+	models.FunctionNitrogenFixer,
+	models.FunctionBiomassProduction,
+	models.FunctionGroundCover,
+	models.FunctionDynamicAccumulator,
+	models.FunctionPollinator,
+}
+
+// Peso de cada tipo de hueco en la puntuación de una especie.
+// Los estratos vacíos pesan más porque dejan luz sin aprovechar.
+const (
+	scoreStratum    = 3
+	scoreSuccession = 2
+	scoreFunction   = 2
+)
+
+// AnalyzePlotGaps calcula los estratos, etapas y funciones que faltan entre las
+// plantas vivas de la parcela. Requiere PlantInstances con Species cargadas.
+func AnalyzePlotGaps(plot *models.Plot) models.PlotGaps {
+	strata := make(map[string]bool)
+	stages := make(map[string]bool)
+	functions := make(map[string]bool)
+
+	for _, instance := range plot.PlantInstances {
+		if instance.Status == models.PlantStatusDead {
+			continue
+		}
+		strata[instance.Species.Stratum] = true
+		stages[instance.Species.SuccessionStage] = true
+		for _, function := range instance.Species.FunctionNames() {
+			functions[function] = true
+		}
+	}
+
+	return models.PlotGaps{
+		MissingStrata:     missingValues(recommendedStrata, strata),
+		MissingSuccession: missingValues(recommendedSuccession, stages),
+		MissingFunctions:  missingValues(recommendedFunctions, functions),
+	}
+}
+
+// SpeciesInPlot devuelve los IDs de las especies vivas presentes en la parcela
+func SpeciesInPlot(plot *models.Plot) []uint {
+	seen := make(map[uint]bool)
+	ids := []uint{}
+	for _, instance := range plot.PlantInstances {
+		if instance.Status == models.PlantStatusDead || seen[instance.SpeciesID] {
+			continue
+		}
+		seen[instance.SpeciesID] = true
+		ids = append(ids, instance.SpeciesID)
+	}
+	return ids
+}
+
+// RecommendSpecies puntúa las especies candidatas según cuántos huecos de la
+// parcela cubren y devuelve las limit mejores, de mayor a menor puntuación
+func RecommendSpecies(plot *models.Plot, candidates []models.PlantSpecies, limit int) *models.PlotRecommendations {
+	gaps := AnalyzePlotGaps(plot)
+
+	present := make(map[uint]bool)
+	for _, id := range SpeciesInPlot(plot) {
+		present[id] = true
+	}
+
+	recommendations := []models.SpeciesRecommendation{}
+	for _, species := range candidates {
+		if present[species.ID] {
+			continue
+		}
+
+		rec := models.SpeciesRecommendation{Species: species}
+		if contains(gaps.MissingStrata, species.Stratum) {
+			rec.Score += scoreStratum
+			rec.Fills = append(rec.Fills, "estrato:"+species.Stratum)
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("ocupa el estrato vacío %q", species.Stratum))
+		}
+		if contains(gaps.MissingSuccession, species.SuccessionStage) {
+			rec.Score += scoreSuccession
+			rec.Fills = append(rec.Fills, "sucesion:"+species.SuccessionStage)
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("aporta la etapa sucesional %q", species.SuccessionStage))
+		}
+		for _, function := range species.FunctionNames() {
+			if contains(gaps.MissingFunctions, function) {
+				rec.Score += scoreFunction
+				rec.Fills = append(rec.Fills, "funcion:"+function)
+				rec.Reasons = append(rec.Reasons, fmt.Sprintf("cumple la función %q", function))
+			}
+		}
+
+		if rec.Score > 0 {
+			recommendations = append(recommendations, rec)
+		}
+	}
+
+	// Mayor puntuación primero; a igual puntuación, orden alfabético
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Species.CommonName < recommendations[j].Species.CommonName
+	})
+
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return &models.PlotRecommendations{
+		PlotID:          plot.ID,
+		Gaps:            gaps,
+		Recommendations: recommendations,
+	}
+}
+
+func missingValues(expected []string, present map[string]bool) []string {
+	missing := []string{}
+	for _, v := range expected {
+		if !present[v] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+func contains(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// EvaluateTemplate evalúa una plantación contra las reglas de una plantilla.
+// La plantación debe venir con sus parcelas, instancias y especies cargadas
+// (ver PlantationRepository.GetWithLayout). Las plantas muertas no cuentan.
+func EvaluateTemplate(template *models.SuggestionTemplate, plantation *models.Plantation, now time.Time) (*models.ComplianceReport, error) {
+	rules, err := models.ParseTemplateRules(template.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidTemplateRules, err)
+	}
+
+	report := &models.ComplianceReport{
+		TemplateID:     template.ID,
+		TemplateName:   template.Name,
+		PlantationID:   plantation.ID,
+		Compliant:      true,
+		PlotsEvaluated: len(plantation.Plots),
+		Rules:          []models.RuleResult{},
+		EvaluatedAt:    now,
+	}
+
+	if rules.MaxDensity != nil {
+		report.Rules = append(report.Rules, evaluateMaxDensity(*rules.MaxDensity, plantation.Plots))
+	}
+
+	if len(rules.RequiredStrata) > 0 {
+		report.Rules = append(report.Rules, evaluateRequiredValues(
+			models.RuleRequiredStrata, rules.RequiredStrata, plantation.Plots,
+			func(species models.PlantSpecies) string { return species.Stratum },
+			"faltan estratos",
+		))
+	}
+
+	if len(rules.MinSuccession) > 0 {
+		report.Rules = append(report.Rules, evaluateRequiredValues(
+			models.RuleMinSuccession, rules.MinSuccession, plantation.Plots,
+			func(species models.PlantSpecies) string { return species.SuccessionStage },
+			"faltan etapas sucesionales",
+		))
+	}
+
+	for _, result := range report.Rules {
+		if !result.Passed {
+			report.Compliant = false
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// evaluateMaxDensity marca las parcelas cuya densidad de plantas vivas supera el máximo
+func evaluateMaxDensity(maxDensity float64, plots []models.Plot) models.RuleResult {
+	result := models.RuleResult{
+		Rule:           models.RuleMaxDensity,
+		Expected:       maxDensity,
+		OffendingPlots: []models.PlotViolation{},
+	}
+
+	for _, plot := range plots {
+		metrics := models.NewPlotWithMetrics(plot, livePlantCount(plot.PlantInstances))
+		if metrics.AreaM2 <= 0 || metrics.DensityPerM2 <= maxDensity {
+			continue
+		}
+
+		result.OffendingPlots = append(result.OffendingPlots, models.PlotViolation{
+			PlotID:   plot.ID,
+			PlotType: plot.PlotType,
+			Actual:   metrics.DensityPerM2,
+			Message: fmt.Sprintf("densidad %.2f plantas/m² supera el máximo de %.2f",
+				metrics.DensityPerM2, maxDensity),
+		})
+	}
+
+	result.Passed = len(result.OffendingPlots) == 0
+	result.Message = fmt.Sprintf("%d de %d parcelas cumplen", len(plots)-len(result.OffendingPlots), len(plots))
+	return result
+}
+
+// evaluateRequiredValues marca las parcelas a las que les falta alguno de los
+// valores requeridos (estratos o etapas) entre las especies de sus plantas vivas
+func evaluateRequiredValues(rule string, required []string, plots []models.Plot, valueOf func(models.PlantSpecies) string, missingMsg string) models.RuleResult {
+	result := models.RuleResult{
+		Rule:           rule,
+		Expected:       required,
+		OffendingPlots: []models.PlotViolation{},
+	}
+
+	// Una plantación sin parcelas no puede cubrir ningún requisito
+	if len(plots) == 0 {
+		result.Message = "la plantación no tiene parcelas"
+		return result
+	}
+
+	for _, plot := range plots {
+		present := make(map[string]bool)
+		presentList := []string{}
+		for _, instance := range plot.PlantInstances {
+			if instance.Status == models.PlantStatusDead {
+				continue
+			}
+			value := valueOf(instance.Species)
+			if value != "" && !present[value] {
+				present[value] = true
+				presentList = append(presentList, value)
+			}
+		}
+
+		var missing []string
+		for _, value := range required {
+			if !present[value] {
+				missing = append(missing, value)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		result.OffendingPlots = append(result.OffendingPlots, models.PlotViolation{
+			PlotID:   plot.ID,
+			PlotType: plot.PlotType,
+			Actual:   presentList,
+			Missing:  missing,
+			Message:  fmt.Sprintf("%s: %v", missingMsg, missing),
+		})
+	}
+
+	result.Passed = len(result.OffendingPlots) == 0
+	result.Message = fmt.Sprintf("%d de %d parcelas cumplen", len(plots)-len(result.OffendingPlots), len(plots))
+	return result
+}
+
+// livePlantCount suma la cantidad de plantas que no están muertas
+func livePlantCount(instances []models.PlantInstance) int {
+	total := 0
+	for _, instance := range instances {
+		if instance.Status != models.PlantStatusDead {
+			total += instance.Quantity
+		}
+	}
+	return total
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/deibys/sintronia/pkg/models"
+)
+
+// linePlot es una línea de 10 m² con las instancias indicadas
+func linePlot(id uint, instances ...models.PlantInstance) models.Plot {
+	return models.Plot{ID: id, PlotType: models.PlotTypeLine, LengthM: 5, WidthM: 2, PlantInstances: instances}
+}
+
+func instance(quantity int, status, stratum, stage string) models.PlantInstance {
+	return models.PlantInstance{
+		Quantity: quantity,
+		Status:   status,
+		Species:  models.PlantSpecies{Stratum: stratum, SuccessionStage: stage},
+	}
+}
+
+func TestEvaluateTemplate(t *testing.T) {
+	plots := []models.Plot{
+		linePlot(1,
+			instance(10, models.PlantStatusPlanted, models.StratumHigh, models.SuccessionPioneer),
+			instance(5, models.PlantStatusPlanted, models.StratumLow, models.SuccessionSecondary),
+		),
+		linePlot(2,
+			instance(30, models.PlantStatusPlanted, models.StratumHigh, models.SuccessionPioneer),
+			instance(50, models.PlantStatusDead, models.StratumLow, models.SuccessionSecondary),
+		),
+	}
+
+	cases := []struct {
+		name      string
+		rules     string
+		compliant bool
+		results   map[string][]uint // Regla → parcelas que la incumplen
+	}{
+		{
+			name:      "sin reglas",
+			rules:     "",
+			compliant: true,
+			results:   map[string][]uint{},
+		},
+		{
+			name:      "densidad máxima ignora plantas muertas",
+			rules:     `{"densidad_maxima": 2}`,
+			compliant: false,
+			results:   map[string][]uint{models.RuleMaxDensity: {2}},
+		},
+		{
+			name:      "densidad máxima holgada",
+			rules:     `{"densidad_maxima": 5}`,
+			compliant: true,
+			results:   map[string][]uint{models.RuleMaxDensity: {}},
+		},
+		{
+			name:      "estratos requeridos con plantas muertas",
+			rules:     `{"estratos_requeridos": ["alto", "bajo"]}`,
+			compliant: false,
+			results:   map[string][]uint{models.RuleRequiredStrata: {2}},
+		},
+		{
+			name:      "etapas sucesionales",
+			rules:     `{"sucesion_minima": ["pionera"]}`,
+			compliant: true,
+			results:   map[string][]uint{models.RuleMinSuccession: {}},
+		},
+		{
+			name:      "varias reglas",
+			rules:     `{"densidad_maxima": 5, "estratos_requeridos": ["alto"], "sucesion_minima": ["pionera", "secundaria"]}`,
+			compliant: false,
+			results: map[string][]uint{
+				models.RuleMaxDensity:     {},
+				models.RuleRequiredStrata: {},
+				models.RuleMinSuccession:  {2},
+			},
+		},
+	}
+
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			template := &models.SuggestionTemplate{ID: 3, Name: "Base", Rules: tc.rules}
+			plantation := &models.Plantation{ID: 9, Plots: plots}
+
+			report, err := EvaluateTemplate(template, plantation, now)
+			if err != nil {
+				t.Fatalf("EvaluateTemplate: %v", err)
+			}
+
+			if report.Compliant != tc.compliant {
+				t.Errorf("compliant = %t, se esperaba %t", report.Compliant, tc.compliant)
+			}
+			if report.TemplateID != 3 || report.PlantationID != 9 || report.PlotsEvaluated != 2 || !report.EvaluatedAt.Equal(now) {
+				t.Errorf("report = %+v, datos de cabecera inesperados", report)
+			}
+
+			got := make(map[string][]uint, len(report.Rules))
+			for _, result := range report.Rules {
+				ids := []uint{}
+				for _, violation := range result.OffendingPlots {
+					ids = append(ids, violation.PlotID)
+				}
+				got[result.Rule] = ids
+				if result.Passed != (len(ids) == 0) {
+					t.Errorf("%s: passed = %t con parcelas %v", result.Rule, result.Passed, ids)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.results) {
+				t.Errorf("parcelas que incumplen = %v, se esperaba %v", got, tc.results)
+			}
+		})
+	}
+}
+
+func TestEvaluateTemplateMissingValues(t *testing.T) {
+	template := &models.SuggestionTemplate{Rules: `{"estratos_requeridos": ["alto", "bajo", "medio"]}`}
+	plantation := &models.Plantation{Plots: []models.Plot{
+		linePlot(4, instance(1, models.PlantStatusPlanted, models.StratumHigh, "")),
+	}}
+
+	report, err := EvaluateTemplate(template, plantation, time.Now())
+	if err != nil {
+		t.Fatalf("EvaluateTemplate: %v", err)
+	}
+
+	violations := report.Rules[0].OffendingPlots
+	if len(violations) != 1 || !reflect.DeepEqual(violations[0].Missing, []string{"bajo", "medio"}) {
+		t.Fatalf("violations = %+v, se esperaba que falten bajo y medio", violations)
+	}
+}
+
+func TestEvaluateTemplateWithoutPlots(t *testing.T) {
+	template := &models.SuggestionTemplate{Rules: `{"densidad_maxima": 1, "estratos_requeridos": ["alto"]}`}
+
+	report, err := EvaluateTemplate(template, &models.Plantation{}, time.Now())
+	if err != nil {
+		t.Fatalf("EvaluateTemplate: %v", err)
+	}
+
+	// La densidad se cumple sin parcelas, pero los estratos no se pueden cubrir
+	if report.Compliant || !report.Rules[0].Passed || report.Rules[1].Passed {
+		t.Errorf("report = %+v, se esperaba incumplimiento solo de estratos", report)
+	}
+}
+
+func TestEvaluateTemplateInvalidRules(t *testing.T) {
+	cases := map[string]string{
+		"json inválido":     `{"densidad_maxima":`,
+		"regla desconocida": `{"otra": 1}`,
+		"densidad negativa": `{"densidad_maxima": -1}`,
+		"estrato inválido":  `{"estratos_requeridos": ["nube"]}`,
+		"etapa repetida":    `{"sucesion_minima": ["pionera", "pionera"]}`,
+	}
+
+	for name, rules := range cases {
+		t.Run(name, func(t *testing.T) {
+			template := &models.SuggestionTemplate{Rules: rules}
+			_, err := EvaluateTemplate(template, &models.Plantation{}, time.Now())
+			if !errors.Is(err, models.ErrInvalidTemplateRules) {
+				t.Errorf("err = %v, se esperaba ErrInvalidTemplateRules", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package tenant
+
+import (
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Filtros de cada tabla de la organización. Las tablas hijas se filtran a
+// través de su sitio para no duplicar organization_id en cada una.
+const (
+	sitesOfTenant       = "SELECT id FROM sites WHERE organization_id = ?"
+	plantationsOfTenant = "SELECT plantations.id FROM plantations JOIN sites ON sites.id = plantations.site_id WHERE sites.organization_id = ?"
+	plotsOfTenant       = "SELECT plots.id FROM plots JOIN plantations ON plantations.id = plots.plantation_id JOIN sites ON sites.id = plantations.site_id WHERE sites.organization_id = ?"
+	instancesOfTenant   = "SELECT plant_instances.id FROM plant_instances JOIN plots ON plots.id = plant_instances.plot_id JOIN plantations ON plantations.id = plots.plantation_id JOIN sites ON sites.id = plantations.site_id WHERE sites.organization_id = ?"
+)
+
+var scopedTables = map[string]string{
+	"sites":                 "sites.organization_id = ?",
+	"plantations":           "plantations.site_id IN (" + sitesOfTenant + ")",
+	"site_members":          "site_members.site_id IN (" + sitesOfTenant + ")",
+	"suggestion_templates":  "suggestion_templates.plantation_id IN (" + plantationsOfTenant + ")",
+	"plots":                 "plots.plantation_id IN (" + plantationsOfTenant + ")",
+	"plant_instances":       "plant_instances.plot_id IN (" + plotsOfTenant + ")",
+	"plant_instance_events": "plant_instance_events.plant_instance_id IN (" + instancesOfTenant + ")",
+}
+
+// Tabla del catálogo de especies: las especies globales (sin organización) se
+// leen desde cualquier organización, pero solo se modifican con All
+const speciesTable = "plant_species"
+
+// RegisterCallbacks registra los filtros por organización en la conexión
+func RegisterCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", scopeRead); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", scopeRead); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", scopeWrite); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", scopeWrite); err != nil {
+		return err
+	}
+	return cb.Create().Before("gorm:create").Register("tenant:create", assignOnCreate)
+}
+
+func scopeRead(db *gorm.DB) {
+	applyScope(db, false)
+}
+
+func scopeWrite(db *gorm.DB) {
+	applyScope(db, true)
+}
+
+func applyScope(db *gorm.DB, write bool) {
+	table := db.Statement.Table
+	condition, scoped := scopedTables[table]
+	if !scoped && table != speciesTable {
+		return
+	}
+
+	t, ok := FromContext(db.Statement.Context)
+	if !ok {
+		db.AddError(ErrTenantRequired)
+		return
+	}
+	if t.all {
+		return
+	}
+
+	if table == speciesTable {
+		switch {
+		case write:
+			// Solo las especies propias; las globales requieren All
+			db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "plant_species.organization_id = ?", Vars: []interface{}{t.OrganizationID}},
+			}})
+		case t.OrganizationID == 0:
+			db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "plant_species.organization_id IS NULL"},
+			}})
+		default:
+			db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "(plant_species.organization_id IS NULL OR plant_species.organization_id = ?)", Vars: []interface{}{t.OrganizationID}},
+			}})
+		}
+		return
+	}
+
+	if t.OrganizationID == 0 {
+		db.AddError(ErrTenantRequired)
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: condition, Vars: []interface{}{t.OrganizationID}},
+	}})
+}
+
+// assignOnCreate completa organization_id al crear sitios y especies. Las tablas
+// hijas no lo necesitan: los repositorios verifican el padre con una consulta
+// filtrada antes de crear.
+func assignOnCreate(db *gorm.DB) {
+	table := db.Statement.Table
+	if table != "sites" && table != speciesTable {
+		return
+	}
+
+	t, ok := FromContext(db.Statement.Context)
+	if !ok {
+		db.AddError(ErrTenantRequired)
+		return
+	}
+	if t.all {
+		return // Sitios con organización explícita o especies del catálogo global
+	}
+	if t.OrganizationID == 0 {
+		db.AddError(ErrTenantRequired)
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField("OrganizationID")
+	if field == nil {
+		return
+	}
+	if db.Statement.ReflectValue.Kind() == reflect.Struct {
+		if value, isZero := field.ValueOf(db.Statement.Context, db.Statement.ReflectValue); !isZero {
+			if id := organizationIDOf(value); id != 0 && id != t.OrganizationID {
+				db.AddError(ErrTenantMismatch)
+				return
+			}
+		}
+	}
+
+	organizationID := t.OrganizationID
+	if table == speciesTable {
+		db.Statement.SetColumn("OrganizationID", &organizationID)
+		return
+	}
+	db.Statement.SetColumn("OrganizationID", organizationID)
+}
+
+func organizationIDOf(value interface{}) uint {
+	switch v := value.(type) {
+	case uint:
+		return v
+	case *uint:
+		if v != nil {
+			return *v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,246 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Modelos mínimos con las tablas que filtran los callbacks
+type site struct {
+	ID             uint
+	OrganizationID uint
+	Name           string
+}
+
+func (site) TableName() string { return "sites" }
+
+type species struct {
+	ID             uint
+	OrganizationID *uint
+	CommonName     string
+}
+
+func (species) TableName() string { return "plant_species" }
+
+type plot struct {
+	ID           uint
+	PlantationID uint
+}
+
+func (plot) TableName() string { return "plots" }
+
+type user struct {
+	ID    uint
+	Email string
+}
+
+func (user) TableName() string { return "users" }
+
+// dryRunDB arma el SQL sin conectarse a PostgreSQL
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	if err := RegisterCallbacks(db); err != nil {
+		t.Fatalf("RegisterCallbacks: %v", err)
+	}
+	return db
+}
+
+// withTenant devuelve el contexto con el Tenant, o sin él si t es nil
+func withTenant(t *Tenant) context.Context {
+	if t == nil {
+		return context.Background()
+	}
+	return WithTenant(context.Background(), *t)
+}
+
+func tenantPtr(t Tenant) *Tenant {
+	return &t
+}
+
+func TestScopeQueries(t *testing.T) {
+	db := dryRunDB(t)
+
+	cases := []struct {
+		name    string
+		tenant  *Tenant
+		run     func(tx *gorm.DB) *gorm.DB
+		wantSQL string // Fragmento esperado en el WHERE ("" = sin filtro)
+		vars    []interface{}
+		wantErr error
+	}{
+		{
+			name:    "sitios de la organización",
+			tenant:  tenantPtr(Organization(7)),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]site{}) },
+			wantSQL: "sites.organization_id = $1",
+			vars:    []interface{}{uint(7)},
+		},
+		{
+			name:    "tabla hija filtrada a través del sitio",
+			tenant:  tenantPtr(Organization(7)),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]plot{}) },
+			wantSQL: "plots.plantation_id IN (SELECT plantations.id FROM plantations JOIN sites",
+			vars:    []interface{}{uint(7)},
+		},
+		{
+			name:    "conteo",
+			tenant:  tenantPtr(Organization(7)),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Model(&site{}).Count(new(int64)) },
+			wantSQL: "sites.organization_id = $1",
+			vars:    []interface{}{uint(7)},
+		},
+		{
+			name:    "actualización",
+			tenant:  tenantPtr(Organization(7)),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Model(&site{ID: 3}).Update("name", "Finca") },
+			wantSQL: "sites.organization_id = $",
+		},
+		{
+			name:    "borrado",
+			tenant:  tenantPtr(Organization(7)),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Delete(&site{ID: 3}) },
+			wantSQL: "sites.organization_id = $",
+		},
+		{
+			name:    "All no filtra",
+			tenant:  tenantPtr(All()),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]site{}) },
+			wantSQL: "",
+		},
+		{
+			name:    "sin tenant falla",
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]site{}) },
+			wantErr: ErrTenantRequired,
+		},
+		{
+			name:    "el catálogo no ve sitios",
+			tenant:  tenantPtr(Catalog()),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]plot{}) },
+			wantErr: ErrTenantRequired,
+		},
+		{
+			name:    "tablas sin organización no se filtran",
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]user{}) },
+			wantSQL: "",
+		},
+		{
+			name:    "especies globales y propias",
+			tenant:  tenantPtr(Organization(7)),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]species{}) },
+			wantSQL: "(plant_species.organization_id IS NULL OR plant_species.organization_id = $1)",
+			vars:    []interface{}{uint(7)},
+		},
+		{
+			name:    "el catálogo solo ve especies globales",
+			tenant:  tenantPtr(Catalog()),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]species{}) },
+			wantSQL: "plant_species.organization_id IS NULL",
+		},
+		{
+			name:    "solo se modifican especies propias",
+			tenant:  tenantPtr(Organization(7)),
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Model(&species{ID: 3}).Update("common_name", "Guamo") },
+			wantSQL: "plant_species.organization_id = $",
+		},
+		{
+			name:    "sin tenant no se leen especies",
+			run:     func(tx *gorm.DB) *gorm.DB { return tx.Find(&[]species{}) },
+			wantErr: ErrTenantRequired,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := tc.run(db.WithContext(withTenant(tc.tenant)))
+
+			if !errors.Is(tx.Error, tc.wantErr) {
+				t.Fatalf("err = %v, se esperaba %v", tx.Error, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			sql := tx.Statement.SQL.String()
+			if tc.wantSQL == "" {
+				if strings.Contains(sql, "organization_id") {
+					t.Errorf("SQL = %q, no se esperaba filtro por organización", sql)
+				}
+				return
+			}
+			if !strings.Contains(sql, tc.wantSQL) {
+				t.Errorf("SQL = %q, se esperaba %q", sql, tc.wantSQL)
+			}
+			if tc.vars != nil && !reflect.DeepEqual(tx.Statement.Vars, tc.vars) {
+				t.Errorf("vars = %v, se esperaba %v", tx.Statement.Vars, tc.vars)
+			}
+		})
+	}
+}
+
+func TestAssignOnCreate(t *testing.T) {
+	db := dryRunDB(t)
+
+	cases := []struct {
+		name     string
+		tenant   *Tenant
+		existing uint // organization_id que ya trae el sitio
+		want     uint
+		wantErr  error
+	}{
+		{name: "asigna la organización", tenant: tenantPtr(Organization(7)), want: 7},
+		{name: "acepta la misma organización", tenant: tenantPtr(Organization(7)), existing: 7, want: 7},
+		{name: "rechaza otra organización", tenant: tenantPtr(Organization(7)), existing: 8, wantErr: ErrTenantMismatch},
+		{name: "All respeta la organización explícita", tenant: tenantPtr(All()), existing: 8, want: 8},
+		{name: "el catálogo no crea sitios", tenant: tenantPtr(Catalog()), wantErr: ErrTenantRequired},
+		{name: "sin tenant falla", wantErr: ErrTenantRequired},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			record := site{Name: "Finca", OrganizationID: tc.existing}
+			err := db.WithContext(withTenant(tc.tenant)).Create(&record).Error
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, se esperaba %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil && record.OrganizationID != tc.want {
+				t.Errorf("organization_id = %d, se esperaba %d", record.OrganizationID, tc.want)
+			}
+		})
+	}
+}
+
+func TestAssignOnCreateSpecies(t *testing.T) {
+	db := dryRunDB(t)
+
+	private := species{CommonName: "Guamo"}
+	if err := db.WithContext(WithTenant(context.Background(), Organization(7))).Create(&private).Error; err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if private.OrganizationID == nil || *private.OrganizationID != 7 {
+		t.Errorf("organization_id = %v, se esperaba 7", private.OrganizationID)
+	}
+
+	// Con All la especie queda en el catálogo global
+	global := species{CommonName: "Guamo"}
+	if err := db.WithContext(WithTenant(context.Background(), All())).Create(&global).Error; err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if global.OrganizationID != nil {
+		t.Errorf("organization_id = %v, se esperaba NULL", *global.OrganizationID)
+	}
+}
@@ -0,0 +1,62 @@
+// Package tenant aísla los datos de cada organización. Los repositorios
+// consultan con un contexto que lleva el Tenant y los callbacks de GORM
+// registrados por RegisterCallbacks filtran cada consulta, actualización y
+// borrado de las tablas de la organización. Sin Tenant en el contexto esas
+// consultas fallan con ErrTenantRequired en lugar de devolver filas de todos.
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrTenantRequired se devuelve al consultar datos de una organización sin indicar cuál
+	ErrTenantRequired = errors.New("organización requerida para acceder a estos datos")
+	// ErrTenantMismatch se devuelve al crear un registro para otra organización
+	ErrTenantMismatch = errors.New("el registro pertenece a otra organización")
+)
+
+// Tenant identifica la organización con la que se accede a los datos
+type Tenant struct {
+	OrganizationID uint
+	all            bool
+}
+
+// Organization devuelve el Tenant de una organización
+func Organization(id uint) Tenant {
+	return Tenant{OrganizationID: id}
+}
+
+// Catalog devuelve un Tenant sin organización: solo ve el catálogo global de
+// especies. Es el que se usa para las consultas públicas.
+func Catalog() Tenant {
+	return Tenant{}
+}
+
+// All devuelve un Tenant sin filtros, para tareas administrativas (importaciones,
+// CLI) y el catálogo global. Nunca debe salir de una solicitud de usuario común.
+func All() Tenant {
+	return Tenant{all: true}
+}
+
+// IsAll indica si el Tenant accede a los datos de todas las organizaciones
+func (t Tenant) IsAll() bool {
+	return t.all
+}
+
+type contextKey struct{}
+
+// WithTenant agrega el Tenant al contexto
+func WithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext obtiene el Tenant del contexto
+func FromContext(ctx context.Context) (Tenant, bool) {
+	if ctx == nil {
+		return Tenant{}, false
+	}
+	t, ok := ctx.Value(contextKey{}).(Tenant)
+	return t, ok
+}
@@ -0,0 +1,101 @@
+// Package vault cifra los secretos de integraciones externas antes de guardarlos
+// en la base de datos (AES-256-GCM con una clave tomada de la configuración).
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyEnvVar es la variable de entorno con la clave de cifrado en base64 (32 bytes)
+const KeyEnvVar = "CREDENTIALS_ENCRYPTION_KEY"
+
+var (
+	// ErrKeyNotConfigured se devuelve cuando no hay clave de cifrado configurada
+	ErrKeyNotConfigured = errors.New("clave de cifrado de credenciales no configurada (" + KeyEnvVar + ")")
+	// ErrInvalidKey se devuelve cuando la clave no es base64 de 32 bytes
+	ErrInvalidKey = errors.New("la clave de cifrado debe ser base64 de 32 bytes")
+	// ErrDecrypt se devuelve cuando el secreto no se puede descifrar con la clave actual
+	ErrDecrypt = errors.New("no se pudo descifrar el secreto")
+)
+
+// Vault cifra y descifra secretos
+type Vault struct {
+	aead cipher.AEAD
+}
+
+// New crea un vault con una clave de 32 bytes
+func New(key []byte) (*Vault, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error inicializando cifrado: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error inicializando cifrado: %w", err)
+	}
+
+	return &Vault{aead: aead}, nil
+}
+
+// FromKey crea un vault con la clave en base64 de la configuración
+// (CREDENTIALS_ENCRYPTION_KEY)
+func FromKey(encoded string) (*Vault, error) {
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, ErrKeyNotConfigured
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return New(key)
+}
+
+// Seal cifra plaintext y devuelve nonce+ciphertext en base64
+func (v *Vault) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generando nonce: %w", err)
+	}
+
+	sealed := v.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open descifra un valor producido por Seal
+func (v *Vault) Open(sealed string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < v.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+
+	nonce, ciphertext := raw[:v.aead.NonceSize()], raw[v.aead.NonceSize():]
+	plaintext, err := v.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// Mask oculta un secreto para mostrarlo en logs o respuestas: solo deja
+// visibles los últimos 4 caracteres si el valor es lo bastante largo
+func Mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
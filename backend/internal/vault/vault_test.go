@@ -0,0 +1,125 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func testVault(t *testing.T, fill byte) *Vault {
+	t.Helper()
+	v, err := New(bytes.Repeat([]byte{fill}, 32))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return v
+}
+
+func TestSealOpen(t *testing.T) {
+	v := testVault(t, 1)
+
+	for _, plaintext := range []string{"", "secreto", "clave con ñ y símbolos ✓", string(bytes.Repeat([]byte("x"), 4096))} {
+		sealed, err := v.Seal(plaintext)
+		if err != nil {
+			t.Fatalf("Seal: %v", err)
+		}
+		if plaintext != "" && bytes.Contains([]byte(sealed), []byte(plaintext)) {
+			t.Errorf("el valor cifrado contiene el texto plano")
+		}
+
+		opened, err := v.Open(sealed)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		if opened != plaintext {
+			t.Errorf("Open = %q, se esperaba %q", opened, plaintext)
+		}
+	}
+}
+
+func TestSealUsesRandomNonce(t *testing.T) {
+	v := testVault(t, 1)
+
+	first, _ := v.Seal("secreto")
+	second, _ := v.Seal("secreto")
+	if first == second {
+		t.Error("dos cifrados del mismo valor no deben coincidir")
+	}
+}
+
+func TestOpenRejectsInvalidInput(t *testing.T) {
+	v := testVault(t, 1)
+	sealed, err := v.Seal("secreto")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	raw, _ := base64.StdEncoding.DecodeString(sealed)
+	raw[len(raw)-1] ^= 0xff
+	tampered := base64.StdEncoding.EncodeToString(raw)
+
+	otherKey, _ := testVault(t, 2).Seal("secreto")
+
+	cases := map[string]string{
+		"base64 inválido": "%%%",
+		"demasiado corto": base64.StdEncoding.EncodeToString([]byte("abc")),
+		"alterado":        tampered,
+		"otra clave":      otherKey,
+	}
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := v.Open(value); !errors.Is(err, ErrDecrypt) {
+				t.Errorf("err = %v, se esperaba ErrDecrypt", err)
+			}
+		})
+	}
+}
+
+func TestNewRequires32ByteKey(t *testing.T) {
+	for _, size := range []int{0, 16, 31, 33} {
+		if _, err := New(make([]byte, size)); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("New(%d bytes): err = %v, se esperaba ErrInvalidKey", size, err)
+		}
+	}
+}
+
+func TestFromKey(t *testing.T) {
+	cases := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{"sin clave", "", ErrKeyNotConfigured},
+		{"base64 inválido", "no-es-base64!", ErrInvalidKey},
+		{"largo incorrecto", base64.StdEncoding.EncodeToString(make([]byte, 16)), ErrInvalidKey},
+		{"válida", base64.StdEncoding.EncodeToString(make([]byte, 32)), nil},
+		{"válida con espacios", " " + base64.StdEncoding.EncodeToString(make([]byte, 32)) + "\n", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := FromKey(tc.value)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, se esperaba %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil && v == nil {
+				t.Fatal("se esperaba un vault")
+			}
+		})
+	}
+}
+
+func TestMask(t *testing.T) {
+	cases := map[string]string{
+		"":                 "",
+		"corto":            "****",
+		"12345678":         "****",
+		"clave-muy-larga1": "****rga1",
+	}
+	for secret, want := range cases {
+		if got := Mask(secret); got != want {
+			t.Errorf("Mask(%q) = %q, se esperaba %q", secret, got, want)
+		}
+	}
+}
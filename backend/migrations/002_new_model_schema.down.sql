@@ -0,0 +1,18 @@
+-- 🌱 Migración 002 (down): elimina el modelo jerárquico con sus vistas, funciones y datos
+
+DROP VIEW IF EXISTS v_popular_species;
+DROP VIEW IF EXISTS v_plantation_summary;
+DROP VIEW IF EXISTS v_plant_instances_full;
+
+DROP FUNCTION IF EXISTS get_plant_instance_hierarchy(BIGINT);
+DROP FUNCTION IF EXISTS calculate_plant_density(BIGINT, INTEGER);
+DROP FUNCTION IF EXISTS calculate_plot_area(VARCHAR, DECIMAL, DECIMAL, DECIMAL);
+
+DROP TABLE IF EXISTS suggestion_templates;
+DROP TABLE IF EXISTS plant_instances;
+DROP TABLE IF EXISTS plots;
+DROP TABLE IF EXISTS plant_species;
+DROP TABLE IF EXISTS plantations;
+DROP TABLE IF EXISTS sites;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
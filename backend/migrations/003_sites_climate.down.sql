@@ -0,0 +1,3 @@
+-- 🌱 Migración 003 (down): quita el clima de los sitios
+
+ALTER TABLE sites DROP COLUMN IF EXISTS climate;
@@ -0,0 +1,6 @@
+-- 🌱 Migración 003: Clima de los sitios
+-- La columna existe en el modelo Go (models.Site.Climate) pero no en el esquema 002
+
+ALTER TABLE sites ADD COLUMN IF NOT EXISTS climate TEXT;
+
+COMMENT ON COLUMN sites.climate IS 'Descripción del clima del sitio (ej: "tropical húmedo")';
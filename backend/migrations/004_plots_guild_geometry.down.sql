@@ -0,0 +1,4 @@
+-- 🌱 Migración 004 (down): quita la geometría de las parcelas tipo gremio
+
+ALTER TABLE plots DROP COLUMN IF EXISTS geometry;
+ALTER TABLE plots DROP COLUMN IF EXISTS radius_m;
@@ -0,0 +1,8 @@
+-- 🌱 Migración 004: Geometría de parcelas tipo gremio
+-- Los gremios (guild) toman su área del polígono GeoJSON o de un radio explícito
+
+ALTER TABLE plots ADD COLUMN IF NOT EXISTS radius_m DECIMAL(10,2);
+ALTER TABLE plots ADD COLUMN IF NOT EXISTS geometry TEXT;
+
+COMMENT ON COLUMN plots.radius_m IS 'Radio en metros (para gremios sin geometría)';
+COMMENT ON COLUMN plots.geometry IS 'Polígono GeoJSON de la parcela (obligatorio en gremios sin radio)';
@@ -0,0 +1,5 @@
+-- 🌱 Migración 005 (down): quita el último cambio de estado de las instancias
+-- La columna "order" se conserva: la usa models.PlantInstance desde el esquema 002.
+
+ALTER TABLE plant_instances DROP COLUMN IF EXISTS status_changed_by;
+ALTER TABLE plant_instances DROP COLUMN IF EXISTS status_changed_at;
@@ -0,0 +1,14 @@
+-- 🌱 Migración 005: Ciclo de vida de instancias de plantas
+-- Registra quién y cuándo hizo el último cambio de estado.
+-- También agrega la columna "order" que usa models.PlantInstance y faltaba en el esquema 002.
+
+ALTER TABLE plant_instances ADD COLUMN IF NOT EXISTS "order" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE plant_instances ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE plant_instances ADD COLUMN IF NOT EXISTS status_changed_by VARCHAR(100);
+
+-- Las instancias existentes toman como último cambio su fecha de actualización
+UPDATE plant_instances SET status_changed_at = updated_at WHERE status_changed_at IS NULL;
+
+COMMENT ON COLUMN plant_instances."order" IS 'Orden de la instancia dentro de la parcela';
+COMMENT ON COLUMN plant_instances.status_changed_at IS 'Fecha del último cambio de estado';
+COMMENT ON COLUMN plant_instances.status_changed_by IS 'Usuario que hizo el último cambio de estado';
@@ -0,0 +1,3 @@
+-- 🌱 Migración 006 (down): elimina la historia de estados de las instancias
+
+DROP TABLE IF EXISTS plant_instance_events;
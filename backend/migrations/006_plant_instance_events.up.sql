@@ -0,0 +1,64 @@
+-- 🌱 Migración 006: Historia de estados de instancias de plantas
+-- Cada cambio de estado queda registrado en lugar de sobrescribir el anterior
+
+CREATE TABLE IF NOT EXISTS plant_instance_events (
+    id BIGSERIAL PRIMARY KEY,
+    plant_instance_id BIGINT NOT NULL REFERENCES plant_instances(id) ON DELETE CASCADE,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL CHECK (to_status IN (
+        'planned', 'germinated', 'planted', 'established',
+        'productive', 'dormant', 'dead'
+    )),
+    actor VARCHAR(100),
+    source VARCHAR(20) NOT NULL DEFAULT 'api' CHECK (source IN ('api', 'backfill')),
+    notes TEXT,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_plant_instance_events_plant_instance_id ON plant_instance_events(plant_instance_id);
+CREATE INDEX IF NOT EXISTS idx_plant_instance_events_to_status ON plant_instance_events(to_status);
+CREATE INDEX IF NOT EXISTS idx_plant_instance_events_occurred_at ON plant_instance_events(occurred_at);
+
+COMMENT ON TABLE plant_instance_events IS 'Historia de cambios de estado de las instancias de plantas';
+COMMENT ON COLUMN plant_instance_events.from_status IS 'Estado anterior (NULL en el alta)';
+COMMENT ON COLUMN plant_instance_events.source IS 'api: registrado por la API; backfill: reconstruido por esta migración';
+
+-- Backfill: reconstruir la historia de las instancias que aún no tienen eventos.
+-- Toda instancia arranca en created_at: 'planted' si ya estaba plantada ese día, si no 'planned'.
+-- Si planted_at es posterior al alta se agrega el paso a 'planted', y si el estado
+-- actual es otro se agrega un último evento en status_changed_at/updated_at.
+WITH pending AS (
+    SELECT
+        pi.id,
+        pi.status,
+        pi.created_at,
+        pi.planted_at,
+        COALESCE(pi.status_changed_at, pi.updated_at, pi.created_at) AS changed_at,
+        CASE
+            WHEN pi.planted_at IS NOT NULL AND pi.planted_at <= pi.created_at::date THEN 'planted'
+            ELSE 'planned'
+        END AS initial_status,
+        (pi.planted_at IS NOT NULL AND pi.planted_at > pi.created_at::date) AS planted_later
+    FROM plant_instances pi
+    WHERE NOT EXISTS (
+        SELECT 1 FROM plant_instance_events e WHERE e.plant_instance_id = pi.id
+    )
+), steps AS (
+    SELECT
+        p.*,
+        CASE WHEN p.planted_later THEN 'planted' ELSE p.initial_status END AS last_backfilled_status
+    FROM pending p
+)
+INSERT INTO plant_instance_events (plant_instance_id, from_status, to_status, actor, source, occurred_at)
+SELECT id, NULL, initial_status, 'sistema', 'backfill', created_at
+FROM steps
+UNION ALL
+SELECT id, initial_status, 'planted', 'sistema', 'backfill', planted_at::timestamptz
+FROM steps
+WHERE planted_later
+UNION ALL
+SELECT id, last_backfilled_status, status, 'sistema', 'backfill',
+       GREATEST(changed_at, created_at, COALESCE(planted_at::timestamptz, created_at))
+FROM steps
+WHERE status <> last_backfilled_status;
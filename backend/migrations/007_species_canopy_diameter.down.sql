@@ -0,0 +1,4 @@
+-- 🌱 Migración 007 (down): quita el diámetro de copa de las especies
+
+ALTER TABLE plant_species DROP CONSTRAINT IF EXISTS plant_species_canopy_diameter_check;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS canopy_diameter_m;
@@ -0,0 +1,16 @@
+-- 🌱 Migración 007: Diámetro de copa por especie
+-- Lo usa el análisis de ocupación por estrato para estimar la cobertura de cada planta.
+
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS canopy_diameter_m DECIMAL(6,2);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'plant_species_canopy_diameter_check'
+    ) THEN
+        ALTER TABLE plant_species ADD CONSTRAINT plant_species_canopy_diameter_check
+            CHECK (canopy_diameter_m IS NULL OR canopy_diameter_m > 0);
+    END IF;
+END $$;
+
+COMMENT ON COLUMN plant_species.canopy_diameter_m IS 'Diámetro de copa adulta en metros';
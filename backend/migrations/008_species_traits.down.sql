@@ -0,0 +1,18 @@
+-- 🌱 Migración 008 (down): quita los rasgos de las especies
+
+ALTER TABLE plant_species DROP CONSTRAINT IF EXISTS plant_species_traits_check;
+
+DROP INDEX IF EXISTS idx_plant_species_mature_height;
+DROP INDEX IF EXISTS idx_plant_species_lifespan;
+DROP INDEX IF EXISTS idx_plant_species_light;
+DROP INDEX IF EXISTS idx_plant_species_water_needs;
+DROP INDEX IF EXISTS idx_plant_species_frost_tolerance;
+
+ALTER TABLE plant_species DROP COLUMN IF EXISTS frost_tolerance;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS water_needs;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS root_depth;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS light_requirement;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS time_to_harvest_months;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS lifespan_years;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS spacing_m;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS mature_height_m;
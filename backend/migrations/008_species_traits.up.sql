@@ -0,0 +1,45 @@
+-- 🌱 Migración 008: Rasgos de especies para diseño y análisis
+-- Altura, espaciamiento, longevidad, cosecha, luz, raíces, agua y heladas.
+-- El diámetro de copa se agregó en la migración 007.
+
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS mature_height_m DECIMAL(6,2);
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS spacing_m DECIMAL(6,2);
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS lifespan_years DECIMAL(8,2);
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS time_to_harvest_months INTEGER;
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS light_requirement VARCHAR(20);
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS root_depth VARCHAR(20);
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS water_needs VARCHAR(20);
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS frost_tolerance VARCHAR(20);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'plant_species_traits_check') THEN
+        ALTER TABLE plant_species ADD CONSTRAINT plant_species_traits_check CHECK (
+            (mature_height_m IS NULL OR (mature_height_m > 0 AND mature_height_m <= 150)) AND
+            (canopy_diameter_m IS NULL OR canopy_diameter_m <= 60) AND
+            (spacing_m IS NULL OR (spacing_m > 0 AND spacing_m <= 50)) AND
+            (lifespan_years IS NULL OR (lifespan_years > 0 AND lifespan_years <= 5000)) AND
+            (time_to_harvest_months IS NULL OR (time_to_harvest_months >= 0 AND time_to_harvest_months <= 1200)) AND
+            (light_requirement IS NULL OR light_requirement IN ('', 'pleno_sol', 'media_sombra', 'sombra')) AND
+            (root_depth IS NULL OR root_depth IN ('', 'superficial', 'media', 'profunda')) AND
+            (water_needs IS NULL OR water_needs IN ('', 'baja', 'media', 'alta')) AND
+            (frost_tolerance IS NULL OR frost_tolerance IN ('', 'sensible', 'semi_resistente', 'resistente'))
+        );
+    END IF;
+END $$;
+
+-- Índices para los filtros por rango y por categoría del catálogo
+CREATE INDEX IF NOT EXISTS idx_plant_species_mature_height ON plant_species(mature_height_m);
+CREATE INDEX IF NOT EXISTS idx_plant_species_lifespan ON plant_species(lifespan_years);
+CREATE INDEX IF NOT EXISTS idx_plant_species_light ON plant_species(light_requirement);
+CREATE INDEX IF NOT EXISTS idx_plant_species_water_needs ON plant_species(water_needs);
+CREATE INDEX IF NOT EXISTS idx_plant_species_frost_tolerance ON plant_species(frost_tolerance);
+
+COMMENT ON COLUMN plant_species.mature_height_m IS 'Altura adulta en metros';
+COMMENT ON COLUMN plant_species.spacing_m IS 'Espaciamiento recomendado entre plantas en metros';
+COMMENT ON COLUMN plant_species.lifespan_years IS 'Longevidad en años';
+COMMENT ON COLUMN plant_species.time_to_harvest_months IS 'Meses hasta la primera cosecha';
+COMMENT ON COLUMN plant_species.light_requirement IS 'Requerimiento de luz: pleno_sol, media_sombra, sombra';
+COMMENT ON COLUMN plant_species.root_depth IS 'Profundidad de raíz: superficial, media, profunda';
+COMMENT ON COLUMN plant_species.water_needs IS 'Necesidad de agua: baja, media, alta';
+COMMENT ON COLUMN plant_species.frost_tolerance IS 'Tolerancia a heladas: sensible, semi_resistente, resistente';
@@ -0,0 +1,4 @@
+-- 🌱 Migración 009 (down): elimina las funciones múltiples por especie
+-- plant_species.function_ecol conserva la función principal de cada especie.
+
+DROP TABLE IF EXISTS species_functions;
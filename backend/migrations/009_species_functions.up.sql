@@ -0,0 +1,41 @@
+-- 🌱 Migración 009: Funciones ecológicas múltiples por especie
+-- Una especie puede cumplir varias funciones (p. ej. Leucaena: fijadora de nitrógeno,
+-- productora de biomasa y cortaviento). species_functions es la fuente de verdad;
+-- plant_species.function_ecol se mantiene como copia de la función principal para
+-- compatibilidad con las vistas y clientes existentes.
+
+CREATE TABLE IF NOT EXISTS species_functions (
+    species_id BIGINT NOT NULL REFERENCES plant_species(id) ON DELETE CASCADE,
+    function VARCHAR(100) NOT NULL CHECK (function IN (
+        'fijador_nitrogeno', 'acumulador_dinamico', 'cobertura_suelo', 'cortaviento',
+        'polinizador', 'control_plagas', 'aireacion_suelo', 'regulacion_agua',
+        'produccion_biomasa', 'alimentario', 'medicinal', 'maderable', 'fibra', 'ornamental'
+    )),
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (species_id, function)
+);
+
+CREATE INDEX IF NOT EXISTS idx_species_functions_function ON species_functions(function);
+
+-- Como máximo una función principal por especie
+CREATE UNIQUE INDEX IF NOT EXISTS idx_species_functions_primary
+    ON species_functions(species_id) WHERE is_primary;
+
+-- Backfill: la función única actual pasa a ser la principal
+INSERT INTO species_functions (species_id, function, is_primary)
+SELECT id, function_ecol, TRUE
+FROM plant_species
+WHERE function_ecol IS NOT NULL AND function_ecol <> ''
+ON CONFLICT DO NOTHING;
+
+-- Datos de ejemplo: funciones secundarias de la Leucaena
+INSERT INTO species_functions (species_id, function, is_primary)
+SELECT ps.id, f.function, FALSE
+FROM plant_species ps
+CROSS JOIN (VALUES ('produccion_biomasa'), ('cortaviento')) AS f(function)
+WHERE ps.scientific_name = 'Leucaena leucocephala'
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE species_functions IS 'Funciones ecológicas de cada especie';
+COMMENT ON COLUMN species_functions.is_primary IS 'Función principal (copiada en plant_species.function_ecol)';
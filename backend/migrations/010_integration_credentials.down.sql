@@ -0,0 +1,3 @@
+-- 🌱 Migración 010 (down): elimina las credenciales de integraciones externas
+
+DROP TABLE IF EXISTS integration_credentials;
@@ -0,0 +1,22 @@
+-- 🌱 Migración 010: Credenciales de integraciones externas
+-- Las credenciales de APIs de terceros (Permapeople) se guardan en el servidor.
+-- El secreto se guarda cifrado con AES-256-GCM; la clave vive en la variable de
+-- entorno CREDENTIALS_ENCRYPTION_KEY y nunca en la base de datos.
+
+CREATE TABLE IF NOT EXISTS integration_credentials (
+    id BIGSERIAL PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL UNIQUE CHECK (provider IN ('permapeople')),
+    key_id VARCHAR(255) NOT NULL,
+    secret_encrypted TEXT NOT NULL,
+    updated_by VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMENT ON TABLE integration_credentials IS 'Credenciales de APIs externas, con el secreto cifrado';
+COMMENT ON COLUMN integration_credentials.secret_encrypted IS 'Nonce + secreto cifrado con AES-256-GCM, en base64';
+
+DROP TRIGGER IF EXISTS update_integration_credentials_updated_at ON integration_credentials;
+CREATE TRIGGER update_integration_credentials_updated_at
+    BEFORE UPDATE ON integration_credentials
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
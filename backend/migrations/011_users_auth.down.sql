@@ -0,0 +1,4 @@
+-- 🌱 Migración 011 (down): elimina los usuarios y sus tokens de renovación
+
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS users;
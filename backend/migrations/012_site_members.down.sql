@@ -0,0 +1,3 @@
+-- 🌱 Migración 012 (down): elimina los miembros de los sitios
+
+DROP TABLE IF EXISTS site_members;
@@ -0,0 +1,25 @@
+-- 🌱 Migración 012: Miembros de sitios con roles
+-- Cada finca (sitio) tiene sus propios miembros: owner, designer, field_worker
+-- y viewer. Los permisos de cada rol se verifican en la API.
+-- Los sitios existentes no tienen miembros: solo los administradores los ven
+-- hasta que se les asigne un owner.
+
+CREATE TABLE IF NOT EXISTS site_members (
+    id BIGSERIAL PRIMARY KEY,
+    site_id BIGINT NOT NULL REFERENCES sites(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'designer', 'field_worker', 'viewer')),
+    invited_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_site_members_site_user ON site_members(site_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_site_members_user_id ON site_members(user_id);
+
+COMMENT ON TABLE site_members IS 'Usuarios de cada sitio y su rol';
+
+DROP TRIGGER IF EXISTS update_site_members_updated_at ON site_members;
+CREATE TRIGGER update_site_members_updated_at
+    BEFORE UPDATE ON site_members
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
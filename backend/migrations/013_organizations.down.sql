@@ -0,0 +1,28 @@
+-- 🌱 Migración 013 (down): elimina las organizaciones
+-- Las especies privadas y los overrides de las organizaciones se eliminan: sin
+-- organizaciones pasarían a formar parte del catálogo global.
+
+DELETE FROM plant_species WHERE organization_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_plant_species_org_override;
+DROP INDEX IF EXISTS idx_plant_species_org_external_ref;
+
+ALTER TABLE plant_species DROP CONSTRAINT IF EXISTS plant_species_override_check;
+ALTER TABLE plant_species DROP CONSTRAINT IF EXISTS plant_species_overrides_species_id_fkey;
+ALTER TABLE plant_species DROP CONSTRAINT IF EXISTS plant_species_organization_id_fkey;
+ALTER TABLE sites DROP CONSTRAINT IF EXISTS sites_organization_id_fkey;
+
+ALTER TABLE plant_species DROP COLUMN IF EXISTS overrides_species_id;
+ALTER TABLE plant_species DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE sites DROP COLUMN IF EXISTS organization_id;
+
+-- external_ref vuelve a ser único en toda la tabla
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'plant_species_external_ref_key') THEN
+        ALTER TABLE plant_species ADD CONSTRAINT plant_species_external_ref_key UNIQUE (external_ref);
+    END IF;
+END $$;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,103 @@
+-- 🌱 Migración 013: Organizaciones (multi-tenant)
+-- Cada sitio pertenece a una organización y todo lo que cuelga de él (plantaciones,
+-- parcelas, instancias, eventos, plantillas y miembros) queda aislado en ella.
+-- El catálogo de especies es mixto: las especies sin organización forman el
+-- catálogo global compartido y cada organización puede tener especies privadas
+-- o copias propias (overrides) de especies globales.
+-- Los sitios y usuarios existentes pasan a la organización "Organización por defecto".
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_slug ON organizations(slug);
+CREATE INDEX IF NOT EXISTS idx_organizations_deleted_at ON organizations(deleted_at);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'member')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+
+-- Sitios: la organización es la raíz del aislamiento. Un sitio sin organización
+-- no es visible para nadie (solo con acceso administrativo completo).
+ALTER TABLE sites ADD COLUMN IF NOT EXISTS organization_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_sites_organization_id ON sites(organization_id);
+
+-- Especies: NULL = catálogo global
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS organization_id BIGINT;
+ALTER TABLE plant_species ADD COLUMN IF NOT EXISTS overrides_species_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_plant_species_organization_id ON plant_species(organization_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'sites_organization_id_fkey') THEN
+        ALTER TABLE sites ADD CONSTRAINT sites_organization_id_fkey
+            FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'plant_species_organization_id_fkey') THEN
+        ALTER TABLE plant_species ADD CONSTRAINT plant_species_organization_id_fkey
+            FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'plant_species_overrides_species_id_fkey') THEN
+        ALTER TABLE plant_species ADD CONSTRAINT plant_species_overrides_species_id_fkey
+            FOREIGN KEY (overrides_species_id) REFERENCES plant_species(id) ON DELETE SET NULL;
+    END IF;
+    -- Solo una organización puede sobrescribir una especie global, nunca el catálogo global
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'plant_species_override_check') THEN
+        ALTER TABLE plant_species ADD CONSTRAINT plant_species_override_check
+            CHECK (overrides_species_id IS NULL OR organization_id IS NOT NULL);
+    END IF;
+END $$;
+
+-- external_ref deja de ser único en toda la tabla: es único dentro del catálogo
+-- global y dentro de cada organización
+ALTER TABLE plant_species DROP CONSTRAINT IF EXISTS plant_species_external_ref_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_plant_species_org_external_ref
+    ON plant_species(COALESCE(organization_id, 0), external_ref)
+    WHERE external_ref IS NOT NULL AND external_ref <> '' AND deleted_at IS NULL;
+
+-- Como máximo un override vigente por especie global en cada organización
+CREATE UNIQUE INDEX IF NOT EXISTS idx_plant_species_org_override
+    ON plant_species(organization_id, overrides_species_id)
+    WHERE overrides_species_id IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON TABLE organizations IS 'Organizaciones (tenants): cada una ve solo sus sitios y especies';
+COMMENT ON TABLE organization_members IS 'Usuarios de cada organización';
+COMMENT ON COLUMN plant_species.organization_id IS 'Organización dueña de la especie; NULL = catálogo global';
+COMMENT ON COLUMN plant_species.overrides_species_id IS 'Especie global que esta copia reemplaza para la organización';
+
+DROP TRIGGER IF EXISTS update_organizations_updated_at ON organizations;
+CREATE TRIGGER update_organizations_updated_at
+    BEFORE UPDATE ON organizations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Backfill: organización por defecto con los sitios y usuarios existentes
+INSERT INTO organizations (name, slug)
+SELECT 'Organización por defecto', 'default'
+WHERE EXISTS (SELECT 1 FROM sites WHERE organization_id IS NULL)
+   OR EXISTS (SELECT 1 FROM users u WHERE NOT EXISTS (
+        SELECT 1 FROM organization_members om WHERE om.user_id = u.id))
+ON CONFLICT DO NOTHING;
+
+UPDATE sites SET organization_id = o.id
+FROM organizations o
+WHERE o.slug = 'default' AND sites.organization_id IS NULL;
+
+INSERT INTO organization_members (organization_id, user_id, role)
+SELECT o.id, u.id, CASE WHEN u.role = 'admin' THEN 'owner' ELSE 'member' END
+FROM organizations o
+CROSS JOIN users u
+WHERE o.slug = 'default'
+  AND u.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM organization_members om WHERE om.user_id = u.id)
+ON CONFLICT DO NOTHING;
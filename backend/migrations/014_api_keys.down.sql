@@ -0,0 +1,3 @@
+-- 🌱 Migración 014 (down): elimina las API keys personales
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,24 @@
+-- 🌱 Migración 014: API keys personales
+-- Claves para scripts e integraciones que no pueden iniciar sesión. Cada clave
+-- actúa en nombre de su usuario limitada a sus scopes (read:catalog, write:plots...).
+-- Solo se guarda el hash SHA-256 de la clave.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash ON api_keys(key_hash);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+COMMENT ON TABLE api_keys IS 'API keys personales (solo el hash)';
+COMMENT ON COLUMN api_keys.scopes IS 'Lista JSON de scopes, p. ej. ["read:catalog", "write:plots"]';
+COMMENT ON COLUMN api_keys.prefix IS 'Inicio de la clave para reconocerla en listados';
@@ -0,0 +1,3 @@
+-- 🌱 Migración 015 (down): elimina la auditoría de cambios
+
+DROP TABLE IF EXISTS audit_events;
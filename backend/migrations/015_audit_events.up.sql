@@ -0,0 +1,27 @@
+-- 🌱 Migración 015: Auditoría de cambios
+-- Cada alta, modificación y baja de los modelos del dominio queda registrada con
+-- su autor, la solicitud y las columnas que cambiaron (valor anterior y nuevo).
+-- Sin claves foráneas: el historial se conserva aunque se borre el usuario o el registro.
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT,
+    api_key_id BIGINT,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'upsert')),
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    request_id VARCHAR(64),
+    ip VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_request_id ON audit_events(request_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at);
+
+COMMENT ON TABLE audit_events IS 'Auditoría de los cambios de los modelos del dominio';
+COMMENT ON COLUMN audit_events.entity_type IS 'Tabla del registro modificado';
+COMMENT ON COLUMN audit_events.entity_id IS 'Clave primaria del registro (las compuestas separadas por ":")';
+COMMENT ON COLUMN audit_events.changes IS 'Columnas modificadas: {"columna": {"before": ..., "after": ...}}';
@@ -0,0 +1,11 @@
+// Package migrations contiene las migraciones SQL del esquema, embebidas en el
+// binario. Cada versión tiene un archivo NNN_nombre.up.sql y su reversión
+// NNN_nombre.down.sql; las aplica internal/migrate.
+package migrations
+
+import "embed"
+
+// FS contiene los archivos .sql de este directorio
+//
+//go:embed *.sql
+var FS embed.FS
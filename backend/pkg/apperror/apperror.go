@@ -0,0 +1,125 @@
+// Package apperror define los errores del dominio: cada uno tiene un tipo
+// (ErrNotFound, ErrConflict, ErrValidation...) que middleware.ErrorHandler
+// traduce al estado HTTP, y un código estable que los clientes pueden comparar
+// en lugar del mensaje.
+package apperror
+
+import (
+	"errors"
+)
+
+// Tipos de error. Se comparan con errors.Is: errors.Is(err, apperror.ErrNotFound)
+// es verdadero para cualquier error "no encontrado" del dominio.
+var (
+	ErrNotFound     = errors.New("recurso no encontrado")
+	ErrConflict     = errors.New("conflicto con el estado actual del recurso")
+	ErrValidation   = errors.New("datos inválidos")
+	ErrUnauthorized = errors.New("autenticación requerida")
+	ErrForbidden    = errors.New("acceso denegado")
+	ErrInternal     = errors.New("error interno")
+)
+
+// Códigos genéricos, para los errores que no tienen uno propio
+const (
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeValidation   = "validation_failed"
+	CodeInvalidJSON  = "invalid_json"
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+	CodeInternal     = "internal_error"
+	CodeUnavailable  = "service_unavailable"
+)
+
+// Códigos de FieldError
+const (
+	FieldRequired   = "required"
+	FieldInvalid    = "invalid"
+	FieldOutOfRange = "out_of_range"
+	FieldDuplicate  = "duplicate"
+	FieldType       = "invalid_type"
+)
+
+// FieldError describe un problema de un campo de la solicitud
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error es un error del dominio. Message se muestra al cliente; Err es la causa
+// interna y solo se registra en el log.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Details []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap permite comparar con el tipo (errors.Is(err, ErrNotFound)) y con la causa
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// NotFound crea un error de recurso inexistente
+func NotFound(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+// Conflict crea un error por el estado actual del recurso (duplicados, reglas del dominio)
+func Conflict(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+// Unauthorized crea un error de credenciales faltantes o inválidas
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden crea un error de permisos
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+// Invalid crea un error de validación de un campo
+func Invalid(field, code, message string) *Error {
+	return Validation(FieldError{Field: field, Code: code, Message: message})
+}
+
+// Validation crea un error de validación con el detalle de cada campo. El
+// mensaje es el del primer campo, para los clientes que solo leen "error".
+func Validation(details ...FieldError) *Error {
+	message := ErrValidation.Error()
+	if len(details) > 0 {
+		message = details[0].Message
+	}
+	return &Error{Kind: ErrValidation, Code: CodeValidation, Message: message, Details: details}
+}
+
+// BadRequest crea un error de solicitud mal formada (p. ej. JSON inválido) con
+// un código propio, sin detalle por campo
+func BadRequest(code, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+// Internal envuelve un error inesperado: el cliente solo ve message
+func Internal(message string, err error) *Error {
+	return &Error{Kind: ErrInternal, Code: CodeInternal, Message: message, Err: err}
+}
+
+// As obtiene el error del dominio de la cadena de err
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
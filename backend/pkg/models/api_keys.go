@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+)
+
+// Recursos sobre los que se otorgan permisos a una API key. Cada recurso tiene
+// un scope de lectura (read:<recurso>) y uno de escritura (write:<recurso>).
+const (
+	APIResourceCatalog       = "catalog"       // Catálogo de especies (/plantas)
+	APIResourceOrganizations = "organizations" // Organizaciones y sus miembros
+	APIResourceSites         = "sites"         // Sitios y sus miembros
+	APIResourcePlantations   = "plantations"   // Plantaciones y plantillas de sugerencias
+	APIResourcePlots         = "plots"         // Parcelas
+	APIResourceInstances     = "instances"     // Instancias de plantas
+	APIResourceAdmin         = "admin"         // Rutas de administración (requiere rol admin)
+)
+
+// Acciones de un scope
+const (
+	APIActionRead  = "read"
+	APIActionWrite = "write"
+)
+
+// APIScope arma el scope de una acción sobre un recurso, p. ej. "write:plots"
+func APIScope(action, resource string) string {
+	return action + ":" + resource
+}
+
+// GetAPIKeyScopes devuelve los scopes válidos de una API key
+func GetAPIKeyScopes() []string {
+	resources := []string{
+		APIResourceCatalog, APIResourceOrganizations, APIResourceSites,
+		APIResourcePlantations, APIResourcePlots, APIResourceInstances, APIResourceAdmin,
+	}
+	scopes := make([]string, 0, len(resources)*2)
+	for _, resource := range resources {
+		scopes = append(scopes, APIScope(APIActionRead, resource), APIScope(APIActionWrite, resource))
+	}
+	return scopes
+}
+
+// IsValidAPIKeyScope verifica si un scope es válido
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range GetAPIKeyScopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey es una clave personal para scripts e integraciones. Actúa en nombre de
+// su usuario, pero solo en los recursos de sus scopes. Solo se guarda el hash
+// SHA-256 de la clave; el secreto se muestra una única vez al crearla.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Prefix     string     `json:"prefix" gorm:"type:varchar(20);not null"` // Inicio de la clave, para reconocerla
+	KeyHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scopes     []string   `json:"scopes" gorm:"type:text;not null;serializer:json"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName define el nombre de la tabla
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive indica si la clave no fue revocada ni venció
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// HasScope indica si la clave tiene el scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKeyRequest estructura para crear una API key. Sin expires_in_days la
+// clave vence a los 90 días.
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// APIKeyCreatedResponse incluye la clave completa, que no se vuelve a mostrar
+type APIKeyCreatedResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+)
+
+// Acciones registradas en la auditoría
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionUpsert = "upsert" // Alta que reemplaza un registro existente (p. ej. credenciales)
+)
+
+// GetAuditActions devuelve las acciones válidas de la auditoría
+func GetAuditActions() []string {
+	return []string{AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionUpsert}
+}
+
+// IsValidAuditAction verifica si una acción de auditoría es válida
+func IsValidAuditAction(action string) bool {
+	for _, a := range GetAuditActions() {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// AuditChange es el valor de una columna antes y después del cambio. En las
+// altas Before es nulo y en las bajas After es nulo.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEvent registra un cambio de un registro del dominio: quién lo hizo, desde
+// qué solicitud y qué columnas cambiaron. Las columnas que la API nunca expone
+// (contraseñas, secretos) se registran como "[oculto]".
+type AuditEvent struct {
+	ID         uint                   `json:"id" gorm:"primaryKey"`
+	ActorID    *uint                  `json:"actor_id" gorm:"index"` // Usuario que hizo el cambio (nulo en tareas internas)
+	APIKeyID   *uint                  `json:"api_key_id,omitempty"`  // API key con la que se autenticó, si se usó una
+	Action     string                 `json:"action" gorm:"type:varchar(10);not null"`
+	EntityType string                 `json:"entity_type" gorm:"type:varchar(50);not null;index:idx_audit_events_entity"` // Tabla del registro
+	EntityID   string                 `json:"entity_id" gorm:"type:varchar(100);not null;index:idx_audit_events_entity"`  // Clave primaria (compuesta: "1:2")
+	Changes    map[string]AuditChange `json:"changes" gorm:"type:jsonb;not null;serializer:json"`
+	RequestID  string                 `json:"request_id,omitempty" gorm:"type:varchar(64);index"`
+	IP         string                 `json:"ip,omitempty" gorm:"type:varchar(45)"`
+	CreatedAt  time.Time              `json:"created_at" gorm:"index"`
+}
+
+// TableName define el nombre de la tabla
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // Tipos de parcela
 const (
 	PlotTypeLine   = "line"   // Líneas de plantación
@@ -25,6 +27,26 @@ const (
 	PlantStatusDead        = "dead"        // Muerta
 )
 
+// plantStatusTransitions define la máquina de estados de una instancia de planta.
+// El ciclo principal es planned → germinated → planted → established → productive;
+// dormant y dead son ramas laterales. dead es terminal.
+var plantStatusTransitions = map[string][]string{
+	PlantStatusPlanned:     {PlantStatusGerminated, PlantStatusDead},
+	PlantStatusGerminated:  {PlantStatusPlanted, PlantStatusDormant, PlantStatusDead},
+	PlantStatusPlanted:     {PlantStatusEstablished, PlantStatusDormant, PlantStatusDead},
+	PlantStatusEstablished: {PlantStatusProductive, PlantStatusDormant, PlantStatusDead},
+	PlantStatusProductive:  {PlantStatusDormant, PlantStatusDead},
+	PlantStatusDormant:     {PlantStatusEstablished, PlantStatusProductive, PlantStatusDead},
+	PlantStatusDead:        {},
+}
+
+// initialPlantStatuses son los estados con los que se puede registrar una
+// instancia nueva. Los demás solo se alcanzan mediante transiciones.
+var initialPlantStatuses = []string{PlantStatusPlanned, PlantStatusGerminated, PlantStatusPlanted}
+
+// plantedStatuses son los estados en los que la planta ya está en el campo
+var plantedStatuses = []string{PlantStatusPlanted, PlantStatusEstablished, PlantStatusProductive}
+
 // Etapas sucesionales según Ernst Götsch
 const (
 	SuccessionPlacenta  = "placenta"   // Preparación del suelo
@@ -71,7 +93,9 @@ const (
 	PlantingModeTree     = "arbol"   // Árboles desarrollados
 )
 
-// Estados del ciclo de plantación
+// Estados del ciclo de plantación en español (vocabulario anterior).
+// Ya no se almacenan: se aceptan en la entrada y se traducen al estado
+// canónico PlantStatus* con NormalizePlantStatus.
 const (
 	StatusPlanned     = "planeada"    // En planificación
 	StatusGerminating = "germinacion" // En proceso de germinación
@@ -93,6 +117,43 @@ const (
 	SoilTypeAnegadizo = "anegadizo" // Propenso a encharcamiento
 )
 
+// Requerimiento de luz de una especie
+const (
+	LightFullSun      = "pleno_sol"    // Sol directo todo el día
+	LightPartialShade = "media_sombra" // Sol filtrado o parte del día
+	LightShade        = "sombra"       // Tolera sombra densa
+)
+
+// Profundidad del sistema radicular
+const (
+	RootDepthShallow = "superficial" // Raíces en los primeros 30 cm
+	RootDepthMedium  = "media"       // Entre 30 cm y 1 m
+	RootDepthDeep    = "profunda"    // Más de 1 m, pivotantes
+)
+
+// Necesidad de agua
+const (
+	WaterNeedsLow    = "baja"  // Resistente a la sequía
+	WaterNeedsMedium = "media" // Riego moderado
+	WaterNeedsHigh   = "alta"  // Suelo siempre húmedo
+)
+
+// Tolerancia a heladas
+const (
+	FrostSensitive = "sensible"        // No tolera heladas
+	FrostSemiHardy = "semi_resistente" // Tolera heladas leves
+	FrostHardy     = "resistente"      // Tolera heladas fuertes
+)
+
+// Rangos válidos de los rasgos numéricos de una especie
+const (
+	MaxMatureHeightM       = 150.0  // Altura adulta máxima (m)
+	MaxCanopyDiameterM     = 60.0   // Diámetro de copa máximo (m)
+	MaxSpacingM            = 50.0   // Espaciamiento máximo recomendado (m)
+	MaxLifespanYears       = 5000.0 // Longevidad máxima (años)
+	MaxTimeToHarvestMonths = 1200   // Tiempo máximo hasta la primera cosecha (meses)
+)
+
 // Funciones de validación para el nuevo modelo
 
 // Etapas sucesionales según Ernst Götsch
@@ -142,6 +203,41 @@ func IsValidPlantStatus(status string) bool {
 	return false
 }
 
+// AllowedPlantStatusTransitions devuelve los estados a los que se puede pasar desde from
+func AllowedPlantStatusTransitions(from string) []string {
+	return plantStatusTransitions[from]
+}
+
+// IsValidInitialPlantStatus indica si una instancia nueva puede registrarse con status
+func IsValidInitialPlantStatus(status string) bool {
+	for _, v := range initialPlantStatuses {
+		if v == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPlantedStatus indica si status implica que la planta ya fue plantada
+func IsPlantedStatus(status string) bool {
+	for _, v := range plantedStatuses {
+		if v == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionPlantStatus indica si el cambio de estado from → to es legal
+func CanTransitionPlantStatus(from, to string) bool {
+	for _, v := range plantStatusTransitions[from] {
+		if v == to {
+			return true
+		}
+	}
+	return false
+}
+
 // Mantener funciones de validación existentes para compatibilidad
 // (las funciones IsValidStratum, IsValidFunction, etc. se mantienen igual)
 func IsValidStratum(stratum string) bool {
@@ -185,18 +281,36 @@ func IsValidPlantingMode(mode string) bool {
 	return false
 }
 
+// IsValidStatus acepta tanto el estado canónico como su alias en español
 func IsValidStatus(status string) bool {
-	validStatuses := []string{
-		StatusPlanned, StatusGerminating, StatusSeedling,
-		StatusPlanted, StatusEstablished, StatusProductive,
-		StatusDormant, StatusDead,
+	_, ok := NormalizePlantStatus(status)
+	return ok
+}
+
+// plantStatusAliases traduce el vocabulario en español al estado canónico.
+// "plantula" no tiene equivalente propio: una plántula ya germinó.
+var plantStatusAliases = map[string]string{
+	StatusPlanned:     PlantStatusPlanned,
+	StatusGerminating: PlantStatusGerminated,
+	StatusSeedling:    PlantStatusGerminated,
+	StatusPlanted:     PlantStatusPlanted,
+	StatusEstablished: PlantStatusEstablished,
+	StatusProductive:  PlantStatusProductive,
+	StatusDormant:     PlantStatusDormant,
+	StatusDead:        PlantStatusDead,
+}
+
+// NormalizePlantStatus devuelve el estado canónico (en inglés) para un valor
+// recibido en cualquiera de los dos vocabularios, sin distinguir mayúsculas
+func NormalizePlantStatus(status string) (string, bool) {
+	value := strings.ToLower(strings.TrimSpace(status))
+	if IsValidPlantStatus(value) {
+		return value, true
 	}
-	for _, v := range validStatuses {
-		if v == status {
-			return true
-		}
+	if canonical, ok := plantStatusAliases[value]; ok {
+		return canonical, true
 	}
-	return false
+	return "", false
 }
 
 func IsValidSoilType(soilType string) bool {
@@ -211,3 +325,39 @@ func IsValidSoilType(soilType string) bool {
 	}
 	return false
 }
+
+func IsValidLightRequirement(light string) bool {
+	for _, v := range []string{LightFullSun, LightPartialShade, LightShade} {
+		if v == light {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidRootDepth(depth string) bool {
+	for _, v := range []string{RootDepthShallow, RootDepthMedium, RootDepthDeep} {
+		if v == depth {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidWaterNeeds(needs string) bool {
+	for _, v := range []string{WaterNeedsLow, WaterNeedsMedium, WaterNeedsHigh} {
+		if v == needs {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidFrostTolerance(tolerance string) bool {
+	for _, v := range []string{FrostSensitive, FrostSemiHardy, FrostHardy} {
+		if v == tolerance {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// Radio ecuatorial WGS84 en metros, usado para el cálculo de áreas geodésicas
+const earthRadiusM = 6378137.0
+
+// geoJSON cubre las formas de GeoJSON que aceptamos para la geometría de una parcela:
+// un Polygon/MultiPolygon directo o envuelto en un Feature
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSON        `json:"geometry"`
+}
+
+// PolygonAreaM2 calcula el área en metros cuadrados de un polígono GeoJSON
+// (coordenadas [longitud, latitud] en WGS84). Los anillos interiores se restan.
+func PolygonAreaM2(geometry string) (float64, error) {
+	var g geoJSON
+	if err := json.Unmarshal([]byte(geometry), &g); err != nil {
+		return 0, errors.New("la geometría no es un GeoJSON válido")
+	}
+
+	if g.Type == "Feature" {
+		if g.Geometry == nil {
+			return 0, errors.New("el Feature GeoJSON no tiene geometría")
+		}
+		g = *g.Geometry
+	}
+
+	switch g.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return 0, errors.New("coordenadas de polígono inválidas")
+		}
+		return polygonArea(rings)
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &polygons); err != nil {
+			return 0, errors.New("coordenadas de multipolígono inválidas")
+		}
+		total := 0.0
+		for _, rings := range polygons {
+			area, err := polygonArea(rings)
+			if err != nil {
+				return 0, err
+			}
+			total += area
+		}
+		return total, nil
+	default:
+		return 0, errors.New("la geometría debe ser un Polygon o MultiPolygon")
+	}
+}
+
+// polygonArea calcula el área de un polígono: anillo exterior menos huecos
+func polygonArea(rings [][][]float64) (float64, error) {
+	if len(rings) == 0 {
+		return 0, errors.New("el polígono no tiene anillos")
+	}
+
+	area := 0.0
+	for i, ring := range rings {
+		if len(ring) < 4 {
+			return 0, errors.New("cada anillo del polígono requiere al menos 4 posiciones")
+		}
+		for _, position := range ring {
+			if len(position) < 2 {
+				return 0, errors.New("posición GeoJSON inválida")
+			}
+		}
+
+		ringArea := math.Abs(ringAreaM2(ring))
+		if i == 0 {
+			area += ringArea
+		} else {
+			area -= ringArea
+		}
+	}
+
+	return math.Max(area, 0), nil
+}
+
+// ringAreaM2 aplica la fórmula de área esférica de Chamberlain y Duquette
+// sobre un anillo cerrado de posiciones [longitud, latitud]
+func ringAreaM2(ring [][]float64) float64 {
+	total := 0.0
+	n := len(ring)
+	for i := 0; i < n-1; i++ {
+		lon1, lat1 := toRadians(ring[i][0]), toRadians(ring[i][1])
+		lon2, lat2 := toRadians(ring[i+1][0]), toRadians(ring[i+1][1])
+		total += (lon2 - lon1) * (2 + math.Sin(lat1) + math.Sin(lat2))
+	}
+	return total * earthRadiusM * earthRadiusM / 2
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
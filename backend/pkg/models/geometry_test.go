@@ -0,0 +1,109 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+// Un cuadrado de 0.001° en el ecuador mide unos 111.32 m de lado
+const (
+	squareGeoJSON = `{"type":"Polygon","coordinates":[[[0,0],[0.001,0],[0.001,0.001],[0,0.001],[0,0]]]}`
+	squareAreaM2  = 12392.0
+)
+
+func TestPolygonAreaM2(t *testing.T) {
+	cases := []struct {
+		name     string
+		geometry string
+		want     float64
+	}{
+		{"polígono", squareGeoJSON, squareAreaM2},
+		{"sentido horario", `{"type":"Polygon","coordinates":[[[0,0],[0,0.001],[0.001,0.001],[0.001,0],[0,0]]]}`, squareAreaM2},
+		{"feature", `{"type":"Feature","geometry":` + squareGeoJSON + `}`, squareAreaM2},
+		{"con hueco", `{"type":"Polygon","coordinates":[
+			[[0,0],[0.001,0],[0.001,0.001],[0,0.001],[0,0]],
+			[[0.00025,0.00025],[0.00075,0.00025],[0.00075,0.00075],[0.00025,0.00075],[0.00025,0.00025]]]}`, squareAreaM2 * 0.75},
+		{"multipolígono", `{"type":"MultiPolygon","coordinates":[
+			[[[0,0],[0.001,0],[0.001,0.001],[0,0.001],[0,0]]],
+			[[[1,0],[1.001,0],[1.001,0.001],[1,0.001],[1,0]]]]}`, squareAreaM2 * 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PolygonAreaM2(tc.geometry)
+			if err != nil {
+				t.Fatalf("PolygonAreaM2: %v", err)
+			}
+			if math.Abs(got-tc.want)/tc.want > 0.005 {
+				t.Errorf("área = %.1f m², se esperaba ~%.1f m²", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolygonAreaM2Invalid(t *testing.T) {
+	cases := map[string]string{
+		"json inválido":       `{"type":`,
+		"tipo no soportado":   `{"type":"Point","coordinates":[0,0]}`,
+		"feature sin geom":    `{"type":"Feature"}`,
+		"sin anillos":         `{"type":"Polygon","coordinates":[]}`,
+		"anillo corto":        `{"type":"Polygon","coordinates":[[[0,0],[1,0],[0,0]]]}`,
+		"posición incompleta": `{"type":"Polygon","coordinates":[[[0,0],[1],[1,1],[0,0]]]}`,
+		"coordenadas texto":   `{"type":"Polygon","coordinates":"x"}`,
+	}
+
+	for name, geometry := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := PolygonAreaM2(geometry); err == nil {
+				t.Errorf("se esperaba error para %s", geometry)
+			}
+		})
+	}
+}
+
+func TestPlotCalculateArea(t *testing.T) {
+	cases := []struct {
+		name string
+		plot Plot
+		want float64
+	}{
+		{"línea", Plot{PlotType: PlotTypeLine, LengthM: 10, WidthM: 2}, 20},
+		{"isla", Plot{PlotType: PlotTypeIsland, DiameterM: 4}, math.Pi * 4},
+		{"gremio por radio", Plot{PlotType: PlotTypeGuild, RadiusM: 1}, math.Pi},
+		{"gremio por geometría", Plot{PlotType: PlotTypeGuild, RadiusM: 1, Geometry: squareGeoJSON}, squareAreaM2},
+		{"tipo desconocido", Plot{PlotType: "otro"}, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.plot.CalculateArea()
+			if math.Abs(got-tc.want) > math.Max(1e-9, tc.want*0.005) {
+				t.Errorf("CalculateArea = %.2f, se esperaba %.2f", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlotValidateGeometry(t *testing.T) {
+	cases := []struct {
+		name    string
+		plot    Plot
+		wantErr bool
+	}{
+		{"gremio con geometría", Plot{PlantationID: 1, PlotType: PlotTypeGuild, Geometry: squareGeoJSON}, false},
+		{"gremio con radio", Plot{PlantationID: 1, PlotType: PlotTypeGuild, RadiusM: 2}, false},
+		{"gremio sin geometría ni radio", Plot{PlantationID: 1, PlotType: PlotTypeGuild}, true},
+		{"gremio con geometría inválida", Plot{PlantationID: 1, PlotType: PlotTypeGuild, Geometry: `{"type":"Point"}`}, true},
+		{"línea sin ancho", Plot{PlantationID: 1, PlotType: PlotTypeLine, LengthM: 3}, true},
+		{"isla sin diámetro", Plot{PlantationID: 1, PlotType: PlotTypeIsland}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.plot.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Proveedores de integraciones externas con credenciales en el servidor
+const (
+	IntegrationPermapeople = "permapeople"
+)
+
+// IsValidIntegrationProvider indica si el proveedor es una integración conocida
+func IsValidIntegrationProvider(provider string) bool {
+	return provider == IntegrationPermapeople
+}
+
+// IntegrationCredential guarda las credenciales de una API externa. El secreto se
+// guarda cifrado (ver internal/vault) y nunca se serializa en las respuestas.
+type IntegrationCredential struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	Provider        string    `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex"`
+	KeyID           string    `json:"-" gorm:"type:varchar(255);not null"`
+	SecretEncrypted string    `json:"-" gorm:"type:text;not null"`
+	UpdatedBy       string    `json:"updated_by" gorm:"type:varchar(100)"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName define el nombre de la tabla
+func (IntegrationCredential) TableName() string {
+	return "integration_credentials"
+}
+
+// RotateCredentialRequest estructura para reemplazar las credenciales de una integración
+type RotateCredentialRequest struct {
+	KeyID     string `json:"key_id" binding:"required"`
+	KeySecret string `json:"key_secret" binding:"required"`
+}
+
+// String evita que el secreto aparezca si la solicitud se imprime en un log
+func (r RotateCredentialRequest) String() string {
+	return fmt.Sprintf("{key_id:%s key_secret:[REDACTED]}", r.KeyID)
+}
+
+// IntegrationCredentialInfo describe las credenciales configuradas sin exponer el secreto
+type IntegrationCredentialInfo struct {
+	Provider   string     `json:"provider"`
+	Configured bool       `json:"configured"`
+	Source     string     `json:"source"`           // "vault", "env" o "" si no hay credenciales
+	KeyID      string     `json:"key_id,omitempty"` // Enmascarado
+	UpdatedBy  string     `json:"updated_by,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
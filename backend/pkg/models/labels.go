@@ -0,0 +1,346 @@
+package models
+
+// Idiomas soportados para las etiquetas de las constantes
+const (
+	LangES = "es"
+	LangEN = "en"
+)
+
+// Grupos de constantes expuestos por /constants
+const (
+	ConstantGroupStrata           = "estratos"
+	ConstantGroupSuccessionStages = "etapas_sucesionales"
+	ConstantGroupFunctions        = "funciones"
+	ConstantGroupPlantingModes    = "modalidades_plantacion"
+	ConstantGroupStatuses         = "estados"
+	ConstantGroupSoilTypes        = "tipos_suelo"
+	ConstantGroupPlotTypes        = "tipo_de_parcela"
+	ConstantGroupPlantRoles       = "roles"
+	ConstantGroupLight            = "requerimientos_luz"
+	ConstantGroupRootDepths       = "profundidades_raiz"
+	ConstantGroupWaterNeeds       = "necesidades_agua"
+	ConstantGroupFrostTolerance   = "tolerancias_helada"
+	ConstantGroupSiteRoles        = "roles_sitio"
+)
+
+// ConstantOption es un valor de constante con su etiqueta localizada
+type ConstantOption struct {
+	Value       string `json:"value"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+type localizedText struct {
+	Label       string
+	Description string
+}
+
+// IsValidLang indica si hay etiquetas para el idioma pedido
+func IsValidLang(lang string) bool {
+	return lang == LangES || lang == LangEN
+}
+
+// LocalizedOptions devuelve los valores de un grupo con etiqueta y descripción
+// en el idioma pedido. Si falta una traducción se usa el propio valor.
+func LocalizedOptions(group string, values []string, lang string) []ConstantOption {
+	options := make([]ConstantOption, 0, len(values))
+	for _, value := range values {
+		option := ConstantOption{Value: value, Label: value}
+		if text, ok := constantLabels[group][value][lang]; ok {
+			option.Label = text.Label
+			option.Description = text.Description
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// constantLabels: grupo → valor → idioma → texto
+var constantLabels = map[string]map[string]map[string]localizedText{
+	ConstantGroupStrata: {
+		StratumEmergent: {
+			LangES: {"Emergente", "Árboles de gran porte (>25m)"},
+			LangEN: {"Emergent", "Very tall trees (>25m)"},
+		},
+		StratumHigh: {
+			LangES: {"Alto", "Árboles medianos (15-25m)"},
+			LangEN: {"High", "Medium-sized trees (15-25m)"},
+		},
+		StratumMedium: {
+			LangES: {"Medio", "Árboles pequeños y arbustos (5-15m)"},
+			LangEN: {"Medium", "Small trees and shrubs (5-15m)"},
+		},
+		StratumLow: {
+			LangES: {"Bajo", "Arbustos y herbáceas (1-5m)"},
+			LangEN: {"Low", "Shrubs and herbaceous plants (1-5m)"},
+		},
+		StratumGround: {
+			LangES: {"Rastrero", "Cobertura del suelo (<1m)"},
+			LangEN: {"Ground cover", "Ground-covering plants (<1m)"},
+		},
+		StratumClimber: {
+			LangES: {"Trepador", "Plantas trepadoras"},
+			LangEN: {"Climber", "Climbing plants"},
+		},
+		StratumRoot: {
+			LangES: {"Raíz", "Sistema radicular y tubérculos"},
+			LangEN: {"Root", "Root systems and tubers"},
+		},
+	},
+	ConstantGroupSuccessionStages: {
+		SuccessionPlacenta: {
+			LangES: {"Placenta", "Preparación del suelo"},
+			LangEN: {"Placenta", "Soil preparation"},
+		},
+		SuccessionPioneer: {
+			LangES: {"Pionera", "Colonización inicial"},
+			LangEN: {"Pioneer", "Initial colonization"},
+		},
+		SuccessionSecondary: {
+			LangES: {"Secundaria", "Consolidación"},
+			LangEN: {"Secondary", "Consolidation"},
+		},
+		SuccessionClimax: {
+			LangES: {"Clímax", "Clímax y madurez"},
+			LangEN: {"Climax", "Climax and maturity"},
+		},
+	},
+	ConstantGroupFunctions: {
+		FunctionNitrogenFixer: {
+			LangES: {"Fijador de nitrógeno", "Leguminosas"},
+			LangEN: {"Nitrogen fixer", "Legumes"},
+		},
+		FunctionDynamicAccumulator: {
+			LangES: {"Acumulador dinámico", "Acumulan minerales"},
+			LangEN: {"Dynamic accumulator", "Accumulates minerals"},
+		},
+		FunctionGroundCover: {
+			LangES: {"Cobertura de suelo", "Protección del suelo"},
+			LangEN: {"Ground cover", "Soil protection"},
+		},
+		FunctionWindbreak: {
+			LangES: {"Cortaviento", "Protección contra viento"},
+			LangEN: {"Windbreak", "Wind protection"},
+		},
+		FunctionPollinator: {
+			LangES: {"Polinizador", "Atrae polinizadores"},
+			LangEN: {"Pollinator", "Attracts pollinators"},
+		},
+		FunctionPestControl: {
+			LangES: {"Control de plagas", "Control biológico"},
+			LangEN: {"Pest control", "Biological control"},
+		},
+		FunctionSoilAeration: {
+			LangES: {"Aireación del suelo", "Mejora estructura del suelo"},
+			LangEN: {"Soil aeration", "Improves soil structure"},
+		},
+		FunctionWaterRegulation: {
+			LangES: {"Regulación del agua", "Manejo hídrico"},
+			LangEN: {"Water regulation", "Water management"},
+		},
+		FunctionBiomassProduction: {
+			LangES: {"Producción de biomasa", "Generación de materia orgánica"},
+			LangEN: {"Biomass production", "Generates organic matter"},
+		},
+		FunctionFood: {
+			LangES: {"Alimentario", "Producción de alimentos"},
+			LangEN: {"Food", "Food production"},
+		},
+		FunctionMedicinal: {
+			LangES: {"Medicinal", "Propiedades medicinales"},
+			LangEN: {"Medicinal", "Medicinal properties"},
+		},
+		FunctionTimber: {
+			LangES: {"Maderable", "Producción de madera"},
+			LangEN: {"Timber", "Timber production"},
+		},
+		FunctionFiber: {
+			LangES: {"Fibra", "Producción de fibras"},
+			LangEN: {"Fiber", "Fiber production"},
+		},
+		FunctionOrnamental: {
+			LangES: {"Ornamental", "Valor estético"},
+			LangEN: {"Ornamental", "Aesthetic value"},
+		},
+	},
+	ConstantGroupPlantingModes: {
+		PlantingModeSeed: {
+			LangES: {"Semilla", "Siembra directa"},
+			LangEN: {"Seed", "Direct sowing"},
+		},
+		PlantingModeCutting: {
+			LangES: {"Esqueje", "Propagación vegetativa"},
+			LangEN: {"Cutting", "Vegetative propagation"},
+		},
+		PlantingModeStake: {
+			LangES: {"Estaca", "Estacas leñosas"},
+			LangEN: {"Stake", "Woody stakes"},
+		},
+		PlantingModeSeedling: {
+			LangES: {"Plantín", "Plantines o mudas"},
+			LangEN: {"Seedling", "Nursery seedlings"},
+		},
+		PlantingModeTree: {
+			LangES: {"Árbol", "Árboles desarrollados"},
+			LangEN: {"Tree", "Grown trees"},
+		},
+	},
+	ConstantGroupStatuses: {
+		PlantStatusPlanned: {
+			LangES: {"Planeada", "En planificación"},
+			LangEN: {"Planned", "Being planned"},
+		},
+		PlantStatusGerminated: {
+			LangES: {"Germinada", "Germinada o en estado de plántula"},
+			LangEN: {"Germinated", "Germinated or at seedling stage"},
+		},
+		PlantStatusPlanted: {
+			LangES: {"Plantada", "Plantada en campo"},
+			LangEN: {"Planted", "Planted in the field"},
+		},
+		PlantStatusEstablished: {
+			LangES: {"Establecida", "Establecida y creciendo"},
+			LangEN: {"Established", "Established and growing"},
+		},
+		PlantStatusProductive: {
+			LangES: {"Productiva", "En etapa productiva"},
+			LangEN: {"Productive", "In productive stage"},
+		},
+		PlantStatusDormant: {
+			LangES: {"Dormante", "En dormancia"},
+			LangEN: {"Dormant", "Dormant"},
+		},
+		PlantStatusDead: {
+			LangES: {"Muerta", "No viable"},
+			LangEN: {"Dead", "No longer viable"},
+		},
+	},
+	ConstantGroupSoilTypes: {
+		SoilTypeArgiloso: {
+			LangES: {"Arcilloso", "Suelo arcilloso"},
+			LangEN: {"Clay", "Clay soil"},
+		},
+		SoilTypeArenoso: {
+			LangES: {"Arenoso", "Suelo arenoso"},
+			LangEN: {"Sandy", "Sandy soil"},
+		},
+		SoilTypeFranco: {
+			LangES: {"Franco", "Suelo franco"},
+			LangEN: {"Loam", "Loamy soil"},
+		},
+		SoilTypeHumifero: {
+			LangES: {"Humífero", "Rico en humus"},
+			LangEN: {"Humus-rich", "Rich in humus"},
+		},
+		SoilTypePedregoso: {
+			LangES: {"Pedregoso", "Suelo pedregoso"},
+			LangEN: {"Stony", "Stony soil"},
+		},
+		SoilTypeAnegadizo: {
+			LangES: {"Anegadizo", "Propenso a encharcamiento"},
+			LangEN: {"Waterlogged", "Prone to waterlogging"},
+		},
+	},
+	ConstantGroupPlotTypes: {
+		PlotTypeLine: {
+			LangES: {"Línea", "Líneas de plantación"},
+			LangEN: {"Line", "Planting rows"},
+		},
+		PlotTypeIsland: {
+			LangES: {"Isla", "Islas circulares"},
+			LangEN: {"Island", "Circular islands"},
+		},
+		PlotTypeGuild: {
+			LangES: {"Gremio", "Gremios de plantas"},
+			LangEN: {"Guild", "Plant guilds"},
+		},
+	},
+	ConstantGroupPlantRoles: {
+		PlantRoleObjetivo: {
+			LangES: {"Objetivo", "Producción principal"},
+			LangEN: {"Target", "Main production"},
+		},
+		PlantRoleServicio: {
+			LangES: {"Servicio", "Apoyo ecológico"},
+			LangEN: {"Service", "Ecological support"},
+		},
+		PlantRoleAcompañante: {
+			LangES: {"Acompañante", "Planta acompañante"},
+			LangEN: {"Companion", "Companion plant"},
+		},
+	},
+	ConstantGroupLight: {
+		LightFullSun: {
+			LangES: {"Pleno sol", "Sol directo todo el día"},
+			LangEN: {"Full sun", "Direct sun all day"},
+		},
+		LightPartialShade: {
+			LangES: {"Media sombra", "Sol filtrado o parte del día"},
+			LangEN: {"Partial shade", "Filtered sun or part of the day"},
+		},
+		LightShade: {
+			LangES: {"Sombra", "Tolera sombra densa"},
+			LangEN: {"Shade", "Tolerates dense shade"},
+		},
+	},
+	ConstantGroupRootDepths: {
+		RootDepthShallow: {
+			LangES: {"Superficial", "Raíces en los primeros 30 cm"},
+			LangEN: {"Shallow", "Roots in the top 30 cm"},
+		},
+		RootDepthMedium: {
+			LangES: {"Media", "Raíces entre 30 cm y 1 m"},
+			LangEN: {"Medium", "Roots between 30 cm and 1 m"},
+		},
+		RootDepthDeep: {
+			LangES: {"Profunda", "Raíces de más de 1 m, pivotantes"},
+			LangEN: {"Deep", "Taproots deeper than 1 m"},
+		},
+	},
+	ConstantGroupWaterNeeds: {
+		WaterNeedsLow: {
+			LangES: {"Baja", "Resistente a la sequía"},
+			LangEN: {"Low", "Drought tolerant"},
+		},
+		WaterNeedsMedium: {
+			LangES: {"Media", "Riego moderado"},
+			LangEN: {"Medium", "Moderate watering"},
+		},
+		WaterNeedsHigh: {
+			LangES: {"Alta", "Suelo siempre húmedo"},
+			LangEN: {"High", "Constantly moist soil"},
+		},
+	},
+	ConstantGroupFrostTolerance: {
+		FrostSensitive: {
+			LangES: {"Sensible", "No tolera heladas"},
+			LangEN: {"Sensitive", "Does not tolerate frost"},
+		},
+		FrostSemiHardy: {
+			LangES: {"Semirresistente", "Tolera heladas leves"},
+			LangEN: {"Semi-hardy", "Tolerates light frost"},
+		},
+		FrostHardy: {
+			LangES: {"Resistente", "Tolera heladas fuertes"},
+			LangEN: {"Hardy", "Tolerates hard frost"},
+		},
+	},
+	ConstantGroupSiteRoles: {
+		SiteRoleOwner: {
+			LangES: {"Propietario", "Administra la finca y sus miembros"},
+			LangEN: {"Owner", "Manages the farm and its members"},
+		},
+		SiteRoleDesigner: {
+			LangES: {"Diseñador", "Diseña plantaciones, parcelas y plantillas"},
+			LangEN: {"Designer", "Designs plantations, plots and templates"},
+		},
+		SiteRoleFieldWorker: {
+			LangES: {"Trabajador de campo", "Registra el trabajo sobre las plantas"},
+			LangEN: {"Field worker", "Records work on the plants"},
+		},
+		SiteRoleViewer: {
+			LangES: {"Observador", "Solo lectura"},
+			LangEN: {"Viewer", "Read only"},
+		},
+	},
+}
@@ -1,25 +1,29 @@
 package models
 
 import (
-	"errors"
+	"encoding/json"
+	"fmt"
+	"math"
 	"strings"
 	"time"
 
+	"github.com/deibys/sintronia/pkg/apperror"
 	"gorm.io/gorm"
 )
 
 // Site representa un sitio o terreno principal
 type Site struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"-:migration"`
-	AreaM2    float64        `json:"area_m2" gorm:"type:decimal(12,2)"` // Área total calculada
-	LengthM   float64        `json:"length_m" gorm:"type:decimal(10,2)"`
-	WidthM    float64        `json:"width_m" gorm:"type:decimal(10,2)"`
-	Notes     string         `json:"notes" gorm:"type:text"`
-	Climate   string         `json:"climate" gorm:"type:text"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID uint           `json:"organization_id" gorm:"index"` // Tenant dueño del sitio
+	Name           string         `json:"name" gorm:"-:migration"`
+	AreaM2         float64        `json:"area_m2" gorm:"type:decimal(12,2)"` // Área total calculada
+	LengthM        float64        `json:"length_m" gorm:"type:decimal(10,2)"`
+	WidthM         float64        `json:"width_m" gorm:"type:decimal(10,2)"`
+	Notes          string         `json:"notes" gorm:"type:text"`
+	Climate        string         `json:"climate" gorm:"type:text"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
 
 	// Relaciones
 	Plantations []Plantation `json:"plantations,omitempty" gorm:"foreignKey:SiteID"`
@@ -28,15 +32,19 @@ type Site struct {
 // Validate valida los datos de un sitio
 func (s *Site) Validate() error {
 	if strings.TrimSpace(s.Name) == "" {
-		return errors.New("el nombre del sitio es requerido")
+		return apperror.Invalid("name", apperror.FieldRequired, "el nombre del sitio es requerido")
 	}
 
 	if s.AreaM2 < 0 {
-		return errors.New("el área no puede ser negativa")
+		return apperror.Invalid("area_m2", apperror.FieldOutOfRange, "el área no puede ser negativa")
 	}
 
-	if s.LengthM < 0 || s.WidthM < 0 {
-		return errors.New("las dimensiones no pueden ser negativas")
+	if s.LengthM < 0 {
+		return apperror.Invalid("length_m", apperror.FieldOutOfRange, "las dimensiones no pueden ser negativas")
+	}
+
+	if s.WidthM < 0 {
+		return apperror.Invalid("width_m", apperror.FieldOutOfRange, "las dimensiones no pueden ser negativas")
 	}
 
 	return nil
@@ -73,56 +81,125 @@ type Plantation struct {
 // Validate valida los datos de una plantación
 func (p *Plantation) Validate() error {
 	if strings.TrimSpace(p.Name) == "" {
-		return errors.New("el nombre de la plantación es requerido")
+		return apperror.Invalid("name", apperror.FieldRequired, "el nombre de la plantación es requerido")
 	}
 
 	if p.SiteID <= 0 {
-		return errors.New("el sitio es requerido")
+		return apperror.Invalid("site_id", apperror.FieldRequired, "el sitio es requerido")
 	}
 
 	if p.AreaM2 < 0 {
-		return errors.New("el área no puede ser negativa")
+		return apperror.Invalid("area_m2", apperror.FieldOutOfRange, "el área no puede ser negativa")
 	}
 
 	return nil
 }
 
-// PlantSpecies representa una especie de planta en el catálogo
+// PlantSpecies representa una especie de planta en el catálogo. Estrato, función
+// y etapa sucesional vacíos se guardan como NULL (default:null) porque los CHECK
+// de la migración 002 no aceptan la cadena vacía.
 type PlantSpecies struct {
 	ID              uint           `json:"id" gorm:"primaryKey"`
 	CommonName      string         `json:"common_name" gorm:"not null;index;-:migration"`
 	ScientificName  string         `json:"scientific_name" gorm:"index;-:migration"`
-	Stratum         string         `json:"stratum" gorm:"type:varchar(50);index;-:migration"`             // Ej: "bajo", "medio", "alto"
-	FunctionEcol    string         `json:"function_ecol" gorm:"type:varchar(100);index;-:migration"`      // "objetivo" o "servicio"
-	SuccessionStage string         `json:"succession_stage" gorm:"type:varchar(50);index;-:migration"`    // Ej: "pionera", "secundaria", "climax"
-	ExternalRef     string         `json:"external_ref" gorm:"type:varchar(100);uniqueIndex;-:migration"` // Referencia a la API externa
+	Stratum         string         `json:"stratum" gorm:"type:varchar(50);default:null;index;-:migration"`          // Ej: "bajo", "medio", "alto"
+	FunctionEcol    string         `json:"function_ecol" gorm:"type:varchar(100);default:null;index;-:migration"`   // Función principal, copia de species_functions por compatibilidad
+	SuccessionStage string         `json:"succession_stage" gorm:"type:varchar(50);default:null;index;-:migration"` // Ej: "pionera", "secundaria", "climax"
+	ExternalRef     string         `json:"external_ref" gorm:"type:varchar(100);index;-:migration"`                 // Referencia a la API externa, única por organización
 	Notes           string         `json:"notes" gorm:"type:text"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
 	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
 
+	// Catálogo: sin organización la especie es global; si no, es privada de la
+	// organización y puede reemplazar (override) a una especie global
+	OrganizationID     *uint `json:"organization_id" gorm:"index"`
+	OverridesSpeciesID *uint `json:"overrides_species_id,omitempty"`
+
+	// Rasgos para diseño y análisis (opcionales)
+	MatureHeightM       *float64 `json:"mature_height_m" gorm:"type:decimal(6,2)"`        // Altura adulta
+	CanopyDiameterM     *float64 `json:"canopy_diameter_m" gorm:"type:decimal(6,2)"`      // Diámetro de copa adulta
+	SpacingM            *float64 `json:"spacing_m" gorm:"type:decimal(6,2)"`              // Espaciamiento recomendado entre plantas
+	LifespanYears       *float64 `json:"lifespan_years" gorm:"type:decimal(8,2)"`         // Longevidad
+	TimeToHarvestMonths *int     `json:"time_to_harvest_months"`                          // Tiempo hasta la primera cosecha
+	LightRequirement    string   `json:"light_requirement" gorm:"type:varchar(20);index"` // "pleno_sol", "media_sombra", "sombra"
+	RootDepth           string   `json:"root_depth" gorm:"type:varchar(20)"`              // "superficial", "media", "profunda"
+	WaterNeeds          string   `json:"water_needs" gorm:"type:varchar(20);index"`       // "baja", "media", "alta"
+	FrostTolerance      string   `json:"frost_tolerance" gorm:"type:varchar(20);index"`   // "sensible", "semi_resistente", "resistente"
+
 	// Relaciones
-	PlantInstances []PlantInstance `json:"plant_instances,omitempty" gorm:"foreignKey:SpeciesID"`
+	Functions      []SpeciesFunction `json:"functions,omitempty" gorm:"foreignKey:SpeciesID"`
+	PlantInstances []PlantInstance   `json:"plant_instances,omitempty" gorm:"foreignKey:SpeciesID"`
 }
 
 // Validate valida los datos de una especie de planta
 func (ps *PlantSpecies) Validate() error {
 	if strings.TrimSpace(ps.CommonName) == "" {
-		return errors.New("el nombre común de la especie es requerido")
+		return apperror.Invalid("common_name", apperror.FieldRequired, "el nombre común de la especie es requerido")
 	}
 
 	if ps.Stratum != "" && !IsValidStratum(ps.Stratum) {
-		return errors.New("estrato inválido")
+		return apperror.Invalid("stratum", apperror.FieldInvalid, "estrato inválido")
 	}
 
-	if ps.FunctionEcol != "" && !IsValidFunction(ps.FunctionEcol) {
-		return errors.New("función ecológica inválida")
+	if err := ps.validateFunctions(); err != nil {
+		return err
 	}
 
 	if ps.SuccessionStage != "" && !IsValidSuccessionStage(ps.SuccessionStage) {
-		return errors.New("etapa sucesional inválida")
+		return apperror.Invalid("succession_stage", apperror.FieldInvalid, "etapa sucesional inválida")
+	}
+
+	return ps.validateTraits()
+}
+
+// validateTraits valida los rangos de los rasgos opcionales de la especie
+func (ps *PlantSpecies) validateTraits() error {
+	if err := validateRange("mature_height_m", ps.MatureHeightM, MaxMatureHeightM, "la altura adulta"); err != nil {
+		return err
+	}
+
+	if err := validateRange("canopy_diameter_m", ps.CanopyDiameterM, MaxCanopyDiameterM, "el diámetro de copa"); err != nil {
+		return err
+	}
+
+	if err := validateRange("spacing_m", ps.SpacingM, MaxSpacingM, "el espaciamiento"); err != nil {
+		return err
+	}
+
+	if err := validateRange("lifespan_years", ps.LifespanYears, MaxLifespanYears, "la longevidad"); err != nil {
+		return err
+	}
+
+	if ps.TimeToHarvestMonths != nil && (*ps.TimeToHarvestMonths < 0 || *ps.TimeToHarvestMonths > MaxTimeToHarvestMonths) {
+		return apperror.Invalid("time_to_harvest_months", apperror.FieldOutOfRange,
+			fmt.Sprintf("el tiempo hasta la primera cosecha debe estar entre 0 y %d meses", MaxTimeToHarvestMonths))
+	}
+
+	if ps.LightRequirement != "" && !IsValidLightRequirement(ps.LightRequirement) {
+		return apperror.Invalid("light_requirement", apperror.FieldInvalid, "requerimiento de luz inválido")
+	}
+
+	if ps.RootDepth != "" && !IsValidRootDepth(ps.RootDepth) {
+		return apperror.Invalid("root_depth", apperror.FieldInvalid, "profundidad de raíz inválida")
+	}
+
+	if ps.WaterNeeds != "" && !IsValidWaterNeeds(ps.WaterNeeds) {
+		return apperror.Invalid("water_needs", apperror.FieldInvalid, "necesidad de agua inválida")
 	}
 
+	if ps.FrostTolerance != "" && !IsValidFrostTolerance(ps.FrostTolerance) {
+		return apperror.Invalid("frost_tolerance", apperror.FieldInvalid, "tolerancia a heladas inválida")
+	}
+
+	return nil
+}
+
+// validateRange verifica que un valor opcional esté en el rango (0, max]
+func validateRange(field string, value *float64, max float64, name string) error {
+	if value != nil && (*value <= 0 || *value > max) {
+		return apperror.Invalid(field, apperror.FieldOutOfRange, fmt.Sprintf("%s debe ser mayor a cero y como máximo %g", name, max))
+	}
 	return nil
 }
 
@@ -134,7 +211,8 @@ type Plot struct {
 	LengthM      float64        `json:"length_m" gorm:"type:decimal(10,2)"`                           // Solo para líneas
 	WidthM       float64        `json:"width_m" gorm:"type:decimal(10,2)"`                            // Solo para líneas
 	DiameterM    float64        `json:"diameter_m" gorm:"type:decimal(10,2)"`                         // Solo para islas
-	Geometry     string         `json:"geometry" gorm:"type:text"`                                    // GeoJSON opcional
+	RadiusM      float64        `json:"radius_m" gorm:"type:decimal(10,2)"`                           // Gremios sin geometría
+	Geometry     string         `json:"geometry" gorm:"type:text"`                                    // GeoJSON opcional (obligatorio en gremios sin radio)
 	Notes        string         `json:"notes" gorm:"type:text"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
@@ -148,22 +226,37 @@ type Plot struct {
 // Validate valida los datos de una parcela
 func (p *Plot) Validate() error {
 	if p.PlantationID <= 0 {
-		return errors.New("la plantación es requerida")
+		return apperror.Invalid("plantation_id", apperror.FieldRequired, "la plantación es requerida")
 	}
 
 	if !IsValidPlotType(p.PlotType) {
-		return errors.New("tipo de parcela inválido")
+		return apperror.Invalid("plot_type", apperror.FieldInvalid, "tipo de parcela inválido")
 	}
 
 	// Validaciones específicas por tipo
 	switch p.PlotType {
 	case PlotTypeLine:
-		if p.LengthM <= 0 || p.WidthM <= 0 {
-			return errors.New("las líneas requieren longitud y ancho válidos")
+		if p.LengthM <= 0 {
+			return apperror.Invalid("length_m", apperror.FieldRequired, "las líneas requieren longitud y ancho válidos")
+		}
+		if p.WidthM <= 0 {
+			return apperror.Invalid("width_m", apperror.FieldRequired, "las líneas requieren longitud y ancho válidos")
 		}
 	case PlotTypeIsland:
 		if p.DiameterM <= 0 {
-			return errors.New("las islas requieren un diámetro válido")
+			return apperror.Invalid("diameter_m", apperror.FieldRequired, "las islas requieren un diámetro válido")
+		}
+	case PlotTypeGuild:
+		if strings.TrimSpace(p.Geometry) != "" {
+			area, err := PolygonAreaM2(p.Geometry)
+			if err != nil {
+				return apperror.Invalid("geometry", apperror.FieldInvalid, err.Error())
+			}
+			if area <= 0 {
+				return apperror.Invalid("geometry", apperror.FieldInvalid, "la geometría del gremio no tiene área")
+			}
+		} else if p.RadiusM <= 0 {
+			return apperror.Invalid("radius_m", apperror.FieldRequired, "los gremios requieren una geometría (polígono GeoJSON) o un radio válido")
 		}
 	}
 
@@ -177,12 +270,46 @@ func (p *Plot) CalculateArea() float64 {
 		return p.LengthM * p.WidthM
 	case PlotTypeIsland:
 		radius := p.DiameterM / 2
-		return 3.14159 * radius * radius
+		return math.Pi * radius * radius
+	case PlotTypeGuild:
+		// La geometría tiene prioridad sobre el radio explícito
+		if strings.TrimSpace(p.Geometry) != "" {
+			if area, err := PolygonAreaM2(p.Geometry); err == nil {
+				return area
+			}
+		}
+		return math.Pi * p.RadiusM * p.RadiusM
 	default:
 		return 0
 	}
 }
 
+// PlotWithMetrics es la representación de una parcela en las respuestas de la API,
+// con el área calculada y la densidad de plantas vivas
+type PlotWithMetrics struct {
+	Plot
+	AreaM2       float64 `json:"area_m2"`
+	PlantCount   int     `json:"plant_count"`
+	DensityPerM2 float64 `json:"density_per_m2"`
+}
+
+// NewPlotWithMetrics calcula el área y la densidad de una parcela a partir
+// de la cantidad total de plantas vivas que contiene
+func NewPlotWithMetrics(plot Plot, plantCount int) PlotWithMetrics {
+	area := plot.CalculateArea()
+	density := 0.0
+	if area > 0 {
+		density = float64(plantCount) / area
+	}
+
+	return PlotWithMetrics{
+		Plot:         plot,
+		AreaM2:       area,
+		PlantCount:   plantCount,
+		DensityPerM2: density,
+	}
+}
+
 // PlantInstance representa una instancia específica de plantas en una parcela
 type PlantInstance struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
@@ -199,6 +326,10 @@ type PlantInstance struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
 
+	// Último cambio de estado: quién y cuándo
+	StatusChangedAt *time.Time `json:"status_changed_at"`
+	StatusChangedBy string     `json:"status_changed_by" gorm:"type:varchar(100)"`
+
 	// Relaciones
 	Plot    Plot         `json:"plot,omitempty" gorm:"foreignKey:PlotID"`
 	Species PlantSpecies `json:"species,omitempty" gorm:"foreignKey:SpeciesID"`
@@ -207,23 +338,56 @@ type PlantInstance struct {
 // Validate valida los datos de una instancia de planta
 func (pi *PlantInstance) Validate() error {
 	if pi.PlotID <= 0 {
-		return errors.New("la parcela es requerida")
+		return apperror.Invalid("plot_id", apperror.FieldRequired, "la parcela es requerida")
 	}
 
 	if pi.SpeciesID <= 0 {
-		return errors.New("la especie es requerida")
+		return apperror.Invalid("species_id", apperror.FieldRequired, "la especie es requerida")
 	}
 
 	if pi.Quantity <= 0 {
-		return errors.New("la cantidad debe ser mayor a cero")
+		return apperror.Invalid("quantity", apperror.FieldOutOfRange, "la cantidad debe ser mayor a cero")
 	}
 
 	if pi.Role != "" && !IsValidPlantRole(pi.Role) {
-		return errors.New("rol de planta inválido")
+		return apperror.Invalid("role", apperror.FieldInvalid, "rol de planta inválido")
 	}
 
 	if !IsValidPlantStatus(pi.Status) {
-		return errors.New("estado inválido")
+		return apperror.Invalid("status", apperror.FieldInvalid, "estado inválido")
+	}
+
+	return nil
+}
+
+// ErrInvalidStatusTransition se devuelve cuando el cambio de estado no respeta
+// el ciclo de vida de la planta
+var ErrInvalidStatusTransition = apperror.Conflict("invalid_status_transition", "transición de estado no permitida")
+
+// TransitionTo cambia el estado de la instancia respetando la máquina de estados,
+// registra quién y cuándo hizo el cambio y fija PlantedAt al llegar a un estado
+// en el que la planta ya está en el campo (p. ej. dormant → established)
+func (pi *PlantInstance) TransitionTo(status, actor string, at time.Time) error {
+	if !IsValidPlantStatus(status) {
+		return apperror.Invalid("status", apperror.FieldInvalid, "estado inválido")
+	}
+
+	if !CanTransitionPlantStatus(pi.Status, status) {
+		return fmt.Errorf("%w: de %q a %q (permitidos: %s)", ErrInvalidStatusTransition,
+			pi.Status, status, strings.Join(AllowedPlantStatusTransitions(pi.Status), ", "))
+	}
+
+	if pi.StatusChangedAt != nil && at.Before(*pi.StatusChangedAt) {
+		return fmt.Errorf("%w: la fecha es anterior al último cambio de estado", ErrInvalidStatusTransition)
+	}
+
+	pi.Status = status
+	pi.StatusChangedAt = &at
+	pi.StatusChangedBy = actor
+
+	if IsPlantedStatus(status) && pi.PlantedAt == nil {
+		plantedAt := at
+		pi.PlantedAt = &plantedAt
 	}
 
 	return nil
@@ -255,11 +419,15 @@ type SuggestionTemplate struct {
 // Validate valida los datos de una plantilla de sugerencias
 func (st *SuggestionTemplate) Validate() error {
 	if strings.TrimSpace(st.Name) == "" {
-		return errors.New("el nombre de la plantilla es requerido")
+		return apperror.Invalid("name", apperror.FieldRequired, "el nombre de la plantilla es requerido")
 	}
 
 	if st.PlantationID <= 0 {
-		return errors.New("la plantación es requerida")
+		return apperror.Invalid("plantation_id", apperror.FieldRequired, "la plantación es requerida")
+	}
+
+	if _, err := ParseTemplateRules(st.Rules); err != nil {
+		return err
 	}
 
 	return nil
@@ -267,10 +435,12 @@ func (st *SuggestionTemplate) Validate() error {
 
 // Estructuras para respuestas de API (mantenemos compatibilidad)
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Message string      `json:"message,omitempty"`
+	Success bool                  `json:"success"`
+	Data    interface{}           `json:"data,omitempty"`
+	Error   string                `json:"error,omitempty"`
+	Code    string                `json:"code,omitempty"`    // Código estable del error (p. ej. "species_not_found")
+	Details []apperror.FieldError `json:"details,omitempty"` // Errores de validación por campo
+	Message string                `json:"message,omitempty"`
 }
 
 type PaginatedResponse struct {
@@ -294,6 +464,7 @@ type CreateSiteRequest struct {
 	LengthM float64 `json:"length_m"`
 	WidthM  float64 `json:"width_m"`
 	Notes   string  `json:"notes"`
+	Climate string  `json:"climate"`
 }
 
 type UpdateSiteRequest struct {
@@ -302,23 +473,43 @@ type UpdateSiteRequest struct {
 	LengthM *float64 `json:"length_m"`
 	WidthM  *float64 `json:"width_m"`
 	Notes   *string  `json:"notes"`
+	Climate *string  `json:"climate"`
 }
 
 type CreatePlantationRequest struct {
-	SiteID uint    `json:"site_id" binding:"required"`
+	SiteID uint    `json:"site_id"` // Se toma de la ruta /sites/:id/plantations
 	Name   string  `json:"name" binding:"required"`
 	AreaM2 float64 `json:"area_m2"`
 	Notes  string  `json:"notes"`
 }
 
+type UpdatePlantationRequest struct {
+	Name   *string  `json:"name"`
+	AreaM2 *float64 `json:"area_m2"`
+	Notes  *string  `json:"notes"`
+}
+
 type CreatePlantSpeciesRequest struct {
 	CommonName      string `json:"common_name" binding:"required"`
 	ScientificName  string `json:"scientific_name"`
 	Stratum         string `json:"stratum"`
-	FunctionEcol    string `json:"function_ecol"`
+	FunctionEcol    string `json:"function_ecol"` // Compatibilidad: función única
 	SuccessionStage string `json:"succession_stage"`
 	ExternalRef     string `json:"external_ref"`
 	Notes           string `json:"notes"`
+
+	Functions       []string `json:"functions"`        // Todas las funciones ecológicas
+	PrimaryFunction string   `json:"primary_function"` // Debe estar en functions; por defecto la primera
+
+	MatureHeightM       *float64 `json:"mature_height_m"`
+	CanopyDiameterM     *float64 `json:"canopy_diameter_m"`
+	SpacingM            *float64 `json:"spacing_m"`
+	LifespanYears       *float64 `json:"lifespan_years"`
+	TimeToHarvestMonths *int     `json:"time_to_harvest_months"`
+	LightRequirement    string   `json:"light_requirement"`
+	RootDepth           string   `json:"root_depth"`
+	WaterNeeds          string   `json:"water_needs"`
+	FrostTolerance      string   `json:"frost_tolerance"`
 }
 
 type UpdatePlantSpeciesRequest struct {
@@ -329,31 +520,73 @@ type UpdatePlantSpeciesRequest struct {
 	SuccessionStage *string `json:"succession_stage"`
 	ExternalRef     *string `json:"external_ref"`
 	Notes           *string `json:"notes"`
+
+	Functions       []string `json:"functions"` // Reemplaza todas las funciones
+	PrimaryFunction *string  `json:"primary_function"`
+
+	MatureHeightM       *float64 `json:"mature_height_m"`
+	CanopyDiameterM     *float64 `json:"canopy_diameter_m"`
+	SpacingM            *float64 `json:"spacing_m"`
+	LifespanYears       *float64 `json:"lifespan_years"`
+	TimeToHarvestMonths *int     `json:"time_to_harvest_months"`
+	LightRequirement    *string  `json:"light_requirement"`
+	RootDepth           *string  `json:"root_depth"`
+	WaterNeeds          *string  `json:"water_needs"`
+	FrostTolerance      *string  `json:"frost_tolerance"`
 }
 
 type CreatePlotRequest struct {
-	PlantationID uint    `json:"plantation_id" binding:"required"`
+	PlantationID uint    `json:"plantation_id"` // Se toma de la ruta /plantations/:id/plots
 	PlotType     string  `json:"plot_type" binding:"required"`
 	LengthM      float64 `json:"length_m"`
 	WidthM       float64 `json:"width_m"`
 	DiameterM    float64 `json:"diameter_m"`
+	RadiusM      float64 `json:"radius_m"`
 	Geometry     string  `json:"geometry"`
 	Notes        string  `json:"notes"`
 }
 
+type UpdatePlotRequest struct {
+	PlotType  *string  `json:"plot_type"`
+	LengthM   *float64 `json:"length_m"`
+	WidthM    *float64 `json:"width_m"`
+	DiameterM *float64 `json:"diameter_m"`
+	RadiusM   *float64 `json:"radius_m"`
+	Geometry  *string  `json:"geometry"`
+	Notes     *string  `json:"notes"`
+}
+
 type CreatePlantInstanceRequest struct {
-	PlotID    uint   `json:"plot_id" binding:"required"`
-	SpeciesID uint   `json:"species_id" binding:"required"`
-	Quantity  int    `json:"quantity" binding:"required,min=1"`
-	Role      string `json:"role"`
-	Status    string `json:"status" binding:"required"`
-	Position  string `json:"position"`
-	Notes     string `json:"notes"`
+	PlotID    uint       `json:"plot_id"` // Se toma de la ruta /plots/:id/instances
+	SpeciesID uint       `json:"species_id" binding:"required"`
+	Quantity  int        `json:"quantity" binding:"required,min=1"`
+	Role      string     `json:"role"`
+	Status    string     `json:"status"` // Por defecto "planned"
+	Position  string     `json:"position"`
+	Order     int        `json:"order"`
+	PlantedAt *time.Time `json:"planted_at"`
+	Notes     string     `json:"notes"`
+}
+
+// UpdatePlantInstanceRequest no incluye el estado: los cambios de estado
+// se hacen únicamente mediante transiciones
+type UpdatePlantInstanceRequest struct {
+	Quantity *int    `json:"quantity"`
+	Role     *string `json:"role"`
+	Position *string `json:"position"`
+	Order    *int    `json:"order"`
+	Notes    *string `json:"notes"`
+}
+
+type PlantInstanceTransitionRequest struct {
+	Status     string     `json:"status" binding:"required"`
+	OccurredAt *time.Time `json:"occurred_at"` // Por defecto, el momento de la solicitud
+	Notes      string     `json:"notes"`
 }
 
 type CreateSuggestionTemplateRequest struct {
-	PlantationID uint   `json:"plantation_id" binding:"required"`
-	Name         string `json:"name" binding:"required"`
-	Description  string `json:"description"`
-	Rules        string `json:"rules"` // JSON string
+	PlantationID uint            `json:"plantation_id"` // Se toma de la ruta
+	Name         string          `json:"name" binding:"required"`
+	Description  string          `json:"description"`
+	Rules        json.RawMessage `json:"rules"` // Objeto con densidad_maxima, estratos_requeridos, sucesion_minima
 }
@@ -0,0 +1,35 @@
+package models
+
+// Estados de ocupación de un estrato respecto a su objetivo
+const (
+	OccupancyUnder = "insuficiente" // Por debajo del objetivo
+	OccupancyOK    = "adecuada"     // Dentro de la tolerancia
+	OccupancyOver  = "excesiva"     // Por encima del objetivo
+)
+
+// StratumOccupancy compara la cobertura de copa real de un estrato con su objetivo
+type StratumOccupancy struct {
+	Stratum         string  `json:"stratum"`
+	TargetPct       float64 `json:"target_pct"`
+	ActualPct       float64 `json:"actual_pct"`
+	CanopyAreaM2    float64 `json:"canopy_area_m2"`
+	PlantCount      int     `json:"plant_count"`
+	EstimatedPlants int     `json:"estimated_plants"` // Plantas sin diámetro de copa propio (se usó el del estrato)
+	Status          string  `json:"status"`
+}
+
+// PlotOccupancy es el análisis de ocupación por estrato de una parcela
+type PlotOccupancy struct {
+	PlotID   uint               `json:"plot_id"`
+	PlotType string             `json:"plot_type"`
+	AreaM2   float64            `json:"area_m2"`
+	Strata   []StratumOccupancy `json:"strata"`
+}
+
+// PlantationOccupancy agrega la ocupación de todas las parcelas de una plantación
+type PlantationOccupancy struct {
+	PlantationID uint               `json:"plantation_id"`
+	AreaM2       float64            `json:"area_m2"` // Suma de las áreas de las parcelas
+	Strata       []StratumOccupancy `json:"strata"`
+	Plots        []PlotOccupancy    `json:"plots"`
+}
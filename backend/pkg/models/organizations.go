@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Roles de un usuario dentro de una organización
+const (
+	OrganizationRoleOwner  = "owner"  // Administra la organización y sus miembros
+	OrganizationRoleMember = "member" // Trabaja en los sitios a los que lo invitan
+)
+
+// IsValidOrganizationRole verifica si un rol de organización es válido
+func IsValidOrganizationRole(role string) bool {
+	return role == OrganizationRoleOwner || role == OrganizationRoleMember
+}
+
+// Organization es un tenant: agrupa sitios y especies privadas que otras
+// organizaciones no pueden ver
+type Organization struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"type:varchar(255);not null"`
+	Slug      string         `json:"slug" gorm:"type:varchar(100);not null;uniqueIndex"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Rol del usuario que consulta (solo en listados)
+	Role string `json:"role,omitempty" gorm:"->;-:migration"`
+}
+
+// TableName define el nombre de la tabla
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+// OrganizationMember es la pertenencia de un usuario a una organización
+type OrganizationMember struct {
+	OrganizationID uint      `json:"organization_id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"primaryKey;index"`
+	Role           string    `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relaciones
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName define el nombre de la tabla
+func (OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+// CreateOrganizationRequest estructura para crear una organización
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+	Slug string `json:"slug" binding:"omitempty,max=100"`
+}
+
+// AddOrganizationMemberRequest estructura para agregar un usuario registrado a la organización
+type AddOrganizationMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Orígenes de los eventos de estado
+const (
+	EventSourceAPI      = "api"      // Registrado por la API al crear o cambiar de estado
+	EventSourceBackfill = "backfill" // Reconstruido a partir de created_at/planted_at
+)
+
+// PlantInstanceEvent registra cada cambio de estado de una instancia de planta
+type PlantInstanceEvent struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	PlantInstanceID uint      `json:"plant_instance_id" gorm:"not null;index"`
+	FromStatus      string    `json:"from_status" gorm:"type:varchar(50)"` // Vacío en el evento de alta
+	ToStatus        string    `json:"to_status" gorm:"type:varchar(50);not null;index"`
+	Actor           string    `json:"actor" gorm:"type:varchar(100)"`
+	Source          string    `json:"source" gorm:"type:varchar(20);not null;default:api"`
+	Notes           string    `json:"notes" gorm:"type:text"`
+	OccurredAt      time.Time `json:"occurred_at" gorm:"not null;index"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// StatusPeriod es un intervalo en el que la instancia permaneció en un estado.
+// Until es nil para el estado actual.
+type StatusPeriod struct {
+	Status       string     `json:"status"`
+	Since        time.Time  `json:"since"`
+	Until        *time.Time `json:"until"`
+	DurationDays float64    `json:"duration_days"`
+	Actor        string     `json:"actor"`
+}
+
+// PlantInstanceTimeline es la historia de estados de una instancia de planta
+type PlantInstanceTimeline struct {
+	PlantInstanceID uint                 `json:"plant_instance_id"`
+	CurrentStatus   string               `json:"current_status"`
+	Events          []PlantInstanceEvent `json:"events"`
+	Periods         []StatusPeriod       `json:"periods"`
+	DaysInStatus    map[string]float64   `json:"days_in_status"`
+}
+
+// BuildPlantInstanceTimeline arma los períodos por estado a partir de los eventos
+// ordenados cronológicamente. El período abierto se mide hasta now.
+func BuildPlantInstanceTimeline(instance PlantInstance, events []PlantInstanceEvent, now time.Time) PlantInstanceTimeline {
+	timeline := PlantInstanceTimeline{
+		PlantInstanceID: instance.ID,
+		CurrentStatus:   instance.Status,
+		Events:          events,
+		Periods:         make([]StatusPeriod, 0, len(events)),
+		DaysInStatus:    make(map[string]float64),
+	}
+
+	for i, event := range events {
+		period := StatusPeriod{
+			Status: event.ToStatus,
+			Since:  event.OccurredAt,
+			Actor:  event.Actor,
+		}
+
+		end := now
+		if i+1 < len(events) {
+			until := events[i+1].OccurredAt
+			period.Until = &until
+			end = until
+		}
+
+		if end.After(period.Since) {
+			period.DurationDays = end.Sub(period.Since).Hours() / 24
+		}
+
+		timeline.Periods = append(timeline.Periods, period)
+		timeline.DaysInStatus[period.Status] += period.DurationDays
+	}
+
+	return timeline
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildPlantInstanceTimeline(t *testing.T) {
+	day0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n float64) time.Time { return day0.Add(time.Duration(n * 24 * float64(time.Hour))) }
+
+	cases := []struct {
+		name    string
+		events  []PlantInstanceEvent
+		now     time.Time
+		periods []StatusPeriod
+		days    map[string]float64
+	}{
+		{
+			name:    "sin eventos",
+			now:     day(5),
+			periods: []StatusPeriod{},
+			days:    map[string]float64{},
+		},
+		{
+			name:   "un solo evento queda abierto hasta now",
+			events: []PlantInstanceEvent{{ToStatus: PlantStatusPlanned, OccurredAt: day(0), Actor: "ana"}},
+			now:    day(2.5),
+			periods: []StatusPeriod{
+				{Status: PlantStatusPlanned, Since: day(0), DurationDays: 2.5, Actor: "ana"},
+			},
+			days: map[string]float64{PlantStatusPlanned: 2.5},
+		},
+		{
+			name: "períodos cerrados y abierto",
+			events: []PlantInstanceEvent{
+				{ToStatus: PlantStatusPlanned, OccurredAt: day(0)},
+				{FromStatus: PlantStatusPlanned, ToStatus: PlantStatusGerminated, OccurredAt: day(3)},
+				{FromStatus: PlantStatusGerminated, ToStatus: PlantStatusPlanted, OccurredAt: day(10), Actor: "luis"},
+			},
+			now: day(12),
+			periods: []StatusPeriod{
+				{Status: PlantStatusPlanned, Since: day(0), Until: timePtr(day(3)), DurationDays: 3},
+				{Status: PlantStatusGerminated, Since: day(3), Until: timePtr(day(10)), DurationDays: 7},
+				{Status: PlantStatusPlanted, Since: day(10), DurationDays: 2, Actor: "luis"},
+			},
+			days: map[string]float64{PlantStatusPlanned: 3, PlantStatusGerminated: 7, PlantStatusPlanted: 2},
+		},
+		{
+			name: "un estado repetido suma sus períodos",
+			events: []PlantInstanceEvent{
+				{ToStatus: PlantStatusEstablished, OccurredAt: day(0)},
+				{ToStatus: PlantStatusDormant, OccurredAt: day(4)},
+				{ToStatus: PlantStatusEstablished, OccurredAt: day(5)},
+			},
+			now: day(6),
+			periods: []StatusPeriod{
+				{Status: PlantStatusEstablished, Since: day(0), Until: timePtr(day(4)), DurationDays: 4},
+				{Status: PlantStatusDormant, Since: day(4), Until: timePtr(day(5)), DurationDays: 1},
+				{Status: PlantStatusEstablished, Since: day(5), DurationDays: 1},
+			},
+			days: map[string]float64{PlantStatusEstablished: 5, PlantStatusDormant: 1},
+		},
+		{
+			name:   "un evento futuro no tiene duración negativa",
+			events: []PlantInstanceEvent{{ToStatus: PlantStatusPlanned, OccurredAt: day(3)}},
+			now:    day(1),
+			periods: []StatusPeriod{
+				{Status: PlantStatusPlanned, Since: day(3)},
+			},
+			days: map[string]float64{PlantStatusPlanned: 0},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			instance := PlantInstance{ID: 7, Status: PlantStatusPlanted}
+			timeline := BuildPlantInstanceTimeline(instance, tc.events, tc.now)
+
+			if timeline.PlantInstanceID != 7 || timeline.CurrentStatus != PlantStatusPlanted {
+				t.Errorf("timeline = %+v, se esperaba la instancia 7 en planted", timeline)
+			}
+			if len(timeline.Periods) != len(tc.periods) {
+				t.Fatalf("periods = %+v, se esperaban %d", timeline.Periods, len(tc.periods))
+			}
+			for i, want := range tc.periods {
+				got := timeline.Periods[i]
+				if got.Status != want.Status || !got.Since.Equal(want.Since) || got.Actor != want.Actor ||
+					!sameTime(got.Until, want.Until) || math.Abs(got.DurationDays-want.DurationDays) > 1e-9 {
+					t.Errorf("period[%d] = %+v, se esperaba %+v", i, got, want)
+				}
+			}
+			if len(timeline.DaysInStatus) != len(tc.days) {
+				t.Errorf("days_in_status = %v, se esperaba %v", timeline.DaysInStatus, tc.days)
+			}
+			for status, want := range tc.days {
+				if got := timeline.DaysInStatus[status]; math.Abs(got-want) > 1e-9 {
+					t.Errorf("days_in_status[%s] = %v, se esperaba %v", status, got, want)
+				}
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanTransitionPlantStatus(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     bool
+	}{
+		{PlantStatusPlanned, PlantStatusGerminated, true},
+		{PlantStatusGerminated, PlantStatusPlanted, true},
+		{PlantStatusPlanted, PlantStatusEstablished, true},
+		{PlantStatusEstablished, PlantStatusProductive, true},
+		{PlantStatusDormant, PlantStatusEstablished, true},
+		{PlantStatusProductive, PlantStatusDead, true},
+		{PlantStatusPlanned, PlantStatusProductive, false},
+		{PlantStatusPlanted, PlantStatusPlanned, false},
+		{PlantStatusDead, PlantStatusPlanned, false},
+		{PlantStatusPlanted, PlantStatusPlanted, false},
+		{"desconocido", PlantStatusPlanned, false},
+	}
+
+	for _, tc := range cases {
+		if got := CanTransitionPlantStatus(tc.from, tc.to); got != tc.want {
+			t.Errorf("CanTransitionPlantStatus(%q, %q) = %t, se esperaba %t", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestInitialAndPlantedStatuses(t *testing.T) {
+	cases := []struct {
+		status           string
+		initial, planted bool
+	}{
+		{PlantStatusPlanned, true, false},
+		{PlantStatusGerminated, true, false},
+		{PlantStatusPlanted, true, true},
+		{PlantStatusEstablished, false, true},
+		{PlantStatusProductive, false, true},
+		{PlantStatusDormant, false, false},
+		{PlantStatusDead, false, false},
+	}
+
+	for _, tc := range cases {
+		if got := IsValidInitialPlantStatus(tc.status); got != tc.initial {
+			t.Errorf("IsValidInitialPlantStatus(%q) = %t, se esperaba %t", tc.status, got, tc.initial)
+		}
+		if got := IsPlantedStatus(tc.status); got != tc.planted {
+			t.Errorf("IsPlantedStatus(%q) = %t, se esperaba %t", tc.status, got, tc.planted)
+		}
+	}
+}
+
+func TestPlantInstanceTransitionTo(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := start.Add(48 * time.Hour)
+
+	cases := []struct {
+		name        string
+		instance    PlantInstance
+		to          string
+		at          time.Time
+		wantErr     error
+		wantPlanted *time.Time
+	}{
+		{
+			name:     "transición legal",
+			instance: PlantInstance{Status: PlantStatusPlanned, StatusChangedAt: &start},
+			to:       PlantStatusGerminated,
+			at:       later,
+		},
+		{
+			name:        "al plantar se fija planted_at",
+			instance:    PlantInstance{Status: PlantStatusGerminated, StatusChangedAt: &start},
+			to:          PlantStatusPlanted,
+			at:          later,
+			wantPlanted: &later,
+		},
+		{
+			name:        "de dormant a established también fija planted_at",
+			instance:    PlantInstance{Status: PlantStatusDormant, StatusChangedAt: &start},
+			to:          PlantStatusEstablished,
+			at:          later,
+			wantPlanted: &later,
+		},
+		{
+			name:        "no pisa un planted_at existente",
+			instance:    PlantInstance{Status: PlantStatusPlanted, StatusChangedAt: &start, PlantedAt: &start},
+			to:          PlantStatusEstablished,
+			at:          later,
+			wantPlanted: &start,
+		},
+		{
+			name:     "transición ilegal",
+			instance: PlantInstance{Status: PlantStatusPlanned, StatusChangedAt: &start},
+			to:       PlantStatusProductive,
+			at:       later,
+			wantErr:  ErrInvalidStatusTransition,
+		},
+		{
+			name:     "desde un estado terminal",
+			instance: PlantInstance{Status: PlantStatusDead, StatusChangedAt: &start},
+			to:       PlantStatusPlanned,
+			at:       later,
+			wantErr:  ErrInvalidStatusTransition,
+		},
+		{
+			name:     "fecha anterior al último cambio",
+			instance: PlantInstance{Status: PlantStatusPlanned, StatusChangedAt: &later},
+			to:       PlantStatusGerminated,
+			at:       start,
+			wantErr:  ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			instance := tc.instance
+			before := instance.Status
+
+			err := instance.TransitionTo(tc.to, "ana", tc.at)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, se esperaba %v", err, tc.wantErr)
+				}
+				if instance.Status != before {
+					t.Errorf("el estado cambió a %q pese al error", instance.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TransitionTo: %v", err)
+			}
+
+			if instance.Status != tc.to || instance.StatusChangedBy != "ana" || !instance.StatusChangedAt.Equal(tc.at) {
+				t.Errorf("instancia = %+v, se esperaba estado %q registrado por ana en %v", instance, tc.to, tc.at)
+			}
+			switch {
+			case tc.wantPlanted == nil && instance.PlantedAt != nil:
+				t.Errorf("planted_at = %v, se esperaba vacío", instance.PlantedAt)
+			case tc.wantPlanted != nil && (instance.PlantedAt == nil || !instance.PlantedAt.Equal(*tc.wantPlanted)):
+				t.Errorf("planted_at = %v, se esperaba %v", instance.PlantedAt, tc.wantPlanted)
+			}
+		})
+	}
+}
+
+func TestPlantInstanceTransitionToInvalidStatus(t *testing.T) {
+	instance := PlantInstance{Status: PlantStatusPlanned}
+	if err := instance.TransitionTo("florecida", "ana", time.Now()); err == nil {
+		t.Fatal("se esperaba error para un estado desconocido")
+	}
+}
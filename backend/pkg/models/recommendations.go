@@ -0,0 +1,29 @@
+package models
+
+// PlotGaps resume qué estratos, etapas sucesionales y funciones ecológicas
+// faltan entre las plantas vivas de una parcela
+type PlotGaps struct {
+	MissingStrata     []string `json:"missing_strata"`
+	MissingSuccession []string `json:"missing_succession"`
+	MissingFunctions  []string `json:"missing_functions"`
+}
+
+// IsEmpty indica si la parcela no tiene huecos que cubrir
+func (g PlotGaps) IsEmpty() bool {
+	return len(g.MissingStrata) == 0 && len(g.MissingSuccession) == 0 && len(g.MissingFunctions) == 0
+}
+
+// SpeciesRecommendation es una especie del catálogo sugerida para una parcela
+type SpeciesRecommendation struct {
+	Species PlantSpecies `json:"species"`
+	Score   int          `json:"score"`
+	Fills   []string     `json:"fills"`   // Huecos que cubre, p. ej. "estrato:alto"
+	Reasons []string     `json:"reasons"` // Explicación legible de cada hueco cubierto
+}
+
+// PlotRecommendations es la respuesta de GET /plots/:id/recommendations
+type PlotRecommendations struct {
+	PlotID          uint                    `json:"plot_id"`
+	Gaps            PlotGaps                `json:"gaps"`
+	Recommendations []SpeciesRecommendation `json:"recommendations"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+)
+
+// Roles de un usuario dentro de un sitio (finca)
+const (
+	SiteRoleOwner       = "owner"        // Administra el sitio y sus miembros
+	SiteRoleDesigner    = "designer"     // Diseña plantaciones, parcelas y plantillas
+	SiteRoleFieldWorker = "field_worker" // Registra el trabajo de campo sobre las plantas
+	SiteRoleViewer      = "viewer"       // Solo lectura
+)
+
+// Permisos que se verifican sobre un sitio y sus descendientes
+const (
+	SitePermView    = "view"    // Ver el sitio, sus plantaciones, parcelas e instancias
+	SitePermField   = "field"   // Actualizar instancias y cambiar su estado
+	SitePermDesign  = "design"  // Crear, modificar y eliminar plantaciones, parcelas, instancias y plantillas
+	SitePermManage  = "manage"  // Modificar o eliminar el sitio
+	SitePermMembers = "members" // Invitar, cambiar el rol y quitar miembros
+)
+
+// sitePermissions: rol → permisos
+var sitePermissions = map[string][]string{
+	SiteRoleOwner:       {SitePermView, SitePermField, SitePermDesign, SitePermManage, SitePermMembers},
+	SiteRoleDesigner:    {SitePermView, SitePermField, SitePermDesign},
+	SiteRoleFieldWorker: {SitePermView, SitePermField},
+	SiteRoleViewer:      {SitePermView},
+}
+
+// GetSiteRoles devuelve los roles de sitio válidos
+func GetSiteRoles() []string {
+	return []string{SiteRoleOwner, SiteRoleDesigner, SiteRoleFieldWorker, SiteRoleViewer}
+}
+
+// IsValidSiteRole verifica si un rol de sitio es válido
+func IsValidSiteRole(role string) bool {
+	_, ok := sitePermissions[role]
+	return ok
+}
+
+// SiteRoleAllows indica si el rol tiene el permiso
+func SiteRoleAllows(role, permission string) bool {
+	for _, p := range sitePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// SiteMember es la pertenencia de un usuario a un sitio con un rol
+type SiteMember struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	SiteID    uint      `json:"site_id" gorm:"not null;uniqueIndex:idx_site_members_site_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_site_members_site_user;index"`
+	Role      string    `json:"role" gorm:"type:varchar(20);not null"`
+	InvitedBy *uint     `json:"invited_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relaciones
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName define el nombre de la tabla
+func (SiteMember) TableName() string {
+	return "site_members"
+}
+
+// InviteSiteMemberRequest estructura para agregar un usuario registrado a un sitio
+type InviteSiteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// UpdateSiteMemberRequest estructura para cambiar el rol de un miembro
+type UpdateSiteMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
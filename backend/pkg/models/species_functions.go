@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deibys/sintronia/pkg/apperror"
+)
+
+// SpeciesFunction asocia una especie con una de sus funciones ecológicas.
+// Una especie puede cumplir varias funciones y como máximo una es la principal.
+type SpeciesFunction struct {
+	SpeciesID uint      `json:"-" gorm:"primaryKey"`
+	Function  string    `json:"function" gorm:"primaryKey;type:varchar(100)"`
+	IsPrimary bool      `json:"is_primary" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// TableName define el nombre de la tabla
+func (SpeciesFunction) TableName() string {
+	return "species_functions"
+}
+
+// SetFunctions reemplaza las funciones de la especie. Si primary está vacío la
+// primera función pasa a ser la principal. FunctionEcol se mantiene con la
+// función principal por compatibilidad.
+func (ps *PlantSpecies) SetFunctions(functions []string, primary string) {
+	if primary == "" && len(functions) > 0 {
+		primary = functions[0]
+	}
+
+	ps.Functions = make([]SpeciesFunction, 0, len(functions))
+	for _, function := range functions {
+		ps.Functions = append(ps.Functions, SpeciesFunction{
+			SpeciesID: ps.ID,
+			Function:  function,
+			IsPrimary: function == primary,
+		})
+	}
+	ps.FunctionEcol = primary
+}
+
+// FunctionNames devuelve las funciones ecológicas de la especie. Si las funciones
+// no se cargaron se usa la función principal.
+func (ps *PlantSpecies) FunctionNames() []string {
+	if len(ps.Functions) == 0 {
+		if ps.FunctionEcol != "" {
+			return []string{ps.FunctionEcol}
+		}
+		return nil
+	}
+
+	names := make([]string, 0, len(ps.Functions))
+	for _, f := range ps.Functions {
+		names = append(names, f.Function)
+	}
+	return names
+}
+
+// validateFunctions verifica que las funciones sean válidas, no se repitan,
+// que haya como máximo una principal y que coincida con FunctionEcol
+func (ps *PlantSpecies) validateFunctions() error {
+	if ps.FunctionEcol != "" && !IsValidFunction(ps.FunctionEcol) {
+		return apperror.Invalid("function_ecol", apperror.FieldInvalid, "función ecológica inválida")
+	}
+
+	seen := make(map[string]bool, len(ps.Functions))
+	primary := ""
+	for _, f := range ps.Functions {
+		if !IsValidFunction(f.Function) {
+			return apperror.Invalid("functions", apperror.FieldInvalid, fmt.Sprintf("función ecológica inválida: %q", f.Function))
+		}
+		if seen[f.Function] {
+			return apperror.Invalid("functions", apperror.FieldDuplicate, fmt.Sprintf("función ecológica repetida: %q", f.Function))
+		}
+		seen[f.Function] = true
+
+		if f.IsPrimary {
+			if primary != "" {
+				return apperror.Invalid("functions", apperror.FieldInvalid, "solo puede haber una función ecológica principal")
+			}
+			primary = f.Function
+		}
+	}
+
+	if len(ps.Functions) > 0 && primary != ps.FunctionEcol {
+		return apperror.Invalid("function_ecol", apperror.FieldInvalid, "la función principal debe estar entre las funciones de la especie")
+	}
+
+	return nil
+}
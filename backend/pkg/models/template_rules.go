@@ -0,0 +1,121 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deibys/sintronia/pkg/apperror"
+)
+
+// Claves de las reglas reconocidas en SuggestionTemplate.Rules
+const (
+	RuleMaxDensity     = "densidad_maxima"     // Plantas vivas por m² como máximo en cada parcela
+	RuleRequiredStrata = "estratos_requeridos" // Estratos que toda parcela debe ocupar
+	RuleMinSuccession  = "sucesion_minima"     // Etapas sucesionales presentes en cada parcela
+)
+
+// TemplateRules es el esquema tipado de las reglas JSONB de una plantilla
+type TemplateRules struct {
+	MaxDensity     *float64 `json:"densidad_maxima,omitempty"`
+	RequiredStrata []string `json:"estratos_requeridos,omitempty"`
+	MinSuccession  []string `json:"sucesion_minima,omitempty"`
+}
+
+// ParseTemplateRules decodifica y valida las reglas de una plantilla.
+// Una cadena vacía equivale a una plantilla sin reglas.
+func ParseTemplateRules(raw string) (*TemplateRules, error) {
+	rules := &TemplateRules{}
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "null" {
+		return rules, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(rules); err != nil {
+		return nil, apperror.Invalid("rules", apperror.FieldInvalid, "reglas inválidas: "+err.Error())
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Validate valida los valores de cada regla
+func (r *TemplateRules) Validate() error {
+	if r.MaxDensity != nil && *r.MaxDensity <= 0 {
+		return apperror.Invalid("rules."+RuleMaxDensity, apperror.FieldOutOfRange, fmt.Sprintf("%s debe ser mayor a cero", RuleMaxDensity))
+	}
+
+	if err := validateRuleValues(RuleRequiredStrata, r.RequiredStrata, IsValidStratum); err != nil {
+		return err
+	}
+
+	return validateRuleValues(RuleMinSuccession, r.MinSuccession, IsValidSuccessionStage)
+}
+
+// IsEmpty indica si la plantilla no define ninguna regla
+func (r *TemplateRules) IsEmpty() bool {
+	return r.MaxDensity == nil && len(r.RequiredStrata) == 0 && len(r.MinSuccession) == 0
+}
+
+// JSON serializa las reglas en su forma canónica para guardarlas
+func (r *TemplateRules) JSON() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+func validateRuleValues(rule string, values []string, isValid func(string) bool) error {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if !isValid(v) {
+			return apperror.Invalid("rules."+rule, apperror.FieldInvalid, fmt.Sprintf("%s: valor inválido %q", rule, v))
+		}
+		if seen[v] {
+			return apperror.Invalid("rules."+rule, apperror.FieldDuplicate, fmt.Sprintf("%s: valor repetido %q", rule, v))
+		}
+		seen[v] = true
+	}
+	return nil
+}
+
+// ErrInvalidTemplateRules se devuelve cuando las reglas guardadas no se pueden evaluar
+var ErrInvalidTemplateRules = apperror.Conflict("invalid_template_rules", "la plantilla tiene reglas inválidas")
+
+// ComplianceReport es el resultado de evaluar una plantación contra una plantilla
+type ComplianceReport struct {
+	TemplateID     uint         `json:"template_id"`
+	TemplateName   string       `json:"template_name"`
+	PlantationID   uint         `json:"plantation_id"`
+	Compliant      bool         `json:"compliant"`
+	PlotsEvaluated int          `json:"plots_evaluated"`
+	Rules          []RuleResult `json:"rules"`
+	EvaluatedAt    time.Time    `json:"evaluated_at"`
+}
+
+// RuleResult indica si una regla se cumple y qué parcelas la incumplen
+type RuleResult struct {
+	Rule           string          `json:"rule"`
+	Expected       interface{}     `json:"expected"`
+	Passed         bool            `json:"passed"`
+	Message        string          `json:"message"`
+	OffendingPlots []PlotViolation `json:"offending_plots"`
+}
+
+// PlotViolation describe por qué una parcela incumple una regla
+type PlotViolation struct {
+	PlotID   uint        `json:"plot_id"`
+	PlotType string      `json:"plot_type"`
+	Actual   interface{} `json:"actual"`            // Densidad medida o valores presentes
+	Missing  []string    `json:"missing,omitempty"` // Valores requeridos ausentes
+	Message  string      `json:"message"`
+}
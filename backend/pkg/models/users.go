@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Roles globales de usuario
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
+// IsValidUserRole verifica si un rol de usuario es válido
+func IsValidUserRole(role string) bool {
+	return role == UserRoleUser || role == UserRoleAdmin
+}
+
+// User representa una cuenta de usuario del sistema
+type User struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	Email        string         `json:"email" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Name         string         `json:"name" gorm:"type:varchar(255)"`
+	PasswordHash string         `json:"-" gorm:"type:varchar(255);not null"`
+	Role         string         `json:"role" gorm:"type:varchar(20);not null;default:user"`
+	IsActive     bool           `json:"is_active" gorm:"not null;default:true"`
+	LastLoginAt  *time.Time     `json:"last_login_at,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// TableName define el nombre de la tabla
+func (User) TableName() string {
+	return "users"
+}
+
+// RefreshToken es un token de renovación emitido a un usuario. Solo se guarda el
+// hash SHA-256 del token; cada uso lo rota por uno nuevo (ReplacedByID).
+type RefreshToken struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash    string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *uint      `json:"replaced_by_id,omitempty"`
+	UserAgent    string     `json:"user_agent" gorm:"type:varchar(255)"`
+	IPAddress    string     `json:"ip_address" gorm:"type:varchar(64)"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName define el nombre de la tabla
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// IsActive indica si el token se puede usar para renovar la sesión
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// RegisterRequest estructura para registrar un usuario
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Name     string `json:"name"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// LoginRequest estructura para iniciar sesión
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest estructura para renovar el token de acceso
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest estructura para cerrar sesión. Con AllSessions se revocan todos
+// los tokens de renovación del usuario.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	AllSessions  bool   `json:"all_sessions"`
+}
+
+// TokenResponse es la respuesta de login, registro y renovación
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // Segundos de validez del token de acceso
+	User         *User  `json:"user"`
+}